@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/ebfe/scard"
 	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
+	"github.com/robertocorreajr/cfs_spool/internal/queue"
 	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	"github.com/robertocorreajr/cfs_spool/internal/spoolman"
+	"github.com/robertocorreajr/cfs_spool/internal/tagformat"
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -19,21 +26,73 @@ type App struct {
 	ctx       context.Context
 	stopWatch chan struct{}
 	watchDone chan struct{}
+	rescan    chan struct{}
 	lastUID   string
+
+	sessionMu sync.Mutex
+	session   *rfid.Session // dona do leitor enquanto o watcher roda
+
+	detectBeepSet atomic.Bool // bipe de detecção já aplicado ao leitor
+
+	opMu  sync.Mutex
+	opSeq int
+	ops   map[int]context.CancelFunc // operações em andamento (CancelOperation)
+
+	txMu    sync.Mutex
+	pending *pendingWrite // gravação interrompida pela remoção da tag
+
+	queueMu     sync.Mutex
+	queue       *queue.Queue // fila de produção (nil = sem fila)
+	queueArmed  bool
+	queueReport string
+
+	logLevel slog.LevelVar
+	logFile  *logging.RotatingFile
+
+	cfgMu   sync.Mutex
+	cfg     Config
+	cfgPath string
+
+	formats *tagformat.Registry // formatos de tag, em ordem de detecção
+
+	spoolmanMu   sync.Mutex
+	spoolman     *spoolman.Syncer // nil = Spoolman desligado ou ainda não usado
+	spoolmanJobs chan func()      // Spoolman e Moonraker, em ordem (ver spoolmanWorker)
 }
 
 // NewApp cria uma nova instância da aplicação
 func NewApp() *App {
-	return &App{}
+	a := &App{rescan: make(chan struct{}, 1), spoolmanJobs: make(chan func(), 32)}
+	a.formats = newFormats(a)
+	return a
 }
 
 // startup é chamado quando a aplicação inicia
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	if path, err := configPath(); err == nil {
+		a.cfgPath = path
+		if cfg, err := loadConfig(path); err == nil {
+			a.cfg = cfg
+		}
+	}
+	a.setupLogging()
+	go a.spoolmanWorker()
 	a.StartTagWatcher()
 }
 
-// StartTagWatcher inicia watcher event-driven do leitor RFID (PC/SC SCardGetStatusChange)
+// shutdown é chamado ao fechar a aplicação
+func (a *App) shutdown(ctx context.Context) {
+	a.StopTagWatcher()
+	slog.Info("CFS Spool encerrado")
+	if a.logFile != nil {
+		a.logFile.Close()
+	}
+}
+
+// StartTagWatcher inicia a sessão com o leitor selecionado e o watcher que
+// reage à presença da tag. A sessão é a única dona do leitor: ReadTag,
+// WriteTag e as demais operações pegam o leitor emprestado dela (openReader).
 func (a *App) StartTagWatcher() {
 	if a.stopWatch != nil {
 		return
@@ -44,7 +103,7 @@ func (a *App) StartTagWatcher() {
 }
 
 // StopTagWatcher bloqueia até a goroutine do watcher encerrar completamente —
-// garante que nenhuma operação PC/SC do watcher esteja em voo antes de retornar.
+// a sessão é fechada depois que a operação em andamento devolve o leitor.
 func (a *App) StopTagWatcher() {
 	if a.stopWatch == nil {
 		return
@@ -56,6 +115,7 @@ func (a *App) StopTagWatcher() {
 	a.lastUID = ""
 }
 
+// tagWatchLoop abre a sessão com o leitor e a reabre quando ele volta
 func (a *App) tagWatchLoop() {
 	defer close(a.watchDone)
 
@@ -70,17 +130,9 @@ func (a *App) tagWatchLoop() {
 		default:
 		}
 
-		ctx, err := scard.EstablishContext()
+		// Segue o leitor escolhido pelo usuário; sem fallback para outro leitor
+		dev, err := rfid.OpenDevice(a.selectedReader())
 		if err != nil {
-			if a.waitOrStop(2 * time.Second) {
-				return
-			}
-			continue
-		}
-
-		readers, err := ctx.ListReaders()
-		if err != nil || len(readers) == 0 {
-			ctx.Release()
 			wailsRuntime.EventsEmit(a.ctx, "tag:status", "no_reader")
 			if a.waitOrStop(2 * time.Second) {
 				return
@@ -88,8 +140,15 @@ func (a *App) tagWatchLoop() {
 			continue
 		}
 
-		a.watchReader(ctx, readers[0])
-		ctx.Release()
+		session := rfid.NewSession(dev)
+		a.detectBeepSet.Store(false)
+		a.setSession(session)
+		stopped := a.watchSession(session)
+		a.setSession(nil)
+		session.Close()
+		if stopped {
+			return
+		}
 	}
 }
 
@@ -103,54 +162,94 @@ func (a *App) waitOrStop(dur time.Duration) bool {
 	}
 }
 
-// watchReader bloqueia em SCardGetStatusChange reagindo a inserção/remoção.
-// Retorna quando stopWatch fecha, quando o leitor é desconectado, ou em erro PC/SC.
-func (a *App) watchReader(ctx *scard.Context, reader string) {
-	states := []scard.ReaderState{{
-		Reader:       reader,
-		CurrentState: scard.StateUnaware,
-	}}
-
-	// Goroutine auxiliar: Cancel() desbloqueia GetStatusChange quando stopWatch fecha.
-	done := make(chan struct{})
-	defer close(done)
-	go func() {
+// watchSession reage a inserção/remoção da tag até o watcher ser parado
+// (retorna true) ou o leitor ser desconectado (emitindo "reader:removed").
+func (a *App) watchSession(session *rfid.Session) bool {
+	for {
 		select {
 		case <-a.stopWatch:
-			_ = ctx.Cancel()
-		case <-done:
+			return true
+		case <-a.rescan:
+			// Tag regravada: reler mesmo com o UID igual, sem novo sinal
+			a.lastUID = ""
+			a.handleTagPresent(false)
+		case present, ok := <-session.Events():
+			switch {
+			case !ok:
+				a.handleReaderRemoved(a.selectedReader())
+				return false
+			case present:
+				a.handleTagPresent(true)
+			default:
+				a.handleTagRemoved()
+			}
 		}
-	}()
+	}
+}
 
-	for {
-		if err := ctx.GetStatusChange(states, -1); err != nil {
-			return
-		}
+// setSession publica a sessão usada por openReader (nil = sem leitor)
+func (a *App) setSession(s *rfid.Session) {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+	a.session = s
+}
 
-		present := states[0].EventState&scard.StatePresent != 0
-		wasPresent := states[0].CurrentState&scard.StatePresent != 0
-		states[0].CurrentState = states[0].EventState
+// rescanTag pede ao watcher para reler a tag depois de uma gravação
+func (a *App) rescanTag() {
+	select {
+	case a.rescan <- struct{}{}:
+	default:
+	}
+}
 
-		switch {
-		case present && !wasPresent:
-			a.handleTagPresent()
-		case !present && wasPresent:
-			a.handleTagRemoved()
+// handleTagPresent lê a tag; detected indica tag recém-colocada (false na
+// releitura após uma gravação). Na detecção o LED do leitor sinaliza o
+// resultado e, com a fila de produção armada, a tag recebe antes a próxima
+// entrada da fila.
+func (a *App) handleTagPresent(detected bool) {
+	// Gravação interrompida: concluir/desfazer antes de ler a tag
+	if a.GetPendingWrite() != nil {
+		// Divergência conclui a gravação e já vai no write:resumed: segue a leitura
+		if v, err := a.resolvePendingWrite(); err != nil && (v == nil || v.OK()) {
+			if !errors.Is(err, rfid.ErrCardRemoved) && !errors.Is(err, rfid.ErrWrongTag) {
+				wailsRuntime.EventsEmit(a.ctx, "tag:error", err.Error())
+			}
+			return
 		}
 	}
-}
 
-func (a *App) handleTagPresent() {
-	data, err := a.ReadTag()
+	signal := detected
+	if q := a.armedQueue(); q != nil && detected {
+		// writeQueued já sinalizou o resultado no leitor
+		a.writeQueued(q)
+		signal = false
+	}
+
+	data, spool, err := a.readTag()
 	if err != nil {
+		// Tag retirada antes da leitura terminar não é erro para o usuário
+		if !errors.Is(err, rfid.ErrCardRemoved) {
+			wailsRuntime.EventsEmit(a.ctx, "tag:status", "error")
+			wailsRuntime.EventsEmit(a.ctx, "tag:error", err.Error())
+			if signal {
+				a.signalTag(rfid.SignalError)
+			}
+		}
 		return
 	}
 	if data.UID == a.lastUID {
 		return
 	}
+	slog.Debug("tag detectada", "uid", data.UID, "blank", data.IsBlank)
 	a.lastUID = data.UID
 	wailsRuntime.EventsEmit(a.ctx, "tag:status", "read")
 	wailsRuntime.EventsEmit(a.ctx, "tag:read", data)
+	if signal {
+		a.signalTag(rfid.SignalRead)
+	}
+	if spool != nil {
+		a.enqueueSpoolman(func() { a.tagSpoolEvent(data.UID, spool) })
+	}
 }
 
 func (a *App) handleTagRemoved() {
@@ -158,6 +257,20 @@ func (a *App) handleTagRemoved() {
 	wailsRuntime.EventsEmit(a.ctx, "tag:status", "waiting")
 }
 
+func (a *App) handleReaderRemoved(reader string) {
+	slog.Info("leitor desconectado", "reader", reader)
+	a.lastUID = ""
+	wailsRuntime.EventsEmit(a.ctx, "reader:removed", reader)
+	wailsRuntime.EventsEmit(a.ctx, "tag:status", "no_reader")
+}
+
+// selectedReader retorna o leitor configurado ("" = automático)
+func (a *App) selectedReader() string {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+	return a.cfg.Reader
+}
+
 // GetVersion retorna a versão da aplicação
 func (a *App) GetVersion() string {
 	return version
@@ -168,6 +281,7 @@ func (a *App) GetVersion() string {
 // TagData dados lidos de uma tag RFID
 type TagData struct {
 	UID          string `json:"uid"`
+	Format       string `json:"format"`        // "cfs" ou "bambu" (MIFARE Classic), "openspool", "opentag3d", "tigertag" ou "ace" (NTAG21x)
 	Date         string `json:"date"`         // YYYY-MM-DD para input date
 	DateDisplay  string `json:"dateDisplay"`   // formato legível pt-BR
 	SupplierCode string `json:"supplierCode"`  // código do vendor UI ("0276", "ESUN", "POLY", "0000")
@@ -179,6 +293,8 @@ type TagData struct {
 	LengthDisplay string `json:"lengthDisplay"` // "330cm (1kg)"
 	Serial       string `json:"serial"`        // "000001"
 	IsBlank      bool   `json:"isBlank"`       // true se tag virgem
+	MinTemp      int    `json:"minTemp,omitempty"` // °C do bico (só OpenSpool)
+	MaxTemp      int    `json:"maxTemp,omitempty"`
 }
 
 // WriteRequest dados enviados pelo frontend para gravação
@@ -189,170 +305,250 @@ type WriteRequest struct {
 	Color    string `json:"color"`    // 6 chars hex (sem # ou prefixo 0)
 	Length   string `json:"length"`   // código 4 chars ou gramas
 	Serial   string `json:"serial"`   // até 6 dígitos
+	MinTemp  int    `json:"minTemp"`  // °C do bico em tags OpenSpool (0 = padrão do material)
+	MaxTemp  int    `json:"maxTemp"`
+	Format   string `json:"format,omitempty"` // destino: "cfs" (padrão, segue o tipo da tag) ou um formato gravável de GetTagFormats
+
+	SpoolmanID int `json:"spoolmanId,omitempty"` // bobina do Spoolman que recebe o UID da tag gravada (0 = nenhuma)
 }
 
 // --- Métodos expostos via Wails bindings ---
 
+// ListReaders lista os leitores PC/SC conectados e as portas PN532 (UART)
+func (a *App) ListReaders() ([]string, error) {
+	readers, err := rfid.ListReaders()
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao listar leitores: %v", err)
+	}
+	return readers, nil
+}
+
+// GetSelectedReader retorna o leitor escolhido ("" = automático)
+func (a *App) GetSelectedReader() string {
+	return a.selectedReader()
+}
+
+// SelectReader escolhe o leitor usado pelo watcher, ReadTag e WriteTag
+// e persiste a escolha. Nome vazio volta para seleção automática.
+func (a *App) SelectReader(name string) error {
+	a.StopTagWatcher()
+	defer a.StartTagWatcher()
+
+	a.cfgMu.Lock()
+	a.cfg.Reader = name
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+
+	if a.cfgPath == "" {
+		return nil
+	}
+	if err := saveConfig(a.cfgPath, cfg); err != nil {
+		return fmt.Errorf("Erro ao salvar configuração: %v", err)
+	}
+	return nil
+}
+
 // ReadTag lê uma tag RFID e retorna os dados decodificados
 func (a *App) ReadTag() (*TagData, error) {
+	data, _, err := a.readTag()
+	return data, err
+}
+
+// readTag lê a tag presente; spool traz o modelo comum do formato (nil em tag virgem)
+func (a *App) readTag() (*TagData, *tagformat.Spool, error) {
 	// Abrir leitor RFID
-	reader, err := rfid.Open()
+	ctx, cancel := context.WithTimeout(context.Background(), readTimeout)
+	defer cancel()
+	reader, err := a.openReader(ctx, "read")
 	if err != nil {
-		return nil, fmt.Errorf("Erro ao conectar leitor: %v", err)
+		return nil, nil, wrapReaderError("Erro ao conectar leitor", err)
 	}
 	defer reader.Close()
 
-	// Obter UID
-	uid, err := reader.UID()
+	// Identificar a tag: o formato vem do registro (CFS, Bambu, OpenSpool, ...)
+	card, err := reader.Identify()
 	if err != nil {
-		return nil, fmt.Errorf("Erro ao ler UID: %v", err)
+		return nil, nil, wrapReaderError("Erro ao ler UID", err)
 	}
-
-	// Ler blocos 4, 5, 6
-	var blocks []string
-	for block := byte(4); block <= 6; block++ {
-		// Tentar chave padrão primeiro (tags novas)
-		data, err := reader.TryReadBlock(block, rfid.KeyTypeA, "FFFFFFFFFFFF")
-		if err != nil {
-			// Se falhar, tentar chave derivada do UID (tags usadas)
-			derivedKey := reader.DeriveKeyFromUID(uid)
-			data, err = reader.TryReadBlock(block, rfid.KeyTypeA, derivedKey)
-			if err != nil {
-				return nil, fmt.Errorf("Erro ao ler bloco %d: %v", block, err)
-			}
-		}
-		blocks = append(blocks, data)
+	if err := checkCard(card); err != nil {
+		return nil, nil, wrapReaderError("Tag recusada", err)
 	}
+	return a.readFormat(reader, card)
+}
 
-	// Descriptografar dados
-	decrypted, err := creality.DecryptBlocks(strings.Join(blocks, ""))
+// WriteTag grava dados em uma tag RFID no formato de req.Format (sem ele,
+// CFS em MIFARE Classic e OpenSpool em NTAG) e relê a tag para confirmar.
+// Campos divergentes na releitura retornam a verificação com um erro que
+// envolve rfid.ErrVerifyFailed; a UI recebe os campos pelo write:mismatch.
+func (a *App) WriteTag(req WriteRequest) (*rfid.WriteVerification, error) {
+	// Releitura pelo watcher mostra os dados gravados
+	defer a.rescanTag()
+
+	fields, err := a.buildFields(req)
 	if err != nil {
-		return nil, fmt.Errorf("Erro na descriptografia: %v", err)
+		return nil, err
 	}
-
-	// Parsear campos
-	fields, err := creality.ParseFieldsCompat(decrypted)
+	target, err := a.writeTarget(req.Format)
 	if err != nil {
-		return nil, fmt.Errorf("Erro ao parsear dados: %v", err)
-	}
-
-	// Tag virgem: retornar dados padrão com o UID
-	if fields.IsBlankTag() {
-		today := time.Now().Format("2006-01-02")
-		return &TagData{
-			UID:          uid,
-			Date:         today,
-			SupplierCode: "0276",
-			SupplierName: "Creality",
-			MaterialCode: "",
-			MaterialName: "",
-			Color:        "000000",
-			LengthCode:   "0330",
-			LengthDisplay: "330cm (1kg)",
-			Serial:       "000001",
-			IsBlank:      true,
-		}, nil
-	}
-
-	// Extrair cor sem prefixo "0"
-	color := ""
-	if len(fields.Color) == 7 && fields.Color[0] == '0' {
-		color = strings.ToUpper(fields.Color[1:])
-	}
-
-	// Determinar vendor UI a partir do código do material (não do supplier RFID)
-	vendorCode := materialToVendor(fields.Material)
-
-	return &TagData{
-		UID:          uid,
-		Date:         parseDateToISO(fields.Date),
-		DateDisplay:  fields.FormatDate(),
-		SupplierCode: vendorCode,
-		SupplierName: vendorName(vendorCode),
-		MaterialCode: fields.Material,
-		MaterialName: fields.GetMaterialName(),
-		Color:        color,
-		LengthCode:   fields.Length,
-		LengthDisplay: fields.FormatLength(),
-		Serial:       fields.Serial,
-	}, nil
-}
-
-// WriteTag grava dados em uma tag RFID
-func (a *App) WriteTag(req WriteRequest) error {
-	// Parar o watcher durante a escrita para evitar interferência PC/SC
-	a.StopTagWatcher()
+		return nil, err
+	}
 
-	// Validar cor
-	validatedColor, err := a.ValidateColor(req.Color)
+	// Abrir leitor RFID
+	ctx, done := a.beginOp(writeTimeout)
+	defer done()
+	reader, err := a.openReader(ctx, "write")
 	if err != nil {
-		return fmt.Errorf("Cor inválida: %v", err)
+		return nil, wrapReaderError("Erro ao conectar leitor", err)
 	}
+	defer reader.Close()
+	// Sinal no leitor antes de devolvê-lo: quem grava várias bobinas olha a tag, não a tela
+	signal := rfid.SignalError
+	defer func() { a.signal(reader, signal) }()
 
-	// Converter data YYYY-MM-DD para YYMDD
-	date, err := convertDate(req.Date)
+	// Identificar a tag: o formato gravado segue o tipo dela (CFS ou OpenSpool)
+	card, err := reader.Identify()
 	if err != nil {
-		return fmt.Errorf("Data inválida: %v", err)
+		return nil, wrapReaderError("Erro ao ler UID", err)
 	}
 
-	// Converter material se necessário
-	materialCode := convertMaterial(req.Material)
+	verification, err := a.writeCard(reader, card, target, fields, req.MinTemp, req.MaxTemp)
+	if err != nil && verification != nil && !verification.OK() {
+		slog.Warn("gravação com campos divergentes", "uid", card.UID, "mismatches", verification.Mismatches)
+		a.emitMismatch(verification)
+		return verification, err
+	}
+	if err == nil {
+		signal = rfid.SignalWrite
+		if req.SpoolmanID > 0 {
+			// Na fila antes da releitura que o defer pede: o vínculo vem antes da sincronização
+			a.enqueueSpoolman(func() { a.linkSpoolmanEvent(card.UID, req.SpoolmanID) })
+		}
+	}
+	return verification, err
+}
 
-	// Converter comprimento se necessário
-	lengthCode := convertLength(req.Length)
+// emitMismatch manda à UI a verificação com campos divergentes: o Wails
+// descarta o resultado de um método que retorna erro. Sem ctx não há UI
+func (a *App) emitMismatch(v *rfid.WriteVerification) {
+	if a.ctx == nil {
+		return
+	}
+	wailsRuntime.EventsEmit(a.ctx, "write:mismatch", v)
+}
 
-	// Abrir leitor RFID
-	reader, err := rfid.Open()
+// buildFields valida o pedido do formulário e monta os campos CFS
+func (a *App) buildFields(req WriteRequest) (creality.Fields, error) {
+	// Validar cor
+	validatedColor, err := a.ValidateColor(req.Color)
 	if err != nil {
-		return fmt.Errorf("Erro ao conectar leitor: %v", err)
+		return creality.Fields{}, fmt.Errorf("Cor inválida: %v", err)
 	}
-	defer reader.Close()
 
-	// Obter UID
-	uid, err := reader.UID()
+	// Converter data YYYY-MM-DD para YYMDD
+	date, err := convertDate(req.Date)
 	if err != nil {
-		return fmt.Errorf("Erro ao ler UID: %v", err)
+		return creality.Fields{}, fmt.Errorf("Data inválida: %v", err)
 	}
 
 	// Preparar campos
 	fields := creality.NewFields()
 	fields.Date = date
 	fields.Supplier = vendorToSupplier(req.Supplier)
-	fields.Material = materialCode
-	fields.Length = lengthCode
+	fields.Material = convertMaterial(req.Material)
+	fields.Length = convertLength(req.Length)
 	fields.Serial = padSerial(req.Serial)
 
 	// Definir cor com validação
 	if err := fields.SetColor(validatedColor); err != nil {
-		return fmt.Errorf("Erro no formato da cor: %v", err)
+		return creality.Fields{}, fmt.Errorf("Erro no formato da cor: %v", err)
 	}
+	return fields, nil
+}
 
+// writeFields grava os campos na tag uid já identificada e confere a
+// gravação relendo com a key derivada.
+func (a *App) writeFields(reader *rfid.Reader, uid string, fields creality.Fields) (*rfid.WriteVerification, error) {
 	// Gerar payload de 48 bytes
 	payload, err := fields.ASCIIConcat48()
 	if err != nil {
-		return fmt.Errorf("Erro na validação: %v", err)
+		return nil, fmt.Errorf("Erro na validação: %v", err)
 	}
 
 	// Criptografar dados
 	b4, b5, b6, err := creality.EncryptPayloadToBlocks(payload)
 	if err != nil {
-		return fmt.Errorf("Erro na criptografia: %v", err)
+		return nil, fmt.Errorf("Erro na criptografia: %v", err)
 	}
 
 	// Escrever na tag
 	blocksToWrite := []string{b4, b5, b6}
 	err = reader.WriteTagCFS(uid, blocksToWrite, false)
+	var ie *rfid.WriteInterruptedError
+	if errors.As(err, &ie) {
+		// Concluída (ou desfeita) quando a mesma tag voltar — ver handleTagPresent
+		a.interruptWrite(ie, fields)
+		return nil, wrapReaderError("Gravação interrompida", err)
+	}
 	if err != nil {
-		a.StartTagWatcher()
-		return fmt.Errorf("Erro na escrita: %v", err)
+		return nil, wrapReaderError("Erro na escrita", err)
 	}
 
-	// Aguardar tag estabilizar após escrita
-	time.Sleep(1 * time.Second)
+	// Reler com a key derivada e conferir os campos gravados
+	verification, err := reader.VerifyTagCFS(uid, fields)
+	if err != nil {
+		return verification, wrapReaderError("Erro na verificação", err)
+	}
+	slog.Info("tag gravada e verificada", "uid", uid, "material", fields.Material,
+		"color", fields.Color, "length", fields.Length, "serial", fields.Serial)
+	return verification, nil
+}
 
-	// Reiniciar leitura — lastUID vazio força releitura com dados atualizados
-	a.StartTagWatcher()
+// IdentifyTag identifica o cartão no leitor (tipo, ATR, ATQA/SAK) e testa
+// se é um clone "mágico" — útil quando a impressora rejeita a tag
+func (a *App) IdentifyTag() (*rfid.CardInfo, error) {
+	ctx, done := a.beginOp(readTimeout)
+	defer done()
+	reader, err := a.openReader(ctx, "identify")
+	if err != nil {
+		return nil, wrapReaderError("Erro ao conectar leitor", err)
+	}
+	defer reader.Close()
+
+	card, err := reader.Identify()
+	if err != nil {
+		return nil, wrapReaderError("Erro ao identificar tag", err)
+	}
+	// Detecção de clone só existe em leitores PN532; nos demais fica vazia
+	if magic, err := reader.DetectMagic(); err == nil {
+		card.Magic = magic
+	}
+	return card, nil
+}
+
+// ResetTag devolve a tag ao estado de fábrica (blocos 4–6 zerados e
+// trailer com FFFFFFFFFFFF) para ser reaproveitada
+func (a *App) ResetTag() error {
+	defer a.rescanTag()
+
+	ctx, done := a.beginOp(writeTimeout)
+	defer done()
+	reader, err := a.openReader(ctx, "reset")
+	if err != nil {
+		return wrapReaderError("Erro ao conectar leitor", err)
+	}
+	defer reader.Close()
 
+	card, err := reader.Identify()
+	if err != nil {
+		return wrapReaderError("Erro ao ler UID", err)
+	}
+	if err := card.Supported(); err != nil {
+		return wrapReaderError("Tag recusada", err)
+	}
+	if err := reader.ResetTagCFS(card.UID); err != nil {
+		return wrapReaderError("Erro ao resetar tag", err)
+	}
+	slog.Info("tag resetada", "uid", card.UID)
 	return nil
 }
 
@@ -380,6 +576,55 @@ func (a *App) ValidateColor(hex string) (string, error) {
 
 // --- Helpers privados ---
 
+// readerError mensagem amigável para a UI que preserva o erro original (errors.Is)
+type readerError struct {
+	msg string
+	err error
+}
+
+func (e *readerError) Error() string { return e.msg }
+func (e *readerError) Unwrap() error { return e.err }
+
+// wrapReaderError traduz erros tipados do pacote rfid em mensagens para o usuário
+func wrapReaderError(prefix string, err error) error {
+	slog.Warn(prefix, "err", err)
+	return &readerError{msg: prefix + ": " + readerErrorMessage(err), err: err}
+}
+
+// readerErrorMessage descreve a causa de um erro do leitor
+func readerErrorMessage(err error) string {
+	var ie *rfid.WriteInterruptedError
+	if errors.As(err, &ie) {
+		return fmt.Sprintf("a tag saiu do leitor no passo %d de %d — recoloque a mesma tag (UID %s) para concluir",
+			ie.Tx.Done+1, ie.Tx.Steps(), ie.Tx.UID)
+	}
+	switch {
+	case errors.Is(err, rfid.ErrNoReader):
+		return "nenhum leitor RFID conectado"
+	case errors.Is(err, rfid.ErrCardRemoved):
+		return "tag ausente ou removida do leitor — mantenha a tag posicionada"
+	case errors.Is(err, rfid.ErrAuthFailed):
+		return "a tag recusou as keys conhecidas (tag de outro sistema ou key alterada)"
+	case errors.Is(err, rfid.ErrAccessDenied):
+		return "os access bits da tag não permitem esta operação"
+	case errors.Is(err, rfid.ErrBlockNotFound):
+		return "bloco inexistente — a tag não parece ser MIFARE Classic 1K"
+	case errors.Is(err, rfid.ErrWrongTag):
+		return err.Error()
+	case errors.Is(err, rfid.ErrNotSupported):
+		return "comando não suportado pelo leitor ou pela tag"
+	case errors.Is(err, rfid.ErrNotNDEF):
+		return "a NTAG não está formatada para NDEF — formate-a com um app NFC antes de gravar"
+	case errors.Is(err, rfid.ErrTagFull):
+		return "os dados OpenSpool não cabem nesta tag — use NTAG215 ou NTAG216"
+	case errors.Is(err, context.Canceled):
+		return "operação cancelada — a tag pode ter ficado com a gravação incompleta"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "o leitor não respondeu a tempo"
+	}
+	return err.Error()
+}
+
 // vendorToSupplier mapeia código de vendor da UI para o código supplier do RFID
 func vendorToSupplier(vendor string) string {
 	if vendor == "0000" {
@@ -545,19 +790,8 @@ func convertLength(length string) string {
 		return code
 	}
 
-	gramMap := map[string]string{
-		"250": "0053", "500": "00A5", "1000": "014A", "2000": "0294",
-	}
-	if code, ok := gramMap[length]; ok {
-		return code
-	}
-
 	if grams, err := strconv.Atoi(length); err == nil {
-		cm := grams / 3
-		if cm > 65535 {
-			cm = 65535
-		}
-		return fmt.Sprintf("%04X", cm)
+		return creality.LengthFromGrams(grams)
 	}
 
 	return "0053"
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/bambu"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	"github.com/robertocorreajr/cfs_spool/internal/tagformat"
+)
+
+// BambuConversion tag Bambu Lab lida e o pedido de gravação CFS equivalente
+type BambuConversion struct {
+	UID        string       `json:"uid"`
+	MaterialID string       `json:"materialId"` // "GFA01"
+	Material   string       `json:"material"`   // tipo detalhado: "PLA Matte"
+	Color      string       `json:"color"`      // 6 hex, sem alfa
+	Weight     int          `json:"weight"`     // g
+	Diameter   float32      `json:"diameter"`   // mm
+	BedTemp    int          `json:"bedTemp"`    // °C
+	Produced   string       `json:"produced"`   // YYYY-MM-DD ("" se ilegível)
+	Request    WriteRequest `json:"request"`    // formulário para uma tag CFS virgem
+}
+
+// ConvertBambuTag lê a tag Bambu Lab no leitor e monta o pedido de
+// gravação CFS com o material Creality mais próximo. A tag Bambu não é
+// alterada; o pedido é gravado depois numa tag CFS virgem.
+func (a *App) ConvertBambuTag() (*BambuConversion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), readTimeout)
+	defer cancel()
+	reader, err := a.openReader(ctx, "read")
+	if err != nil {
+		return nil, wrapReaderError("Erro ao conectar leitor", err)
+	}
+	defer reader.Close()
+
+	card, err := reader.Identify()
+	if err != nil {
+		return nil, wrapReaderError("Erro ao ler UID", err)
+	}
+	if err := card.Supported(); err != nil {
+		return nil, wrapReaderError("Tag recusada", err)
+	}
+	tag, err := a.readBambu(reader, card)
+	if err != nil {
+		return nil, err
+	}
+
+	conv := &BambuConversion{
+		UID:        card.UID,
+		MaterialID: tag.MaterialID,
+		Material:   tag.DetailedType,
+		Color:      tag.ColorHex(),
+		Weight:     tag.Weight,
+		Diameter:   tag.Diameter,
+		BedTemp:    tag.BedTemp,
+		Request:    bambuRequest(tag),
+	}
+	if !tag.Produced.IsZero() {
+		conv.Produced = tag.Produced.Format("2006-01-02")
+	}
+	slog.Info("tag Bambu convertida", "uid", card.UID, "material", tag.MaterialID,
+		"type", tag.DetailedType, "creality", conv.Request.Material)
+	return conv, nil
+}
+
+// readBambu lê a tag Bambu Lab pelo formato registrado
+func (a *App) readBambu(reader *rfid.Reader, card *rfid.CardInfo) (*bambu.Tag, error) {
+	f, ok := a.formats.Get(formatBambu)
+	if !ok {
+		return nil, fmt.Errorf("Formato Bambu Lab não registrado")
+	}
+	t := tagformat.NewTag(reader, card)
+	ok, err := f.Detect(t)
+	if err != nil {
+		return nil, wrapReaderError("Erro ao ler tag Bambu Lab", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: a tag recusou as keys Bambu Lab", bambu.ErrNotBambu)
+	}
+	spool, err := f.Decode(t)
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao ler tag Bambu Lab: %w", err)
+	}
+	tag, ok := spool.Native.(*bambu.Tag)
+	if !ok {
+		return nil, fmt.Errorf("Erro ao ler tag Bambu Lab: dados %T inesperados", spool.Native)
+	}
+	return tag, nil
+}
+
+// bambuRequest pedido de gravação CFS equivalente à bobina Bambu
+func bambuRequest(tag *bambu.Tag) WriteRequest {
+	code := tag.CrealityCode()
+	req := WriteRequest{
+		Date:     time.Now().Format("2006-01-02"),
+		Supplier: materialToVendor(code),
+		Material: code,
+		Color:    tag.ColorHex(),
+		Length:   "0330",
+		Serial:   "000001",
+		MinTemp:  tag.MinTemp,
+		MaxTemp:  tag.MaxTemp,
+	}
+	if !tag.Produced.IsZero() {
+		req.Date = tag.Produced.Format("2006-01-02")
+	}
+	if tag.Weight > 0 {
+		req.Length = lengthCodeForGrams(tag.Weight)
+	}
+	return req
+}
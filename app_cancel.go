@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Prazos das operações com o leitor; um APDU travado não prende a UI além disso
+const (
+	readTimeout  = 10 * time.Second
+	writeTimeout = 30 * time.Second
+	dumpTimeout  = 2 * time.Minute // 16 setores × dicionário de keys
+)
+
+// beginOp cria o contexto de uma operação com o leitor, cancelável pela UI
+// via CancelOperation. done libera o contexto ao fim da operação; cada
+// operação tem a sua vez na lista, então o fim de uma não tira o
+// cancelamento de outra que se sobrepôs a ela.
+func (a *App) beginOp(timeout time.Duration) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	a.opMu.Lock()
+	if a.ops == nil {
+		a.ops = map[int]context.CancelFunc{}
+	}
+	a.opSeq++
+	id := a.opSeq
+	a.ops[id] = cancel
+	a.opMu.Unlock()
+	return ctx, func() {
+		a.opMu.Lock()
+		delete(a.ops, id)
+		a.opMu.Unlock()
+		cancel()
+	}
+}
+
+// CancelOperation interrompe a gravação (ou reset, dump, restore) em
+// andamento. Retorna false se não há operação em andamento.
+func (a *App) CancelOperation() bool {
+	a.opMu.Lock()
+	defer a.opMu.Unlock()
+	if len(a.ops) == 0 {
+		return false
+	}
+	slog.Info("operação cancelada pelo usuário", "ops", len(a.ops))
+	for _, cancel := range a.ops {
+		cancel()
+	}
+	return true
+}
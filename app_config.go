@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// configDirName pasta da aplicação dentro de os.UserConfigDir()
+const configDirName = "cfs-spool"
+
+// Config preferências persistidas entre execuções
+type Config struct {
+	Reader   string `json:"reader"`             // nome do leitor PC/SC escolhido ("" = automático)
+	DumpDir  string `json:"dumpDir,omitempty"`  // pasta dos dumps ("" = <config>/cfs-spool/dumps)
+	LogLevel string `json:"logLevel,omitempty"` // trace, debug, info, warn, error ("" = info)
+	Trace    bool   `json:"trace,omitempty"`    // registra APDUs com keys em claro
+
+	RecordAPDU bool `json:"recordApdu,omitempty"` // grava as sessões com o leitor (ver openReader)
+
+	Mute        bool `json:"mute,omitempty"`        // sinais de LED sem bipe
+	QuietDetect bool `json:"quietDetect,omitempty"` // desliga o bipe do ACR122U ao detectar a tag
+
+	Spoolman SpoolmanSettings `json:"spoolman"` // sincronização das tags lidas com o Spoolman
+
+	Printers []PrinterSettings `json:"printers,omitempty"` // impressoras Klipper (Moonraker)
+	Printer  string            `json:"printer,omitempty"`  // impressora que recebe a bobina lida ("" = nenhuma)
+}
+
+// configPath retorna o caminho do config.json no diretório de config do usuário
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configDirName, "config.json"), nil
+}
+
+// loadConfig lê o config do disco; arquivo inexistente resulta em config padrão
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// saveConfig grava o config criando a pasta se necessário
+func saveConfig(path string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfs-spool", "config.json")
+
+	// Arquivo inexistente resulta em config padrão
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig sem arquivo retornou erro: %v", err)
+	}
+	if cfg.Reader != "" {
+		t.Errorf("Reader padrão = %q, esperado vazio", cfg.Reader)
+	}
+
+	cfg.Reader = "ACS ACR122U PICC Interface 01 00"
+	cfg.Printers = []PrinterSettings{{Name: "K1", URL: "http://k1.local:7125", Tool: "T1"}}
+	cfg.Printer = "K1"
+	if err := saveConfig(path, cfg); err != nil {
+		t.Fatalf("saveConfig erro: %v", err)
+	}
+	volta, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig erro: %v", err)
+	}
+	if !reflect.DeepEqual(volta, cfg) {
+		t.Errorf("round-trip falhou: %+v -> %+v", cfg, volta)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/access"
+	"github.com/robertocorreajr/cfs_spool/internal/dump"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DumpSector resultado de um setor no dump
+type DumpSector struct {
+	Sector int    `json:"sector"`
+	KeyA   string `json:"keyA"` // "" = key não encontrada no dicionário
+	KeyB   string `json:"keyB"`
+	Read   bool   `json:"read"` // todos os blocos do setor foram lidos
+
+	AccessBits string   `json:"accessBits"` // bytes 6–9 do trailer ("" se ilegível)
+	Access     []string `json:"access"`     // condições decodificadas por bloco
+}
+
+// DumpResult resumo de um dump completo enviado ao frontend
+type DumpResult struct {
+	UID      string       `json:"uid"`
+	Complete bool         `json:"complete"`
+	Sectors  []DumpSector `json:"sectors"`
+	Files    []string     `json:"files"` // .bin, .mct e .json gravados
+}
+
+// DumpTag lê os 16 setores da tag com o dicionário padrão (FFFFFFFFFFFF,
+// key derivada do UID) mais extraKeys e arquiva o dump em .bin, .mct e
+// JSON do Proxmark3 na pasta de dumps.
+func (a *App) DumpTag(extraKeys []string) (*DumpResult, error) {
+	ctx, done := a.beginOp(dumpTimeout)
+	defer done()
+	reader, err := a.openReader(ctx, "dump")
+	if err != nil {
+		return nil, wrapReaderError("Erro ao conectar leitor", err)
+	}
+	defer reader.Close()
+
+	uid, err := reader.UID()
+	if err != nil {
+		return nil, wrapReaderError("Erro ao ler UID", err)
+	}
+	keys, err := rfid.DumpKeys(uid, extraKeys...)
+	if err != nil {
+		return nil, err
+	}
+	d, err := reader.Dump(keys)
+	if err != nil {
+		return nil, wrapReaderError("Erro no dump", err)
+	}
+
+	files, err := writeDumpFiles(a.dumpDir(), d, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao salvar dump: %v", err)
+	}
+	return newDumpResult(d, files), nil
+}
+
+// RestoreTag grava na tag o dump salvo em path (.bin, .mct ou .json),
+// dados antes dos trailers, e relê a tag para conferir o resultado.
+func (a *App) RestoreTag(path string, extraKeys []string) (*rfid.RestoreReport, error) {
+	d, err := dump.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao carregar dump: %v", err)
+	}
+
+	defer a.rescanTag()
+
+	ctx, done := a.beginOp(dumpTimeout)
+	defer done()
+	reader, err := a.openReader(ctx, "restore")
+	if err != nil {
+		return nil, wrapReaderError("Erro ao conectar leitor", err)
+	}
+	defer reader.Close()
+
+	uid, err := reader.UID()
+	if err != nil {
+		return nil, wrapReaderError("Erro ao ler UID", err)
+	}
+	keys, err := rfid.DumpKeys(uid, extraKeys...)
+	if err != nil {
+		return nil, err
+	}
+	rep, err := reader.Restore(d, keys)
+	if err != nil {
+		return rep, wrapReaderError("Erro na restauração", err)
+	}
+	return rep, nil
+}
+
+// SelectDumpFile abre o diálogo para escolher um dump salvo ("" se cancelado)
+func (a *App) SelectDumpFile() (string, error) {
+	return wailsRuntime.OpenFileDialog(a.ctx, wailsRuntime.OpenDialogOptions{
+		Title:            "Selecionar dump",
+		DefaultDirectory: a.dumpDir(),
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "Dumps (*.bin, *.mfd, *.dump, *.mct, *.json)", Pattern: "*.bin;*.mfd;*.dump;*.mct;*.json"},
+		},
+	})
+}
+
+// dumpDir pasta onde os dumps são arquivados
+func (a *App) dumpDir() string {
+	a.cfgMu.Lock()
+	dir := a.cfg.DumpDir
+	a.cfgMu.Unlock()
+	if dir != "" {
+		return dir
+	}
+	if a.cfgPath != "" {
+		return filepath.Join(filepath.Dir(a.cfgPath), "dumps")
+	}
+	return "dumps"
+}
+
+// writeDumpFiles grava o dump em todos os formatos como <UID>_<data-hora>.<ext>
+func writeDumpFiles(dir string, d *dump.Dump, now time.Time) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	base := filepath.Join(dir, fmt.Sprintf("%s_%s", d.UID, now.Format("20060102-150405")))
+
+	var files []string
+	for _, format := range []string{dump.FormatBin, dump.FormatMCT, dump.FormatJSON} {
+		path := base + "." + format
+		f, err := os.Create(path)
+		if err != nil {
+			return files, err
+		}
+		err = d.Write(f, format)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return files, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func newDumpResult(d *dump.Dump, files []string) *DumpResult {
+	res := &DumpResult{UID: d.UID, Complete: d.Complete(), Files: files}
+	for s := 0; s < dump.Sectors; s++ {
+		read := true
+		for b := s * dump.BlocksPerSector; b <= dump.TrailerBlock(s); b++ {
+			read = read && d.Read[b]
+		}
+		sector := DumpSector{
+			Sector: s,
+			KeyA:   d.Keys[s].KeyA,
+			KeyB:   d.Keys[s].KeyB,
+			Read:   read,
+		}
+		if t := dump.TrailerBlock(s); d.Read[t] {
+			sector.AccessBits = d.BlockHex(t)[12:20]
+			if bits, err := access.Decode(d.Blocks[t][6:10]); err == nil {
+				sector.Access = bits.Describe()
+			} else {
+				sector.Access = []string{err.Error()}
+			}
+		}
+		res.Sectors = append(res.Sectors, sector)
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+// feedbackTimeout prazo do sinal de LED/buzzer fora de uma operação
+const feedbackTimeout = 3 * time.Second
+
+// FeedbackSettings LED e buzzer do leitor (ACR122U)
+type FeedbackSettings struct {
+	Buzzer     bool `json:"buzzer"`     // bipes nos sinais de leitura, gravação e erro
+	DetectBeep bool `json:"detectBeep"` // bipe do próprio leitor ao detectar a tag
+}
+
+// GetFeedbackSettings retorna a configuração de LED/buzzer atual
+func (a *App) GetFeedbackSettings() FeedbackSettings {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+	return FeedbackSettings{Buzzer: !a.cfg.Mute, DetectBeep: !a.cfg.QuietDetect}
+}
+
+// SetFeedbackSettings liga/desliga os bipes e persiste a escolha. O bipe de
+// detecção é aplicado ao leitor na próxima tag.
+func (a *App) SetFeedbackSettings(s FeedbackSettings) error {
+	a.cfgMu.Lock()
+	a.cfg.Mute = !s.Buzzer
+	a.cfg.QuietDetect = !s.DetectBeep
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+	a.detectBeepSet.Store(false)
+
+	if a.cfgPath == "" {
+		return nil
+	}
+	if err := saveConfig(a.cfgPath, cfg); err != nil {
+		return fmt.Errorf("Erro ao salvar configuração: %v", err)
+	}
+	return nil
+}
+
+// signal pisca o LED (e bipa, se o buzzer estiver ligado) no leitor já
+// aberto por uma operação. Leitores sem LED/buzzer ficam em silêncio.
+func (a *App) signal(reader *rfid.Reader, s rfid.Signal) {
+	a.cfgMu.Lock()
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+
+	if !a.detectBeepSet.Load() {
+		err := reader.SetDetectBeep(!cfg.QuietDetect)
+		if err == nil || errors.Is(err, rfid.ErrNotSupported) {
+			a.detectBeepSet.Store(true)
+		}
+	}
+	if cfg.Mute {
+		s = s.Silent()
+	}
+	if err := reader.Signal(s); err != nil && !errors.Is(err, rfid.ErrNotSupported) {
+		slog.Debug("sinal de LED/buzzer falhou", "err", err)
+	}
+}
+
+// signalTag pega o leitor emprestado só para o sinal (ex.: tag lida pelo
+// watcher, cuja leitura já devolveu o leitor).
+func (a *App) signalTag(s rfid.Signal) {
+	ctx, cancel := context.WithTimeout(context.Background(), feedbackTimeout)
+	defer cancel()
+	reader, err := a.leaseReader(ctx)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	a.signal(reader, s)
+}
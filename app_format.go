@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	"github.com/robertocorreajr/cfs_spool/internal/tagformat"
+)
+
+// Formatos de tag em TagData.Format
+const (
+	formatCFS       = tagformat.NameCFS       // MIFARE Classic com dados Creality
+	formatOpenSpool = tagformat.NameOpenSpool // NTAG21x com JSON OpenSpool em NDEF
+	formatOpenTag3D = tagformat.NameOpenTag3D // NTAG21x com o layout binário OpenTag3D em NDEF
+	formatTigerTag  = tagformat.NameTigerTag  // NTAG213 com o bloco binário TigerTag nas páginas, sem NDEF
+	formatACE       = tagformat.NameACE       // NTAG21x com o layout em claro das bobinas Anycubic (ACE Pro)
+	formatBambu     = tagformat.NameBambu     // MIFARE Classic Bambu Lab (só leitura; o formulário vem convertido para CFS)
+)
+
+// FormatInfo formato de tag registrado, para a UI
+type FormatInfo struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Card     string `json:"card"` // tipo de cartão exigido (rfid.CardType)
+	Writable bool   `json:"writable"`
+}
+
+// newFormats registro dos formatos em ordem de detecção: CFS primeiro, e
+// nas NTAG os layouts em claro antes dos NDEF
+func newFormats(a *App) *tagformat.Registry {
+	return tagformat.NewRegistry(
+		tagformat.CFS{},
+		tagformat.Bambu{},
+		tagformat.TigerTag{Catalog: a.tigerCatalog, MaterialCode: materialCodeForName},
+		tagformat.ACE{},
+		tagformat.OpenSpool{},
+		tagformat.OpenTag3D{},
+	)
+}
+
+// GetTagFormats formatos de tag conhecidos, na ordem de detecção
+func (a *App) GetTagFormats() []FormatInfo {
+	var out []FormatInfo
+	for _, f := range a.formats.Formats() {
+		out = append(out, FormatInfo{
+			Name:     f.Name(),
+			Label:    f.Label(),
+			Card:     string(f.Card()),
+			Writable: tagformat.Writable(f),
+		})
+	}
+	return out
+}
+
+// isNTAG NTAG21x recebe os formatos NTAG do registro; MIFARE Classic
+// recebe CFS
+func isNTAG(card *rfid.CardInfo) bool {
+	return card.Type == rfid.CardUltralight
+}
+
+// checkCard recusa cartões que não recebem nenhum dos formatos
+func checkCard(card *rfid.CardInfo) error {
+	if isNTAG(card) {
+		return nil
+	}
+	return card.Supported()
+}
+
+// readFormat reconhece o formato da tag pelo registro e monta TagData;
+// spool fica nil em tag virgem. NTAG sem formato conhecido é tratada como
+// virgem (a gravação usa OpenSpool)
+func (a *App) readFormat(reader *rfid.Reader, card *rfid.CardInfo) (*TagData, *tagformat.Spool, error) {
+	f, spool, err := a.formats.Read(tagformat.NewTag(reader, card))
+	switch {
+	case errors.Is(err, tagformat.ErrUnknownFormat) && isNTAG(card):
+		slog.Debug("NTAG sem formato conhecido", "uid", card.UID)
+		return blankData(card.UID, formatOpenSpool), nil, nil
+	case errors.Is(err, tagformat.ErrUnknownFormat):
+		// Nos formatos MIFARE Classic só keys recusadas deixam a tag sem formato
+		return nil, nil, wrapReaderError("Tag não reconhecida", fmt.Errorf("%w: %v", rfid.ErrAuthFailed, err))
+	case err != nil:
+		return nil, nil, wrapReaderError("Erro ao ler tag", err)
+	}
+	if spool.Blank {
+		return blankData(card.UID, f.Name()), nil, nil
+	}
+	return spoolData(card.UID, f.Name(), spool), spool, nil
+}
+
+// blankData TagData padrão de uma tag virgem do formato
+func blankData(uid, format string) *TagData {
+	data := &TagData{
+		UID:          uid,
+		Format:       format,
+		Date:         time.Now().Format("2006-01-02"),
+		SupplierCode: "0000",
+		SupplierName: vendorName("0000"),
+		Color:        "000000",
+		LengthCode:   "0330",
+		Serial:       "000001",
+		IsBlank:      true,
+	}
+	if format == formatCFS {
+		data.SupplierCode, data.SupplierName = "0276", "Creality"
+		data.LengthDisplay = "330cm (1kg)"
+	}
+	return data
+}
+
+// spoolData monta TagData do modelo comum. O vendor da UI vem do código
+// do material (não do supplier RFID); nome e marca do formato, quando
+// ele os traz, valem mais que os do catálogo Creality
+func spoolData(uid, format string, s *tagformat.Spool) *TagData {
+	f := s.Fields
+	vendorCode := materialToVendor(f.Material)
+	data := &TagData{
+		UID:          uid,
+		Format:       format,
+		SupplierCode: vendorCode,
+		SupplierName: s.Brand,
+		MaterialCode: f.Material,
+		MaterialName: s.Type,
+		Serial:       f.Serial,
+		MinTemp:      s.MinTemp,
+		MaxTemp:      s.MaxTemp,
+	}
+	if data.SupplierName == "" {
+		data.SupplierName = vendorName(vendorCode)
+	}
+	if data.MaterialName == "" {
+		data.MaterialName = f.GetMaterialName()
+	}
+	// Cor sem o prefixo "0"
+	if len(f.Color) == 7 && f.Color[0] == '0' {
+		data.Color = strings.ToUpper(f.Color[1:])
+	}
+	switch {
+	case f.Date != "":
+		data.Date = parseDateToISO(f.Date)
+		data.DateDisplay = f.FormatDate()
+	case !s.Made.IsZero():
+		data.Date = s.Made.Format("2006-01-02")
+		data.DateDisplay = s.Made.Format("02/01/2006")
+	}
+	switch {
+	case s.Weight > 0:
+		data.LengthCode = lengthCodeForGrams(s.Weight)
+		data.LengthDisplay = fmt.Sprintf("%dg", s.Weight)
+	case f.Length != "":
+		data.LengthCode = f.Length
+		data.LengthDisplay = f.FormatLength()
+	}
+	return data
+}
+
+// lengthCodeForGrams opção de comprimento da UI com o peso dado; pesos
+// fora da lista ficam com a opção de 1kg
+func lengthCodeForGrams(grams int) string {
+	for _, l := range lengths {
+		if l.Grams == strconv.Itoa(grams) {
+			return l.Code
+		}
+	}
+	return "0330"
+}
+
+// writeTarget formato de destino do pedido. "" e formatCFS seguem a tag
+// presente: CFS em MIFARE Classic, OpenSpool em NTAG (ou TigerTag, se ela
+// já for uma); os demais precisam ser formatos graváveis do registro
+func (a *App) writeTarget(format string) (string, error) {
+	if format == "" || format == formatCFS {
+		return formatCFS, nil
+	}
+	if f, ok := a.formats.Get(format); ok && tagformat.Writable(f) {
+		return format, nil
+	}
+	return "", fmt.Errorf("Formato de gravação inválido: %q", format)
+}
+
+// writeCard grava os campos no formato target e confere relendo; o
+// cartão precisa ser do tipo que o formato usa
+func (a *App) writeCard(reader *rfid.Reader, card *rfid.CardInfo, target string, fields creality.Fields, minTemp, maxTemp int) (*rfid.WriteVerification, error) {
+	if target == formatCFS && isNTAG(card) {
+		target = formatOpenSpool
+		if a.hasFormat(reader, card, formatTigerTag) {
+			target = formatTigerTag
+		}
+	}
+	f, ok := a.formats.Get(target)
+	if !ok {
+		return nil, fmt.Errorf("Formato de gravação inválido: %q", target)
+	}
+	if err := tagformat.Check(f, card); err != nil {
+		return nil, wrapReaderError("Tag recusada", err)
+	}
+	if f.Storage() == tagformat.StorageCreality {
+		// Troca de keys e retomada: a transação CFS cifra os campos ela mesma
+		return a.writeFields(reader, card.UID, fields)
+	}
+	want := tagformat.Spool{Fields: fields, MinTemp: minTemp, MaxTemp: maxTemp}
+	if d, err := time.Parse("2006-01-02", parseDateToISO(fields.Date)); err == nil {
+		want.Made = d
+	}
+	return a.writeFormat(reader, card, f, want)
+}
+
+// hasFormat a tag presente já está no formato name (com ou sem dados)
+func (a *App) hasFormat(reader *rfid.Reader, card *rfid.CardInfo, name string) bool {
+	f, ok := a.formats.Get(name)
+	if !ok {
+		return false
+	}
+	found, err := f.Detect(tagformat.NewTag(reader, card))
+	return err == nil && found
+}
+
+// writeFormat grava a NTAG no formato f e confere relendo: bytes iguais
+// aos gravados bastam; senão os campos divergentes vão para a verificação
+func (a *App) writeFormat(reader *rfid.Reader, card *rfid.CardInfo, f tagformat.Format, want tagformat.Spool) (*rfid.WriteVerification, error) {
+	b, err := f.Encode(want)
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao montar %s: %v", f.Label(), err)
+	}
+	if err := tagformat.Store(reader, f.Storage(), b); err != nil {
+		return nil, wrapReaderError("Erro na escrita", err)
+	}
+
+	v := &rfid.WriteVerification{UID: card.UID}
+	back, err := tagformat.Load(reader, f.Storage(), len(b))
+	if err != nil {
+		return v, wrapReaderError("Erro na verificação", err)
+	}
+	got, err := f.Decode(tagformat.Stored(card, f.Storage(), back))
+	if err != nil {
+		return v, wrapReaderError("Erro na verificação", fmt.Errorf("%w: %v", rfid.ErrVerifyFailed, err))
+	}
+	v.Fields = got.Fields
+	if !bytes.Equal(back, b) {
+		if sent, err := f.Decode(tagformat.Stored(card, f.Storage(), b)); err == nil {
+			v.Mismatches = tagformat.Compare(*sent, *got)
+		}
+		if len(v.Mismatches) == 0 {
+			v.Mismatches = append(v.Mismatches, rfid.FieldMismatch{Field: "raw", Expected: "dados gravados", Actual: "dados diferentes"})
+		}
+	}
+	if !v.OK() {
+		return v, wrapReaderError("Erro na verificação", fmt.Errorf("%w: %d campo(s) divergente(s)", rfid.ErrVerifyFailed, len(v.Mismatches)))
+	}
+	slog.Info("tag gravada e verificada", "format", f.Name(), "uid", card.UID,
+		"material", want.Fields.Material, "color", want.Fields.Color)
+	return v, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+const (
+	logFileName   = "cfs-spool.log"
+	logMaxSize    = 2 << 20 // 2 MiB por arquivo
+	logMaxBackups = 3
+	logDirName    = "logs"
+)
+
+// LogSettings nível de log e trace de APDUs expostos ao frontend
+type LogSettings struct {
+	Level string `json:"level"` // trace, debug, info, warn, error
+	Trace bool   `json:"trace"` // APDUs no log, keys em claro
+	File  string `json:"file"`  // arquivo de log atual ("" = só stderr)
+}
+
+// logPath retorna <config>/cfs-spool/logs/cfs-spool.log
+func logPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configDirName, logDirName, logFileName), nil
+}
+
+// setupLogging direciona slog (app e internal/rfid) para stderr e para o
+// arquivo de log com rotação. Sem arquivo, segue só com stderr.
+func (a *App) setupLogging() {
+	var w io.Writer = os.Stderr
+	if path, err := logPath(); err == nil {
+		if f, err := logging.OpenRotating(path, logMaxSize, logMaxBackups); err == nil {
+			a.logFile = f
+			w = io.MultiWriter(os.Stderr, f)
+		}
+	}
+
+	a.cfgMu.Lock()
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+	a.applyLogSettings(cfg.LogLevel, cfg.Trace)
+
+	logger := logging.New(w, &a.logLevel)
+	slog.SetDefault(logger)
+	rfid.SetLogger(logger)
+	slog.Info("CFS Spool iniciado", "version", version, "level", a.logLevel.Level(), "trace", cfg.Trace)
+}
+
+// applyLogSettings ajusta o nível em execução. Trace força o nível TRACE.
+func (a *App) applyLogSettings(level string, trace bool) error {
+	lvl, err := logging.ParseLevel(level)
+	if trace {
+		lvl = logging.LevelTrace
+	}
+	a.logLevel.Set(lvl)
+	rfid.SetTrace(trace)
+	return err
+}
+
+// GetLogSettings retorna a configuração de log atual
+func (a *App) GetLogSettings() LogSettings {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+	s := LogSettings{Level: a.cfg.LogLevel, Trace: a.cfg.Trace}
+	if s.Level == "" {
+		s.Level = "info"
+	}
+	if a.logFile != nil {
+		s.File = a.logFile.Path()
+	}
+	return s
+}
+
+// SetLogSettings altera nível e trace de APDUs e persiste a escolha.
+// Com trace ligado as keys aparecem em claro no log.
+func (a *App) SetLogSettings(level string, trace bool) error {
+	if _, err := logging.ParseLevel(level); err != nil {
+		return fmt.Errorf("Erro ao configurar log: %v", err)
+	}
+	a.applyLogSettings(level, trace)
+
+	a.cfgMu.Lock()
+	a.cfg.LogLevel = level
+	a.cfg.Trace = trace
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+
+	slog.Info("configuração de log alterada", "level", level, "trace", trace)
+	if a.cfgPath == "" {
+		return nil
+	}
+	if err := saveConfig(a.cfgPath, cfg); err != nil {
+		return fmt.Errorf("Erro ao salvar configuração: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/moonraker"
+	"github.com/robertocorreajr/cfs_spool/internal/tagformat"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// PrinterSettings impressora Klipper (Moonraker) que recebe a bobina lida
+type PrinterSettings struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`               // "http://k1.local:7125"
+	APIKey  string `json:"apiKey,omitempty"`  // só com autorização ligada no Moonraker
+	Mode    string `json:"mode,omitempty"`    // "spoolman" (padrão) ou "gcode" (macro SET_ACTIVE_SPOOL)
+	Tool    string `json:"tool,omitempty"`    // extruder/slot alvo ("T1"); "" = único
+	Macro   string `json:"macro,omitempty"`   // macro que recebe TYPE e COLOR ("" = nenhuma)
+	Timeout int    `json:"timeout,omitempty"` // segundos por requisição (0 = 5)
+}
+
+// PrinterActivation bobina marcada como ativa numa impressora
+type PrinterActivation struct {
+	Printer string `json:"printer"`
+	SpoolID int    `json:"spoolId"` // 0 = só a macro de filamento
+	Tool    string `json:"tool,omitempty"`
+}
+
+// GetPrinters retorna as impressoras configuradas
+func (a *App) GetPrinters() []PrinterSettings {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+	return append([]PrinterSettings(nil), a.cfg.Printers...)
+}
+
+// SetPrinters troca a lista de impressoras e persiste; a impressora
+// escolhida que saiu da lista deixa de receber as bobinas
+func (a *App) SetPrinters(list []PrinterSettings) error {
+	seen := map[string]bool{}
+	for i := range list {
+		p := &list[i]
+		p.Name, p.URL = strings.TrimSpace(p.Name), strings.TrimSpace(p.URL)
+		if p.Name == "" || seen[p.Name] {
+			return fmt.Errorf("Nome de impressora inválido ou repetido: %q", p.Name)
+		}
+		seen[p.Name] = true
+		if _, err := moonraker.NewClient(p.URL, p.APIKey, 0); err != nil {
+			return fmt.Errorf("Impressora %s: %v", p.Name, err)
+		}
+		if p.Mode != "" && p.Mode != moonraker.ModeSpoolman && p.Mode != moonraker.ModeGcode {
+			return fmt.Errorf("Impressora %s: modo inválido %q", p.Name, p.Mode)
+		}
+		// O endpoint do Spoolman não tem extruder: o alvo só chega à
+		// impressora pela macro SET_GCODE_VARIABLE ou pela de filamento
+		if p.Tool != "" && p.Mode != moonraker.ModeGcode && p.Macro == "" {
+			return fmt.Errorf("Impressora %s: extruder %s exige o modo gcode ou uma macro de filamento", p.Name, p.Tool)
+		}
+	}
+
+	a.cfgMu.Lock()
+	a.cfg.Printers = list
+	if !seen[a.cfg.Printer] {
+		a.cfg.Printer = ""
+	}
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+
+	if a.cfgPath == "" {
+		return nil
+	}
+	if err := saveConfig(a.cfgPath, cfg); err != nil {
+		return fmt.Errorf("Erro ao salvar configuração: %v", err)
+	}
+	return nil
+}
+
+// GetSelectedPrinter retorna a impressora que recebe as bobinas lidas
+// ("" = nenhuma)
+func (a *App) GetSelectedPrinter() string {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+	return a.cfg.Printer
+}
+
+// SelectPrinter escolhe a impressora que recebe as bobinas lidas e
+// persiste a escolha. Nome vazio desliga o envio.
+func (a *App) SelectPrinter(name string) error {
+	a.cfgMu.Lock()
+	if _, ok := findPrinter(a.cfg.Printers, name); !ok && name != "" {
+		a.cfgMu.Unlock()
+		return fmt.Errorf("Impressora não configurada: %s", name)
+	}
+	a.cfg.Printer = name
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+
+	if a.cfgPath == "" {
+		return nil
+	}
+	if err := saveConfig(a.cfgPath, cfg); err != nil {
+		return fmt.Errorf("Erro ao salvar configuração: %v", err)
+	}
+	return nil
+}
+
+func findPrinter(list []PrinterSettings, name string) (PrinterSettings, bool) {
+	for _, p := range list {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return PrinterSettings{}, false
+}
+
+// activateSpool marca a bobina lida como ativa na impressora escolhida;
+// nil sem impressora escolhida ou sem nada a enviar. spoolID 0 (sem
+// Spoolman) só chama a macro de filamento
+func (a *App) activateSpool(spoolID int, s *tagformat.Spool) (*PrinterActivation, error) {
+	a.cfgMu.Lock()
+	p, ok := findPrinter(a.cfg.Printers, a.cfg.Printer)
+	a.cfgMu.Unlock()
+	if !ok || (spoolID == 0 && p.Macro == "") {
+		return nil, nil
+	}
+
+	c, err := moonraker.NewClient(p.URL, p.APIKey, time.Duration(p.Timeout)*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("Impressora %s: %v", p.Name, err)
+	}
+	e := spoolmanEntry("", s)
+	target := moonraker.Target{Mode: p.Mode, Tool: p.Tool, Macro: p.Macro}
+	fil := moonraker.Filament{SpoolID: spoolID, Type: e.Material, Color: e.ColorHex}
+	if err := c.Activate(context.Background(), target, fil); err != nil {
+		return nil, fmt.Errorf("Impressora %s: %w", p.Name, err)
+	}
+	return &PrinterActivation{Printer: p.Name, SpoolID: spoolID, Tool: p.Tool}, nil
+}
+
+// tagSpoolEvent leva a tag lida pelo watcher ao Spoolman e à impressora
+// escolhida, avisando a UI de cada resultado
+func (a *App) tagSpoolEvent(uid string, s *tagformat.Spool) {
+	res, syncErr := a.spoolmanSync(uid, s)
+	a.emitSpoolman(res, syncErr)
+
+	id := 0
+	if res != nil {
+		id = res.SpoolID
+	}
+	act, err := a.activateSpool(id, s)
+	switch {
+	case err != nil:
+		slog.Warn("moonraker: bobina ativa falhou", "err", err)
+		wailsRuntime.EventsEmit(a.ctx, "moonraker:error", err.Error())
+	case act != nil:
+		slog.Info("moonraker: bobina ativa", "printer", act.Printer, "spool", act.SpoolID, "tool", act.Tool)
+		wailsRuntime.EventsEmit(a.ctx, "moonraker:active", act)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/queue"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// QueueState fila de produção para a UI
+type QueueState struct {
+	Armed    bool           `json:"armed"` // grava a próxima entrada em cada tag nova
+	Items    []queue.Item   `json:"items"`
+	Progress queue.Progress `json:"progress"`
+	Report   string         `json:"report,omitempty"` // último relatório salvo
+}
+
+// QueueRefusal tag recusada pela fila armada
+type QueueRefusal struct {
+	UID    string `json:"uid"`
+	Reason string `json:"reason"`
+}
+
+// SelectQueueFile abre o diálogo para escolher o CSV da fila ("" se cancelado)
+func (a *App) SelectQueueFile() (string, error) {
+	return wailsRuntime.OpenFileDialog(a.ctx, wailsRuntime.OpenDialogOptions{
+		Title: "Selecionar fila de produção",
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "CSV (*.csv)", Pattern: "*.csv"},
+		},
+	})
+}
+
+// LoadQueueCSV carrega a fila de um CSV (colunas date, supplier, material,
+// color, length, serial e, para tags OpenSpool, min_temp e max_temp) e confere cada linha antes de aceitar a fila.
+func (a *App) LoadQueueCSV(path string) (*QueueState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao abrir fila: %v", err)
+	}
+	defer f.Close()
+	q, err := queue.ParseCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao ler fila: %v", err)
+	}
+	return a.setQueue(q)
+}
+
+// SetQueue monta a fila a partir de uma lista criada na UI
+func (a *App) SetQueue(reqs []WriteRequest) (*QueueState, error) {
+	entries := make([]queue.Entry, len(reqs))
+	for i, req := range reqs {
+		entries[i] = queue.Entry(req)
+	}
+	return a.setQueue(queue.New(entries))
+}
+
+// setQueue valida as entradas e troca a fila atual (desarmada)
+func (a *App) setQueue(q *queue.Queue) (*QueueState, error) {
+	if q.Len() == 0 {
+		return nil, fmt.Errorf("Fila vazia")
+	}
+	for i, it := range q.Items() {
+		if err := a.checkEntry(it.Entry); err != nil {
+			if it.Line > 0 {
+				return nil, fmt.Errorf("Linha %d: %v", it.Line, err)
+			}
+			return nil, fmt.Errorf("Entrada %d: %v", i+1, err)
+		}
+	}
+
+	a.queueMu.Lock()
+	a.queue, a.queueArmed, a.queueReport = q, false, ""
+	a.queueMu.Unlock()
+	slog.Info("fila de produção carregada", "entries", q.Len())
+	return a.emitQueue(), nil
+}
+
+// checkEntry recusa entradas que WriteTag também recusaria
+func (a *App) checkEntry(e queue.Entry) error {
+	fields, err := a.buildFields(WriteRequest(e))
+	if err != nil {
+		return err
+	}
+	if _, err := a.writeTarget(e.Format); err != nil {
+		return err
+	}
+	if _, err := fields.ASCIIConcat48(); err != nil {
+		return fmt.Errorf("Erro na validação: %v", err)
+	}
+	return nil
+}
+
+// GetQueue retorna a fila atual (nil se não houver)
+func (a *App) GetQueue() *QueueState {
+	a.queueMu.Lock()
+	defer a.queueMu.Unlock()
+	return a.queueState()
+}
+
+// ArmQueue passa a gravar a próxima entrada em cada tag nova detectada
+func (a *App) ArmQueue() (*QueueState, error) {
+	a.queueMu.Lock()
+	switch {
+	case a.queue == nil:
+		a.queueMu.Unlock()
+		return nil, fmt.Errorf("Nenhuma fila carregada")
+	case a.queue.Finished():
+		a.queueMu.Unlock()
+		return nil, fmt.Errorf("Fila já concluída")
+	}
+	a.queueArmed = true
+	a.queueMu.Unlock()
+	slog.Info("fila de produção armada")
+	return a.emitQueue(), nil
+}
+
+// DisarmQueue volta ao modo normal (a tag detectada só é lida)
+func (a *App) DisarmQueue() *QueueState {
+	a.queueMu.Lock()
+	a.queueArmed = false
+	a.queueMu.Unlock()
+	return a.emitQueue()
+}
+
+// SkipQueueItem pula a entrada i (pendente ou com falha)
+func (a *App) SkipQueueItem(i int) (*QueueState, error) {
+	return a.updateQueue(func(q *queue.Queue) error { return q.Skip(i) })
+}
+
+// RetryQueueItem devolve a entrada i à fila; se já gravada, a tag dela
+// pode ser regravada
+func (a *App) RetryQueueItem(i int) (*QueueState, error) {
+	return a.updateQueue(func(q *queue.Queue) error { return q.Retry(i) })
+}
+
+// ClearQueue descarta a fila
+func (a *App) ClearQueue() {
+	a.queueMu.Lock()
+	a.queue, a.queueArmed, a.queueReport = nil, false, ""
+	a.queueMu.Unlock()
+	wailsRuntime.EventsEmit(a.ctx, "queue:progress", nil)
+}
+
+// SaveQueueReport grava o relatório da fila agora (também salvo
+// automaticamente quando a fila termina) e retorna o caminho
+func (a *App) SaveQueueReport() (string, error) {
+	a.queueMu.Lock()
+	q := a.queue
+	a.queueMu.Unlock()
+	if q == nil {
+		return "", fmt.Errorf("Nenhuma fila carregada")
+	}
+	path, err := a.writeQueueReport(q)
+	if err != nil {
+		return "", fmt.Errorf("Erro ao salvar relatório: %v", err)
+	}
+	return path, nil
+}
+
+func (a *App) updateQueue(fn func(*queue.Queue) error) (*QueueState, error) {
+	a.queueMu.Lock()
+	q := a.queue
+	a.queueMu.Unlock()
+	if q == nil {
+		return nil, fmt.Errorf("Nenhuma fila carregada")
+	}
+	if err := fn(q); err != nil {
+		return nil, fmt.Errorf("Erro na fila: %v", err)
+	}
+	return a.emitQueue(), nil
+}
+
+// queueState monta o estado para a UI; chamar com queueMu travado
+func (a *App) queueState() *QueueState {
+	if a.queue == nil {
+		return nil
+	}
+	return &QueueState{
+		Armed:    a.queueArmed,
+		Items:    a.queue.Items(),
+		Progress: a.queue.Progress(),
+		Report:   a.queueReport,
+	}
+}
+
+// emitQueue avisa a UI do estado atual da fila e o retorna
+func (a *App) emitQueue() *QueueState {
+	a.queueMu.Lock()
+	state := a.queueState()
+	a.queueMu.Unlock()
+	wailsRuntime.EventsEmit(a.ctx, "queue:progress", state)
+	return state
+}
+
+// armedQueue fila a gravar na tag detectada (nil se desarmada)
+func (a *App) armedQueue() *queue.Queue {
+	a.queueMu.Lock()
+	defer a.queueMu.Unlock()
+	if !a.queueArmed {
+		return nil
+	}
+	return a.queue
+}
+
+// writeQueued grava a próxima entrada da fila na tag recém-detectada.
+// Tag já gravada pela fila é recusada com "queue:refused".
+func (a *App) writeQueued(q *queue.Queue) {
+	ctx, done := a.beginOp(writeTimeout)
+	defer done()
+	reader, err := a.openReader(ctx, "queue")
+	if err != nil {
+		wailsRuntime.EventsEmit(a.ctx, "tag:error", wrapReaderError("Erro ao conectar leitor", err).Error())
+		return
+	}
+	defer reader.Close()
+	signal := rfid.SignalError
+	defer func() { a.signal(reader, signal) }()
+
+	card, err := reader.Identify()
+	if err == nil {
+		err = checkCard(card)
+	}
+	if err != nil {
+		if !errors.Is(err, rfid.ErrCardRemoved) {
+			wailsRuntime.EventsEmit(a.ctx, "queue:refused", QueueRefusal{Reason: wrapReaderError("Tag recusada", err).Error()})
+		}
+		return
+	}
+
+	i, entry, err := q.Next(card.UID)
+	if err != nil {
+		slog.Warn("fila: tag recusada", "uid", card.UID, "err", err)
+		wailsRuntime.EventsEmit(a.ctx, "queue:refused", QueueRefusal{UID: card.UID, Reason: err.Error()})
+		return
+	}
+	fields, err := a.buildFields(WriteRequest(entry))
+	target := formatCFS
+	if err == nil {
+		target, err = a.writeTarget(entry.Format)
+	}
+	if err == nil {
+		_, err = a.writeCard(reader, card, target, fields, entry.MinTemp, entry.MaxTemp)
+	}
+	if err != nil {
+		slog.Warn("fila: gravação falhou", "index", i, "uid", card.UID, "err", err)
+		q.Fail(i, card.UID, err)
+		a.emitQueue()
+		return
+	}
+	q.Done(i, card.UID)
+	signal = rfid.SignalWrite
+	slog.Info("fila: entrada gravada", "index", i, "uid", card.UID)
+	if entry.SpoolmanID > 0 {
+		a.enqueueSpoolman(func() { a.linkSpoolmanEvent(card.UID, entry.SpoolmanID) })
+	}
+
+	if q.Finished() {
+		a.finishQueue(q)
+		return
+	}
+	a.emitQueue()
+}
+
+// finishQueue desarma a fila concluída e salva o relatório
+func (a *App) finishQueue(q *queue.Queue) {
+	path, err := a.writeQueueReport(q)
+	if err != nil {
+		slog.Warn("fila: relatório não salvo", "err", err)
+	}
+	a.queueMu.Lock()
+	if a.queue == q {
+		a.queueArmed = false
+	}
+	a.queueMu.Unlock()
+	slog.Info("fila de produção concluída", "report", path)
+	state := a.emitQueue()
+	wailsRuntime.EventsEmit(a.ctx, "queue:done", state)
+}
+
+// writeQueueReport grava reports/queue-<data-hora>.csv ao lado do config
+func (a *App) writeQueueReport(q *queue.Queue) (string, error) {
+	dir := "reports"
+	if a.cfgPath != "" {
+		dir = filepath.Join(filepath.Dir(a.cfgPath), "reports")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("queue-%s.csv", time.Now().Format("20060102-150405")))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	err = q.WriteReport(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	a.queueMu.Lock()
+	if a.queue == q {
+		a.queueReport = path
+	}
+	a.queueMu.Unlock()
+	return path, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+// recordDirName pasta das gravações de APDUs dentro de <config>/cfs-spool
+const recordDirName = "recordings"
+
+// recordDir retorna <config>/cfs-spool/recordings
+func recordDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configDirName, recordDirName), nil
+}
+
+// openReader pega o leitor emprestado da sessão do watcher, esperando a
+// operação em andamento terminar; reader.Close devolve o leitor. As
+// operações ficam limitadas por ctx. Com a gravação ligada, os APDUs da sessão vão para
+// recordings/<data>-<session>.apdu.jsonl, reproduzível selecionando o
+// leitor "replay:<arquivo>".
+func (a *App) openReader(ctx context.Context, session string) (*rfid.Reader, error) {
+	reader, err := a.leaseReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.cfgMu.Lock()
+	record := a.cfg.RecordAPDU
+	a.cfgMu.Unlock()
+	if !record {
+		return reader, nil
+	}
+
+	dir, err := recordDir()
+	if err == nil {
+		err = os.MkdirAll(dir, 0o755)
+	}
+	if err != nil {
+		slog.Warn("gravação de APDUs indisponível", "err", err)
+		return reader, nil
+	}
+	name := fmt.Sprintf("%s-%s.apdu.jsonl", time.Now().Format("20060102-150405.000"), session)
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		slog.Warn("gravação de APDUs indisponível", "err", err)
+		return reader, nil
+	}
+	reader.Record(f)
+	slog.Info("gravando APDUs", "session", session, "file", f.Name())
+	return reader, nil
+}
+
+// leaseReader pega o leitor emprestado da sessão do watcher, sem gravação
+func (a *App) leaseReader(ctx context.Context) (*rfid.Reader, error) {
+	a.sessionMu.Lock()
+	s := a.session
+	a.sessionMu.Unlock()
+	if s == nil {
+		return nil, rfid.ErrNoReader
+	}
+	return s.Open(ctx)
+}
+
+// GetAPDURecording informa se as sessões com o leitor estão sendo gravadas
+func (a *App) GetAPDURecording() bool {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+	return a.cfg.RecordAPDU
+}
+
+// SetAPDURecording liga/desliga a gravação de APDUs e retorna a pasta
+// onde as gravações ficam. As keys saem mascaradas como no log, salvo com
+// trace ligado; os dados dos blocos ficam em claro.
+func (a *App) SetAPDURecording(on bool) (string, error) {
+	a.cfgMu.Lock()
+	a.cfg.RecordAPDU = on
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+
+	dir, _ := recordDir()
+	if a.cfgPath == "" {
+		return dir, nil
+	}
+	if err := saveConfig(a.cfgPath, cfg); err != nil {
+		return dir, fmt.Errorf("Erro ao salvar configuração: %v", err)
+	}
+	return dir, nil
+}
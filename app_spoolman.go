@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/spoolman"
+	"github.com/robertocorreajr/cfs_spool/internal/tagformat"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SpoolmanSettings servidor Spoolman que recebe as tags lidas
+type SpoolmanSettings struct {
+	URL     string `json:"url"`               // "http://host:7912"; "" desliga a sincronização
+	Timeout int    `json:"timeout,omitempty"` // segundos por requisição (0 = 10)
+	Field   string `json:"field,omitempty"`   // campo extra da bobina com o UID ("" = "tag_uid")
+}
+
+// SpoolmanSync resultado da sincronização de uma tag lida
+type SpoolmanSync struct {
+	UID     string `json:"uid"`
+	SpoolID int    `json:"spoolId"`
+	Created bool   `json:"created"` // bobina nova no Spoolman
+}
+
+// SpoolmanSpool bobina do Spoolman para escolher na gravação
+type SpoolmanSpool struct {
+	ID        int     `json:"id"`
+	Label     string  `json:"label"` // "Creality Hyper PLA"
+	Material  string  `json:"material"`
+	Color     string  `json:"color"`     // 6 hex
+	Remaining float64 `json:"remaining"` // g
+	UID       string  `json:"uid"`       // tag já ligada à bobina ("" = nenhuma)
+}
+
+// GetSpoolmanSettings retorna a configuração do Spoolman atual
+func (a *App) GetSpoolmanSettings() SpoolmanSettings {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+	return a.cfg.Spoolman
+}
+
+// SetSpoolmanSettings troca o servidor do Spoolman e persiste a escolha;
+// URL vazia desliga a sincronização
+func (a *App) SetSpoolmanSettings(s SpoolmanSettings) error {
+	s.URL = strings.TrimSpace(s.URL)
+	if s.URL != "" {
+		if _, err := spoolman.NewClient(s.URL, 0); err != nil {
+			return err
+		}
+	}
+	if s.Timeout < 0 {
+		return fmt.Errorf("Prazo do Spoolman inválido: %d", s.Timeout)
+	}
+	a.cfgMu.Lock()
+	a.cfg.Spoolman = s
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+
+	a.spoolmanMu.Lock()
+	a.spoolman = nil
+	a.spoolmanMu.Unlock()
+
+	if a.cfgPath == "" {
+		return nil
+	}
+	if err := saveConfig(a.cfgPath, cfg); err != nil {
+		return fmt.Errorf("Erro ao salvar configuração: %v", err)
+	}
+	return nil
+}
+
+// spoolmanSyncer syncer do servidor configurado; nil com o Spoolman
+// desligado
+func (a *App) spoolmanSyncer() (*spoolman.Syncer, error) {
+	a.cfgMu.Lock()
+	s := a.cfg.Spoolman
+	a.cfgMu.Unlock()
+	if s.URL == "" {
+		return nil, nil
+	}
+
+	a.spoolmanMu.Lock()
+	defer a.spoolmanMu.Unlock()
+	if a.spoolman == nil {
+		c, err := spoolman.NewClient(s.URL, time.Duration(s.Timeout)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		if s.Field == "" {
+			s.Field = spoolman.DefaultField
+		}
+		a.spoolman = &spoolman.Syncer{Client: c, Field: s.Field}
+	}
+	return a.spoolman, nil
+}
+
+// ListSpoolmanSpools bobinas do Spoolman, para preencher uma gravação
+func (a *App) ListSpoolmanSpools() ([]SpoolmanSpool, error) {
+	sm, err := a.spoolmanSyncer()
+	if err != nil || sm == nil {
+		return nil, err
+	}
+	spools, err := sm.Client.Spools(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao consultar o Spoolman: %v", err)
+	}
+	out := make([]SpoolmanSpool, 0, len(spools))
+	for _, s := range spools {
+		item := SpoolmanSpool{ID: s.ID, Remaining: s.RemainingWeight, UID: spoolman.Text(s.Extra, sm.Field)}
+		if f := s.Filament; f != nil {
+			item.Label, item.Material, item.Color = f.Name, f.Material, strings.ToUpper(f.ColorHex)
+			if f.Vendor != nil {
+				item.Label = strings.TrimSpace(f.Vendor.Name + " " + f.Name)
+			}
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// SpoolmanRequest pedido de gravação preenchido com a bobina id do
+// Spoolman; gravado com sucesso, a tag fica ligada à bobina
+func (a *App) SpoolmanRequest(id int) (*WriteRequest, error) {
+	sm, err := a.spoolmanSyncer()
+	if err != nil {
+		return nil, err
+	}
+	if sm == nil {
+		return nil, fmt.Errorf("Spoolman não configurado")
+	}
+	s, err := sm.Client.Spool(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao consultar o Spoolman: %v", err)
+	}
+	req := &WriteRequest{
+		Date:       time.Now().Format("2006-01-02"),
+		Supplier:   "0000",
+		Material:   "00001",
+		Color:      "000000",
+		Length:     "0330",
+		Serial:     fmt.Sprintf("%06d", id%1000000),
+		SpoolmanID: id,
+	}
+	f := s.Filament
+	if f == nil {
+		return req, nil
+	}
+	vendor := ""
+	if f.Vendor != nil {
+		vendor = f.Vendor.Name
+	}
+	// Nome do filamento no catálogo; senão o genérico do material base
+	code := materialCodeForName(vendor, f.Name)
+	if code == "" {
+		code = materialCodeForName(vendor, f.Material)
+	}
+	if code != "" {
+		req.Material = code
+	}
+	req.Supplier = materialToVendor(req.Material)
+	if len(f.ColorHex) >= 6 {
+		req.Color = strings.ToUpper(f.ColorHex[:6])
+	}
+	switch {
+	case s.InitialWeight > 0:
+		req.Length = lengthCodeForGrams(int(s.InitialWeight))
+	case f.Weight > 0:
+		req.Length = lengthCodeForGrams(int(f.Weight))
+	}
+	req.MaxTemp = f.ExtruderTemp
+	return req, nil
+}
+
+// spoolmanEntry bobina da tag lida no vocabulário do Spoolman: nome,
+// marca e peso do formato valem mais que os do catálogo Creality
+func spoolmanEntry(uid string, s *tagformat.Spool) spoolman.Entry {
+	e := spoolman.FromFields(uid, s.Fields)
+	if s.Brand != "" {
+		e.Vendor = s.Brand
+	}
+	if s.Type != "" {
+		e.Name = s.Type
+	}
+	if s.Weight > 0 {
+		e.Weight = s.Weight
+	}
+	e.MaxTemp = s.MaxTemp
+	return e
+}
+
+// spoolmanSync acha ou cria a bobina do Spoolman da tag lida; nil com o
+// Spoolman desligado
+func (a *App) spoolmanSync(uid string, s *tagformat.Spool) (*SpoolmanSync, error) {
+	sm, err := a.spoolmanSyncer()
+	if err != nil || sm == nil {
+		return nil, err
+	}
+	spool, created, err := sm.Sync(context.Background(), spoolmanEntry(uid, s))
+	if err != nil {
+		return nil, fmt.Errorf("Erro ao sincronizar com o Spoolman: %v", err)
+	}
+	return &SpoolmanSync{UID: uid, SpoolID: spool.ID, Created: created}, nil
+}
+
+// spoolmanLink liga a tag gravada à bobina id do Spoolman
+func (a *App) spoolmanLink(uid string, id int) (*SpoolmanSync, error) {
+	sm, err := a.spoolmanSyncer()
+	if err != nil || sm == nil {
+		return nil, err
+	}
+	if _, err := sm.Link(context.Background(), id, uid); err != nil {
+		return nil, fmt.Errorf("Erro ao ligar a tag à bobina %d do Spoolman: %v", id, err)
+	}
+	return &SpoolmanSync{UID: uid, SpoolID: id}, nil
+}
+
+// spoolmanWorker roda os trabalhos de Spoolman e Moonraker um por vez, na
+// ordem em que chegaram: o vínculo pedido na gravação termina antes da
+// sincronização da releitura, que senão criaria outra bobina com o UID
+func (a *App) spoolmanWorker() {
+	for job := range a.spoolmanJobs {
+		job()
+	}
+}
+
+// enqueueSpoolman agenda o trabalho sem travar o watcher; com a fila cheia
+// (servidor fora do ar) o trabalho é descartado
+func (a *App) enqueueSpoolman(job func()) {
+	select {
+	case a.spoolmanJobs <- job:
+	default:
+		slog.Warn("spoolman: fila cheia, sincronização descartada")
+	}
+}
+
+// linkSpoolmanEvent liga a tag gravada à bobina e avisa a UI
+func (a *App) linkSpoolmanEvent(uid string, id int) {
+	a.emitSpoolman(a.spoolmanLink(uid, id))
+}
+
+func (a *App) emitSpoolman(res *SpoolmanSync, err error) {
+	switch {
+	case err != nil:
+		slog.Warn("spoolman: sincronização falhou", "err", err)
+		wailsRuntime.EventsEmit(a.ctx, "spoolman:error", err.Error())
+	case res != nil:
+		slog.Info("spoolman: tag sincronizada", "uid", res.UID, "spool", res.SpoolID, "created", res.Created)
+		wailsRuntime.EventsEmit(a.ctx, "spoolman:synced", res)
+	}
+}
@@ -1,7 +1,23 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/access"
+	"github.com/robertocorreajr/cfs_spool/internal/bambu"
+	"github.com/robertocorreajr/cfs_spool/internal/moonraker/moonrakertest"
+	"github.com/robertocorreajr/cfs_spool/internal/opentag3d"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	"github.com/robertocorreajr/cfs_spool/internal/spoolman/spoolmantest"
 )
 
 func TestValidateColor(t *testing.T) {
@@ -131,3 +147,605 @@ func TestConvertDateRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestWrapReaderError(t *testing.T) {
+	err := wrapReaderError("Erro ao ler bloco 4", fmt.Errorf("falha no bloco 4: %w", rfid.ErrAuthFailed))
+	if !errors.Is(err, rfid.ErrAuthFailed) {
+		t.Errorf("wrapReaderError deveria preservar o erro original")
+	}
+	esperado := "Erro ao ler bloco 4: a tag recusou as keys conhecidas (tag de outro sistema ou key alterada)"
+	if err.Error() != esperado {
+		t.Errorf("mensagem = %q, esperado %q", err.Error(), esperado)
+	}
+
+	// Erros sem sentinela mantêm a mensagem original
+	err = wrapReaderError("Erro na escrita", errors.New("falha qualquer"))
+	if err.Error() != "Erro na escrita: falha qualquer" {
+		t.Errorf("mensagem = %q", err.Error())
+	}
+}
+
+func TestReadTagReplay(t *testing.T) {
+	// testdata/readtag.apdu.jsonl: leitura de uma tag CFS gravada com Reader.Record
+	a := NewApp()
+	a.cfg.Reader = rfid.ReplayPrefix + filepath.Join("testdata", "readtag.apdu.jsonl")
+
+	// Sem a sessão do watcher não há leitor
+	if _, err := a.ReadTag(); !errors.Is(err, rfid.ErrNoReader) {
+		t.Fatalf("ReadTag sem sessão: %v", err)
+	}
+	dev, err := rfid.OpenDevice(a.cfg.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := rfid.NewSession(dev)
+	defer session.Close()
+	a.setSession(session)
+
+	data, err := a.ReadTag()
+	if err != nil {
+		t.Fatalf("ReadTag na reprodução: %v", err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  string
+		esperado string
+	}{
+		{"UID", data.UID, "A1B2C3D4"},
+		{"Material", data.MaterialCode, "04001"},
+		{"Cor", data.Color, "77BB41"},
+		{"Serial", data.Serial, "000001"},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %q, esperado %q", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+}
+
+func TestWrapReaderErrorInterruptedWrite(t *testing.T) {
+	tx := &rfid.WriteTx{UID: "A1B2C3D4", Blocks: make([]string, 3), NewTag: true, Done: 2}
+	err := wrapReaderError("Gravação interrompida", &rfid.WriteInterruptedError{Tx: tx, Err: rfid.ErrCardRemoved})
+	if !errors.Is(err, rfid.ErrCardRemoved) {
+		t.Errorf("wrapReaderError deveria preservar ErrCardRemoved")
+	}
+	esperado := "Gravação interrompida: a tag saiu do leitor no passo 3 de 4 — recoloque a mesma tag (UID A1B2C3D4) para concluir"
+	if err.Error() != esperado {
+		t.Errorf("mensagem = %q, esperado %q", err.Error(), esperado)
+	}
+}
+
+// tagDevice Device com uma tag emulada sempre presente
+type tagDevice struct {
+	tag  rfid.Transport
+	wake chan struct{}
+}
+
+func (d *tagDevice) Wait(present bool, timeout time.Duration) (bool, error) {
+	if present {
+		select {
+		case <-d.wake:
+		case <-time.After(timeout):
+		}
+	}
+	return true, nil
+}
+
+func (d *tagDevice) Interrupt() error {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (d *tagDevice) Connect() (rfid.Transport, error) { return d.tag, nil }
+func (d *tagDevice) Close() error                      { return nil }
+
+// newTagApp App com a tag presente num leitor emulado; a sessão fecha no
+// fim do teste
+func newTagApp(t *testing.T, tag rfid.Transport) *App {
+	t.Helper()
+	a := NewApp()
+	session := rfid.NewSession(&tagDevice{tag: tag, wake: make(chan struct{}, 1)})
+	t.Cleanup(func() { session.Close() })
+	a.setSession(session)
+	return a
+}
+
+func TestOpenSpoolReadWrite(t *testing.T) {
+	tag, err := rfid.NewEmulatedNTAG("04A1B2C3D4E5F6", rfid.NTAG215)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newTagApp(t, tag)
+
+	blank, err := a.ReadTag()
+	if err != nil {
+		t.Fatalf("ReadTag na NTAG virgem: %v", err)
+	}
+	if blank.Format != formatOpenSpool || !blank.IsBlank {
+		t.Errorf("NTAG virgem = %+v", blank)
+	}
+
+	req := WriteRequest{Supplier: "0276", Material: "06002", Color: "77BB41", Length: "0330", MaxTemp: 255}
+	if _, err := a.WriteTag(req); err != nil {
+		t.Fatalf("WriteTag na NTAG: %v", err)
+	}
+	data, err := a.ReadTag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  string
+		esperado string
+	}{
+		{"Format", data.Format, formatOpenSpool},
+		{"Material", data.MaterialName, "PETG"},
+		{"Código", data.MaterialCode, "00003"},
+		{"Marca", data.SupplierName, "Creality"},
+		{"Cor", data.Color, "77BB41"},
+		{"MinTemp", fmt.Sprint(data.MinTemp), "220"},
+		{"MaxTemp", fmt.Sprint(data.MaxTemp), "255"},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %q, esperado %q", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+}
+
+func TestReadOpenTag3D(t *testing.T) {
+	tag, _ := rfid.NewEmulatedNTAG("04A1B2C3D4E5F6", rfid.NTAG215)
+	msg, err := opentag3d.Encode(opentag3d.Tag{Material: "PETG", Modifiers: "CF", Manufacturer: "Polymaker",
+		Colors: [4]opentag3d.RGBA{{0x12, 0x34, 0x56, 0xFF}}, Weight: 500, PrintTemp: 240})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rfid.NewReader(tag).WriteNDEF(msg); err != nil {
+		t.Fatal(err)
+	}
+	a := newTagApp(t, tag)
+
+	data, err := a.ReadTag()
+	if err != nil {
+		t.Fatalf("ReadTag OpenTag3D: %v", err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  string
+		esperado string
+	}{
+		{"Format", data.Format, formatOpenTag3D},
+		{"Material", data.MaterialName, "PETG-CF"},
+		{"Código", data.MaterialCode, "00014"},
+		{"Marca", data.SupplierName, "Polymaker"},
+		{"Cor", data.Color, "123456"},
+		{"Comprimento", data.LengthCode, "0165"},
+		{"Temperatura", fmt.Sprint(data.MaxTemp), "240"},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %q, esperado %q", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+}
+
+func TestReadBambu(t *testing.T) {
+	card, _ := rfid.NewEmulatedCard("A1B2C3D4")
+	keys, err := bambu.Keys("A1B2C3D4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for s, k := range keys {
+		key, _ := hex.DecodeString(k)
+		card.SetBlock(s*4+3, access.Trailer(key, access.Transport, key))
+	}
+	text := func(s string) []byte {
+		b := make([]byte, 16)
+		copy(b, s)
+		return b
+	}
+	b1 := text("A01-K1")
+	copy(b1[8:], "GFA01")
+	card.SetBlock(1, b1)
+	card.SetBlock(2, text("PLA"))
+	card.SetBlock(4, text("PLA Matte"))
+	card.SetBlock(5, []byte{0x11, 0x22, 0x33, 0xFF, 0xE8, 0x03})
+	card.SetBlock(6, []byte{55, 0, 8, 0, 1, 0, 60, 0, 230, 0, 190, 0})
+	card.SetBlock(12, text("2024_03_11_09_24"))
+
+	a := newTagApp(t, card)
+
+	data, err := a.ReadTag()
+	if err != nil {
+		t.Fatalf("ReadTag Bambu: %v", err)
+	}
+	conv, err := a.ConvertBambuTag()
+	if err != nil {
+		t.Fatalf("ConvertBambuTag: %v", err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  string
+		esperado string
+	}{
+		{"Format", data.Format, formatBambu},
+		{"Material", data.MaterialName, "PLA Matte"},
+		{"Código", data.MaterialCode, "14001"},
+		{"Fornecedor", data.SupplierCode, "0276"},
+		{"Cor", data.Color, "112233"},
+		{"Comprimento", data.LengthCode, "0330"},
+		{"Data", data.Date, "2024-03-11"},
+		{"MaxTemp", fmt.Sprint(data.MaxTemp), "230"},
+		{"Pedido", fmt.Sprintf("%+v", conv.Request), fmt.Sprintf("%+v", WriteRequest{Date: "2024-03-11",
+			Supplier: "0276", Material: "14001", Color: "112233", Length: "0330", Serial: "000001", MinTemp: 190, MaxTemp: 230})},
+		{"ID Bambu", conv.MaterialID, "GFA01"},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %q, esperado %q", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+}
+
+func TestTigerTagReadWrite(t *testing.T) {
+	tag, _ := rfid.NewEmulatedNTAG("04A1B2C3D4E5F6", rfid.NTAG213)
+	tag.SetPage(4, []byte{0xBC, 0x0F, 0xCB, 0x97}) // TigerTag sem dados
+	a := newTagApp(t, tag)
+	dir := t.TempDir()
+	a.cfgPath = filepath.Join(dir, "config.json")
+
+	blank, err := a.ReadTag()
+	if err != nil {
+		t.Fatalf("ReadTag TigerTag virgem: %v", err)
+	}
+	if blank.Format != formatTigerTag || !blank.IsBlank {
+		t.Errorf("TigerTag virgem = %+v", blank)
+	}
+
+	// Sem tigertag.json vale o catálogo embutido
+	req := WriteRequest{Date: "2024-03-11", Supplier: "ESUN", Material: "E1001", Color: "77BB41", Length: "0330"}
+	if _, err := a.WriteTag(req); err != nil {
+		t.Fatalf("WriteTag com o catálogo embutido: %v", err)
+	}
+	if data, err := a.ReadTag(); err != nil || data.MaterialName != "PLA" || data.SupplierName != "eSUN" {
+		t.Errorf("ReadTag com o catálogo embutido = %+v, %v", data, err)
+	}
+	catalog := `{"material": [{"id": 100, "label": "PLA+"}], "brand": [{"id": 11, "label": "eSUN"}],
+		"diameter": [{"id": 1, "label": "1.75"}], "unit": [{"id": 5, "label": "g"}]}`
+	if err := os.WriteFile(filepath.Join(dir, tigerCatalogFile), []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.WriteTag(req); err != nil {
+		t.Fatalf("WriteTag TigerTag: %v", err)
+	}
+	data, err := a.ReadTag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  string
+		esperado string
+	}{
+		{"Format", data.Format, formatTigerTag},
+		{"Material", data.MaterialName, "PLA+"},
+		{"Código", data.MaterialCode, "E1001"},
+		{"Fornecedor", data.SupplierCode, "ESUN"},
+		{"Marca", data.SupplierName, "eSUN"},
+		{"Cor", data.Color, "77BB41"},
+		{"Comprimento", data.LengthCode, "0330"},
+		{"Data", data.Date, "2024-03-11"},
+		{"MaxTemp", fmt.Sprint(data.MaxTemp), "220"},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %q, esperado %q", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+}
+
+func TestACEReadWrite(t *testing.T) {
+	tag, _ := rfid.NewEmulatedNTAG("04A1B2C3D4E5F6", rfid.NTAG213)
+	a := newTagApp(t, tag)
+
+	req := WriteRequest{Supplier: "0276", Material: "06002", Color: "77BB41", Length: "0330", Format: formatACE}
+	if _, err := a.WriteTag(req); err != nil {
+		t.Fatalf("WriteTag Anycubic: %v", err)
+	}
+	data, err := a.ReadTag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  string
+		esperado string
+	}{
+		{"Format", data.Format, formatACE},
+		{"Material", data.MaterialName, "PETG"},
+		{"Código", data.MaterialCode, "00003"},
+		{"Marca", data.SupplierName, "Creality"},
+		{"Cor", data.Color, "77BB41"},
+		{"Comprimento", data.LengthCode, "0330"},
+		{"MinTemp", fmt.Sprint(data.MinTemp), "220"},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %q, esperado %q", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+
+	req.Format = "elegoo"
+	if _, err := a.WriteTag(req); err == nil {
+		t.Error("formato desconhecido deveria ser recusado")
+	}
+}
+
+func TestACERefusesClassic(t *testing.T) {
+	card, _ := rfid.NewEmulatedCard("A1B2C3D4")
+	a := newTagApp(t, card)
+
+	req := WriteRequest{Supplier: "0276", Material: "06002", Color: "77BB41", Length: "0330", Format: formatACE}
+	if _, err := a.WriteTag(req); !errors.Is(err, rfid.ErrUnsupportedCard) {
+		t.Errorf("Anycubic em MIFARE Classic: %v", err)
+	}
+	if card.Block(4)[0] != 0 {
+		t.Errorf("bloco 4 alterado: % X", card.Block(4))
+	}
+}
+
+func TestWriteChosenFormat(t *testing.T) {
+	tag, _ := rfid.NewEmulatedNTAG("04A1B2C3D4E5F6", rfid.NTAG215)
+	a := newTagApp(t, tag)
+
+	req := WriteRequest{Supplier: "0276", Material: "06002", Color: "77BB41", Length: "0165", MaxTemp: 240, Format: formatOpenTag3D}
+	if _, err := a.WriteTag(req); err != nil {
+		t.Fatalf("WriteTag OpenTag3D: %v", err)
+	}
+	data, err := a.ReadTag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Format != formatOpenTag3D || data.Color != "77BB41" || data.MaxTemp != 240 {
+		t.Errorf("releitura = %+v", data)
+	}
+
+	req.Format = formatBambu
+	if _, err := a.WriteTag(req); err == nil {
+		t.Error("formato só de leitura deveria ser recusado")
+	}
+	var writable []string
+	for _, f := range a.GetTagFormats() {
+		if f.Writable {
+			writable = append(writable, f.Name)
+		}
+	}
+	if got := strings.Join(writable, ","); got != "cfs,tigertag,ace,openspool,opentag3d" {
+		t.Errorf("formatos graváveis = %s", got)
+	}
+}
+
+func TestSpoolmanSync(t *testing.T) {
+	fake := spoolmantest.NewFake()
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	card, _ := rfid.NewEmulatedCard("A1B2C3D4")
+	a := newTagApp(t, card)
+	if res, err := a.spoolmanSync("A1B2C3D4", nil); res != nil || err != nil {
+		t.Fatalf("Spoolman desligado: %+v, %v", res, err)
+	}
+	if err := a.SetSpoolmanSettings(SpoolmanSettings{URL: "spoolman.local"}); err == nil {
+		t.Error("endereço sem esquema deveria ser recusado")
+	}
+	if err := a.SetSpoolmanSettings(SpoolmanSettings{URL: srv.URL, Timeout: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.WriteTag(WriteRequest{Supplier: "0276", Material: "04001", Color: "77BB41", Length: "0330", Serial: "000001"}); err != nil {
+		t.Fatal(err)
+	}
+	_, spool, err := a.readTag()
+	if err != nil || spool == nil {
+		t.Fatalf("readTag: %v", err)
+	}
+
+	first, err := a.spoolmanSync("A1B2C3D4", spool)
+	if err != nil || !first.Created {
+		t.Fatalf("primeira leitura: %+v, %v", first, err)
+	}
+	again, err := a.spoolmanSync("A1B2C3D4", spool)
+	if err != nil || again.Created || again.SpoolID != first.SpoolID {
+		t.Errorf("segunda leitura: %+v, %v", again, err)
+	}
+
+	// Gravação escolhendo a bobina no Spoolman
+	req, err := a.SpoolmanRequest(first.SpoolID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  any
+		esperado any
+	}{
+		{"material", req.Material, "04001"},
+		{"cor", req.Color, "77BB41"},
+		{"comprimento", req.Length, "0330"},
+		{"bobina", req.SpoolmanID, first.SpoolID},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %v, esperado %v", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+
+	if _, err := a.spoolmanLink("04A1B2C3D4E5F6", first.SpoolID); err != nil {
+		t.Fatal(err)
+	}
+	list, err := a.ListSpoolmanSpools()
+	if err != nil || len(list) != 1 || list[0].UID != "04A1B2C3D4E5F6" || list[0].Label != "Creality CR-PLA" {
+		t.Errorf("ListSpoolmanSpools = %+v, %v", list, err)
+	}
+}
+
+func TestActivateSpool(t *testing.T) {
+	fake := moonrakertest.NewFake()
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	card, _ := rfid.NewEmulatedCard("A1B2C3D4")
+	a := newTagApp(t, card)
+	if _, err := a.WriteTag(WriteRequest{Supplier: "0276", Material: "04001", Color: "77BB41", Length: "0330", Serial: "000001"}); err != nil {
+		t.Fatal(err)
+	}
+	_, spool, err := a.readTag()
+	if err != nil || spool == nil {
+		t.Fatalf("readTag: %v", err)
+	}
+
+	if act, err := a.activateSpool(3, spool); act != nil || err != nil {
+		t.Fatalf("sem impressora: %+v, %v", act, err)
+	}
+	invalidas := [][]PrinterSettings{
+		{{Name: "K1", URL: "k1.local:7125"}},
+		{{Name: "K1", URL: srv.URL}, {Name: "K1", URL: srv.URL}},
+		{{Name: "K1", URL: srv.URL, Mode: "mqtt"}},
+		{{Name: "K1", URL: srv.URL, Tool: "T1"}},
+	}
+	for _, list := range invalidas {
+		if err := a.SetPrinters(list); err == nil {
+			t.Errorf("SetPrinters(%+v) deveria falhar", list)
+		}
+	}
+	if err := a.SetPrinters([]PrinterSettings{{Name: "K1", URL: srv.URL, Mode: "gcode", Tool: "T1", Macro: "SET_FILAMENT"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SelectPrinter("K2"); err == nil {
+		t.Error("impressora não configurada deveria ser recusada")
+	}
+	if err := a.SelectPrinter("K1"); err != nil {
+		t.Fatal(err)
+	}
+
+	act, err := a.activateSpool(3, spool)
+	if err != nil || act == nil || act.Printer != "K1" || act.SpoolID != 3 {
+		t.Fatalf("activateSpool = %+v, %v", act, err)
+	}
+	want := "SET_GCODE_VARIABLE MACRO=T1 VARIABLE=spool_id VALUE=3\nSET_FILAMENT TYPE=PLA COLOR=77BB41 TOOL=T1 SPOOL=3"
+	if got := fake.Scripts(); len(got) != 1 || got[0] != want {
+		t.Errorf("scripts = %q, esperado %q", got, want)
+	}
+
+	// Sem Spoolman e sem macro não há nada a enviar
+	if err := a.SetPrinters([]PrinterSettings{{Name: "K1", URL: srv.URL}}); err != nil {
+		t.Fatal(err)
+	}
+	if act, err := a.activateSpool(0, spool); act != nil || err != nil {
+		t.Errorf("sem bobina nem macro: %+v, %v", act, err)
+	}
+	if got := fake.Scripts(); len(got) != 1 {
+		t.Errorf("scripts = %q, esperado só o anterior", got)
+	}
+
+	// Impressora removida deixa de receber as bobinas
+	if err := a.SetPrinters(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.GetSelectedPrinter(); got != "" {
+		t.Errorf("impressora escolhida = %q, esperado vazio", got)
+	}
+}
+
+// lostWrite cartão que confirma a escrita de block sem gravá-la
+type lostWrite struct {
+	*rfid.EmulatedCard
+	block byte
+}
+
+func (c lostWrite) Transmit(cmd []byte) ([]byte, error) {
+	if len(cmd) == 21 && cmd[1] == 0xD6 && cmd[3] == c.block {
+		return []byte{0x90, 0x00}, nil
+	}
+	return c.EmulatedCard.Transmit(cmd)
+}
+
+func TestWriteTagMismatch(t *testing.T) {
+	card, _ := rfid.NewEmulatedCard("A1B2C3D4")
+	a := newTagApp(t, lostWrite{card, 5})
+
+	// Divergência é erro, e a verificação vem junto com os campos
+	v, err := a.WriteTag(WriteRequest{Supplier: "0276", Material: "04001", Color: "77BB41", Length: "0330", Serial: "000001"})
+	if !errors.Is(err, rfid.ErrVerifyFailed) {
+		t.Fatalf("WriteTag com divergência: erro = %v, esperado ErrVerifyFailed", err)
+	}
+	if v == nil || v.OK() {
+		t.Errorf("verificação = %+v, esperado campos divergentes", v)
+	}
+}
+
+func TestBeginOpOverlap(t *testing.T) {
+	a := NewApp()
+	first, doneFirst := a.beginOp(time.Minute)
+	second, doneSecond := a.beginOp(time.Minute)
+	defer doneSecond()
+
+	// O fim da primeira não tira o cancelamento da segunda
+	doneFirst()
+	if first.Err() == nil {
+		t.Error("contexto da primeira operação deveria ter terminado")
+	}
+	if !a.CancelOperation() {
+		t.Fatal("CancelOperation não achou a segunda operação")
+	}
+	if !errors.Is(second.Err(), context.Canceled) {
+		t.Errorf("segunda operação: %v, esperado context.Canceled", second.Err())
+	}
+	doneSecond()
+	if a.CancelOperation() {
+		t.Error("CancelOperation sem operação em andamento deveria retornar false")
+	}
+}
+
+func TestResolvePendingWithoutOriginal(t *testing.T) {
+	a := NewApp()
+	tx := &rfid.WriteTx{UID: "A1B2C3D4", Blocks: make([]string, 3), NewTag: true, Done: 1}
+	a.pending = &pendingWrite{tx: tx}
+
+	if p := a.GetPendingWrite(); p == nil || p.CanRollback {
+		t.Fatalf("GetPendingWrite = %+v, esperado sem desfazer", p)
+	}
+	if err := a.ResolvePendingWrite(true); err == nil {
+		t.Error("desfazer sem cópia dos blocos originais deveria falhar")
+	}
+	if p := a.GetPendingWrite(); p == nil || p.Rollback {
+		t.Errorf("a escolha não deveria mudar: %+v", p)
+	}
+
+	tx.Original = make([]string, 3)
+	if p := a.GetPendingWrite(); !p.CanRollback {
+		t.Errorf("com cópia dos blocos originais: %+v", p)
+	}
+}
+
+func TestConvertLength(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado string
+	}{
+		{"0330", "0330"},
+		{"1000", "1000"},
+		{"250", "0053"},
+		{"500", "00A5"},
+		{"300", "0064"},
+		{"", "0053"},
+	}
+	for _, tt := range testes {
+		if got := convertLength(tt.entrada); got != tt.esperado {
+			t.Errorf("convertLength(%q) = %q, esperado %q", tt.entrada, got, tt.esperado)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/tigertag"
+)
+
+// tigerCatalogFile catálogo de IDs TigerTag, ao lado do config.json
+const tigerCatalogFile = "tigertag.json"
+
+// tigerCatalog catálogo TigerTag da pasta de config; sem o arquivo, o
+// catálogo embutido
+func (a *App) tigerCatalog() (*tigertag.Catalog, error) {
+	if a.cfgPath == "" {
+		return tigertag.DefaultCatalog(), nil
+	}
+	f, err := os.Open(filepath.Join(filepath.Dir(a.cfgPath), tigerCatalogFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return tigertag.DefaultCatalog(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return tigertag.LoadCatalog(f)
+}
+
+// materialCodeForName código do material do app com o nome dado, com ou
+// sem a marca na frente ("eSUN" + "PLA+" → "eSUN PLA+"); sem nome igual,
+// o material genérico do tipo
+func materialCodeForName(brand, name string) string {
+	if name == "" {
+		return ""
+	}
+	for _, m := range materials {
+		if strings.EqualFold(m.Name, name) || strings.EqualFold(m.Name, brand+" "+name) {
+			return m.Code
+		}
+	}
+	return creality.GenericCode(name)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// PendingWrite gravação interrompida pela remoção da tag, aguardando a
+// mesma tag voltar ao leitor
+type PendingWrite struct {
+	UID      string `json:"uid"`
+	Step     int    `json:"step"`     // passo interrompido (1..Steps)
+	Steps    int    `json:"steps"`    // blocos 4–6 e trailer em tag nova
+	Rollback bool   `json:"rollback"` // desfazer em vez de concluir
+	// CanRollback há cópia dos blocos originais para desfazer
+	CanRollback bool `json:"canRollback"`
+}
+
+// pendingWrite transação interrompida e os campos para a verificação final
+type pendingWrite struct {
+	tx       *rfid.WriteTx
+	fields   creality.Fields
+	rollback bool
+}
+
+func (p *pendingWrite) info() *PendingWrite {
+	return &PendingWrite{UID: p.tx.UID, Step: p.tx.Done + 1, Steps: p.tx.Steps(), Rollback: p.rollback,
+		CanRollback: len(p.tx.Original) == 3}
+}
+
+// interruptWrite guarda a transação interrompida e avisa a UI
+func (a *App) interruptWrite(ie *rfid.WriteInterruptedError, fields creality.Fields) {
+	a.txMu.Lock()
+	if a.pending == nil || a.pending.tx != ie.Tx {
+		a.pending = &pendingWrite{tx: ie.Tx, fields: fields}
+	}
+	info := a.pending.info()
+	a.txMu.Unlock()
+
+	slog.Warn("gravação interrompida: tag removida", "uid", info.UID, "step", info.Step, "steps", info.Steps)
+	wailsRuntime.EventsEmit(a.ctx, "write:interrupted", info)
+}
+
+// GetPendingWrite retorna a gravação interrompida (nil se não houver)
+func (a *App) GetPendingWrite() *PendingWrite {
+	a.txMu.Lock()
+	defer a.txMu.Unlock()
+	if a.pending == nil {
+		return nil
+	}
+	return a.pending.info()
+}
+
+// ResolvePendingWrite escolhe concluir (rollback=false) ou desfazer a
+// gravação interrompida e tenta agora; sem a tag no leitor, a escolha é
+// aplicada quando a mesma tag voltar. Sem cópia dos blocos originais só
+// dá para concluir: o pedido de desfazer é recusado e a escolha não muda.
+func (a *App) ResolvePendingWrite(rollback bool) error {
+	a.txMu.Lock()
+	if a.pending == nil {
+		a.txMu.Unlock()
+		return fmt.Errorf("Nenhuma gravação pendente")
+	}
+	if rollback && !a.pending.info().CanRollback {
+		a.txMu.Unlock()
+		return fmt.Errorf("Sem cópia dos blocos originais: a gravação só pode ser concluída")
+	}
+	a.pending.rollback = rollback
+	a.txMu.Unlock()
+
+	defer a.rescanTag()
+	_, err := a.resolvePendingWrite()
+	return err
+}
+
+// DiscardPendingWrite esquece a gravação interrompida sem tocar na tag
+func (a *App) DiscardPendingWrite() {
+	a.txMu.Lock()
+	defer a.txMu.Unlock()
+	if a.pending != nil {
+		slog.Info("gravação pendente descartada", "uid", a.pending.tx.UID)
+	}
+	a.pending = nil
+}
+
+// resolvePendingWrite conclui ou desfaz a gravação pendente na tag do
+// leitor. Tag diferente ou nova remoção mantêm a gravação pendente. Campos
+// divergentes na releitura vêm na verificação, com erro ErrVerifyFailed.
+func (a *App) resolvePendingWrite() (*rfid.WriteVerification, error) {
+	a.txMu.Lock()
+	p := a.pending
+	a.txMu.Unlock()
+	if p == nil {
+		return nil, nil
+	}
+
+	ctx, done := a.beginOp(writeTimeout)
+	defer done()
+	reader, err := a.openReader(ctx, "resume")
+	if err != nil {
+		return nil, wrapReaderError("Erro ao conectar leitor", err)
+	}
+	defer reader.Close()
+	signal := rfid.SignalError
+	defer func() { a.signal(reader, signal) }()
+
+	var verification *rfid.WriteVerification
+	if p.rollback {
+		err = reader.RollbackWriteCFS(p.tx)
+	} else if err = reader.ResumeWriteCFS(p.tx); err == nil {
+		verification, err = reader.VerifyTagCFS(p.tx.UID, p.fields)
+	}
+
+	var ie *rfid.WriteInterruptedError
+	switch {
+	case errors.As(err, &ie):
+		a.interruptWrite(ie, p.fields)
+		return nil, wrapReaderError("Gravação interrompida de novo", err)
+	case errors.Is(err, rfid.ErrWrongTag):
+		err = wrapReaderError("Tag errada", err)
+		wailsRuntime.EventsEmit(a.ctx, "write:wrong_tag", err.Error())
+		return nil, err
+	case err != nil && verification == nil:
+		return nil, wrapReaderError("Erro ao retomar gravação", err)
+	}
+
+	a.txMu.Lock()
+	if a.pending == p {
+		a.pending = nil
+	}
+	a.txMu.Unlock()
+
+	if p.rollback {
+		signal = rfid.SignalWrite
+		slog.Info("gravação interrompida desfeita", "uid", p.tx.UID)
+		wailsRuntime.EventsEmit(a.ctx, "write:rolledback", p.tx.UID)
+		return nil, nil
+	}
+	if err != nil && verification.OK() {
+		// Releitura falhou sem campos para mostrar
+		return nil, wrapReaderError("Erro na verificação", err)
+	}
+	// Campos divergentes vão no evento, para a UI mostrar
+	wailsRuntime.EventsEmit(a.ctx, "write:resumed", verification)
+	if err != nil {
+		slog.Warn("gravação retomada com campos divergentes", "uid", p.tx.UID, "mismatches", verification.Mismatches)
+		return verification, wrapReaderError("Erro na verificação", err)
+	}
+	signal = rfid.SignalWrite
+	slog.Info("gravação interrompida concluída", "uid", p.tx.UID)
+	return verification, nil
+}
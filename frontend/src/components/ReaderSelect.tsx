@@ -0,0 +1,42 @@
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select";
+import { Label } from "@/components/ui/label";
+
+// Valor sentinela: Radix Select não aceita string vazia como item
+const AUTO = "__auto__";
+const PN532_PREFIX = "pn532:";
+const REPLAY_PREFIX = "replay:";
+
+// PN532 via UART aparece como "pn532:<porta>"; gravações de APDUs como "replay:<arquivo>"
+function readerLabel(name: string) {
+  if (name.startsWith(PN532_PREFIX)) return `PN532 (UART) ${name.slice(PN532_PREFIX.length)}`;
+  if (name.startsWith(REPLAY_PREFIX)) return `Gravação ${name.slice(REPLAY_PREFIX.length)}`;
+  return name;
+}
+
+interface ReaderSelectProps {
+  reader: string;
+  readers: string[];
+  onReaderChange: (value: string) => void;
+}
+
+export function ReaderSelect({ reader, readers, onReaderChange }: ReaderSelectProps) {
+  // Mantém o leitor salvo visível mesmo quando desconectado
+  const items = reader && !readers.includes(reader) ? [reader, ...readers] : readers;
+  return (
+    <div className="space-y-1.5">
+      <Label className="text-xs font-medium text-muted-foreground">Leitor</Label>
+      <Select
+        value={reader || AUTO}
+        onValueChange={(v) => onReaderChange(v === AUTO ? "" : v)}
+      >
+        <SelectTrigger><SelectValue placeholder="Selecione..." /></SelectTrigger>
+        <SelectContent>
+          <SelectItem value={AUTO}>Automático</SelectItem>
+          {items.map((r) => (
+            <SelectItem key={r} value={r}>{readerLabel(r)}</SelectItem>
+          ))}
+        </SelectContent>
+      </Select>
+    </div>
+  );
+}
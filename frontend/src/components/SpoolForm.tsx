@@ -1,4 +1,4 @@
-import { useEffect, useState } from "react";
+import { useEffect, useRef, useState } from "react";
 import { Card, CardContent } from "@/components/ui/card";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
@@ -6,18 +6,26 @@ import { Label } from "@/components/ui/label";
 import { ColorPicker } from "@/components/ColorPicker";
 import { MaterialSelect } from "@/components/MaterialSelect";
 import { LengthSelect } from "@/components/LengthSelect";
+import { ReaderSelect } from "@/components/ReaderSelect";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select";
 import { toast } from "sonner";
-import { WriteTag, GetOptions, GetVersion } from "../../wailsjs/go/main/App";
+import { WriteTag, ResetTag, ConvertBambuTag, CancelOperation, ResolvePendingWrite, GetOptions, GetTagFormats, GetVersion, ListSpoolmanSpools, SpoolmanRequest, GetPrinters, GetSelectedPrinter, SelectPrinter, ListReaders, GetSelectedReader, SelectReader } from "../../wailsjs/go/main/App";
 import { EventsOn } from "../../wailsjs/runtime/runtime";
 import { Header } from "@/components/Header";
-import { Save } from "lucide-react";
-import type { OptionsResponse } from "@/types/spool";
+import { RotateCcw, Save, X } from "lucide-react";
+import type { FormatInfo, OptionsResponse, SpoolmanSpool, TagFormat, WriteTarget } from "@/types/spool";
 
-type TagStatus = "waiting" | "read" | "error";
+type TagStatus = "waiting" | "read" | "no_reader" | "error";
+
+// Campos divergentes da releitura: "Serial: esperado 000002, lido 000001"
+const mismatchText = (v: any) =>
+  (v.mismatches || []).map((m: any) => `${m.field}: esperado ${m.expected}, lido ${m.actual}`).join("; ");
 
 export function SpoolForm() {
   const [options, setOptions] = useState<OptionsResponse>({ materials: [], vendors: [], lengths: [] });
   const [version, setVersion] = useState("");
+  const [readers, setReaders] = useState<string[]>([]);
+  const [reader, setReader] = useState("");
 
   // Campos do formulario
   const [date, setDate] = useState(new Date().toISOString().split("T")[0]);
@@ -27,18 +35,65 @@ export function SpoolForm() {
   const [length, setLength] = useState("0330");
   const [customGrams, setCustomGrams] = useState("");
   const [serial, setSerial] = useState("000001");
+  // Tags NTAG (gravadas como OpenSpool): faixa de temperatura; vazio = padrão do material
+  const [minTemp, setMinTemp] = useState("");
+  const [maxTemp, setMaxTemp] = useState("");
 
   // Estado
   const [uid, setUid] = useState("");
+  const [format, setFormat] = useState<TagFormat>("cfs");
+  const [target, setTarget] = useState<WriteTarget>("cfs");
+  const [targets, setTargets] = useState<FormatInfo[]>([]);
   const [tagStatus, setTagStatus] = useState<TagStatus>("waiting");
   const [isWriting, setIsWriting] = useState(false);
+  // write:mismatch chega antes do erro de WriteTag: evita o toast duplicado
+  const mismatchShown = useRef(false);
   const [writeCount, setWriteCount] = useState(0);
+  // Spoolman: bobinas para preencher a gravação; a escolhida recebe o UID da tag gravada
+  const [spools, setSpools] = useState<SpoolmanSpool[]>([]);
+  const [spoolmanId, setSpoolmanId] = useState(0);
+  // Impressora Klipper que recebe a bobina lida ("" = nenhuma)
+  const [printers, setPrinters] = useState<string[]>([]);
+  const [printer, setPrinter] = useState("");
 
   useEffect(() => {
     GetOptions().then(setOptions).catch(() => toast.error("Erro ao carregar opcoes"));
+    GetTagFormats().then((list) => setTargets((list as FormatInfo[]).filter((f) => f.writable)));
     GetVersion().then(setVersion);
+    GetSelectedReader().then(setReader);
+    refreshReaders();
+    refreshSpools();
+    GetPrinters().then((list) => setPrinters((list || []).map((p) => p.name)));
+    GetSelectedPrinter().then(setPrinter);
   }, []);
 
+  const handlePrinterChange = async (value: string) => {
+    const name = value === "none" ? "" : value;
+    try {
+      await SelectPrinter(name);
+      setPrinter(name);
+    } catch (err: any) {
+      toast.error(err?.message || String(err));
+    }
+  };
+
+  const refreshSpools = () => {
+    ListSpoolmanSpools().then((list) => setSpools(list || [])).catch(() => setSpools([]));
+  };
+
+  const refreshReaders = () => {
+    ListReaders().then((list) => setReaders(list || [])).catch(() => setReaders([]));
+  };
+
+  const handleReaderChange = async (value: string) => {
+    try {
+      await SelectReader(value);
+      setReader(value);
+    } catch (err: any) {
+      toast.error(err?.message || String(err));
+    }
+  };
+
   // Escuta eventos do watcher de tags
   useEffect(() => {
     const offStatus = EventsOn("tag:status", (status: TagStatus) => {
@@ -48,7 +103,61 @@ export function SpoolForm() {
       setTagStatus("read");
       applyTagData(data);
     });
-    return () => { offStatus(); offRead(); };
+    const offRemoved = EventsOn("reader:removed", (name: string) => {
+      toast.error(`Leitor desconectado: ${name}`);
+      refreshReaders();
+    });
+    const offError = EventsOn("tag:error", (message: string) => {
+      toast.error(message);
+    });
+    // Gravação interrompida: aguarda a mesma tag voltar para concluir (ou
+    // desfazer, se houver cópia dos blocos originais)
+    const offInterrupted = EventsOn("write:interrupted", (p: any) => {
+      toast.warning(`Tag removida no passo ${p.step} de ${p.steps} — recoloque a tag UID ${p.uid}`, {
+        id: "write-pending",
+        duration: Infinity,
+        action: p.canRollback
+          ? { label: "Desfazer", onClick: () => ResolvePendingWrite(true).catch(() => {}) }
+          : undefined,
+      });
+    });
+    const offResumed = EventsOn("write:resumed", (v: any) => {
+      if (v?.mismatches?.length) {
+        toast.error(`Gravação concluída com divergências — ${mismatchText(v)}`, { id: "write-pending", duration: 8000 });
+        return;
+      }
+      toast.success(`Gravação concluída e verificada — UID: ${v?.uid ?? ""}`, { id: "write-pending", duration: 4000 });
+    });
+    // Releitura com campos divergentes: o erro de WriteTag não traz os campos
+    const offMismatch = EventsOn("write:mismatch", (v: any) => {
+      mismatchShown.current = true;
+      toast.error(`Verificação falhou — ${mismatchText(v)}`, { duration: 8000 });
+    });
+    const offRolledBack = EventsOn("write:rolledback", (uid: string) => {
+      toast.info(`Gravação desfeita — UID: ${uid}`, { id: "write-pending", duration: 4000 });
+    });
+    const offWrongTag = EventsOn("write:wrong_tag", (message: string) => {
+      toast.error(message);
+    });
+    const offSynced = EventsOn("spoolman:synced", (s: any) => {
+      toast.success(s.created ? `Bobina #${s.spoolId} criada no Spoolman` : `Spoolman: bobina #${s.spoolId}`, { id: "spoolman" });
+      refreshSpools();
+    });
+    const offSpoolmanError = EventsOn("spoolman:error", (message: string) => {
+      toast.error(message, { id: "spoolman" });
+    });
+    const offActive = EventsOn("moonraker:active", (p: any) => {
+      const spool = p.spoolId ? `Bobina #${p.spoolId}` : "Filamento";
+      toast.success(`${spool} ativa em ${p.printer}${p.tool ? ` (${p.tool})` : ""}`, { id: "moonraker" });
+    });
+    const offMoonrakerError = EventsOn("moonraker:error", (message: string) => {
+      toast.error(message, { id: "moonraker" });
+    });
+    return () => {
+      offStatus(); offRead(); offRemoved(); offError();
+      offInterrupted(); offResumed(); offMismatch(); offRolledBack(); offWrongTag();
+      offSynced(); offSpoolmanError(); offActive(); offMoonrakerError();
+    };
   }, []);
 
   const applyTagData = (data: any) => {
@@ -59,11 +168,66 @@ export function SpoolForm() {
     setColor(data.color || "000000");
     setLength(data.lengthCode || "0330");
     setSerial(data.serial || "000001");
+    setFormat(data.format || "cfs");
+    setTarget(data.format === "ace" ? "ace" : "cfs");
+    setMinTemp(data.minTemp ? String(data.minTemp) : "");
+    setMaxTemp(data.maxTemp ? String(data.maxTemp) : "");
     setWriteCount(0);
-    if (data.isBlank) {
-      toast.info(`Tag virgem — UID: ${data.uid}`);
+    setSpoolmanId(0);
+    const kind = ({ cfs: "CFS", openspool: "OpenSpool", opentag3d: "OpenTag3D", tigertag: "TigerTag", ace: "Anycubic", bambu: "Bambu Lab" } as Record<string, string>)[data.format] || "CFS";
+    if (data.format === "bambu") {
+      toast.info(`Tag Bambu Lab lida — UID: ${data.uid}. Converta para gravar numa tag CFS`);
+    } else if (data.isBlank) {
+      toast.info(`Tag ${kind} virgem — UID: ${data.uid}`);
     } else {
-      toast.success(`Tag lida — UID: ${data.uid}`);
+      toast.success(`Tag ${kind} lida — UID: ${data.uid}`);
+    }
+  };
+
+  // Tag Bambu Lab: preenche o formulário com o material Creality mais próximo
+  const handleConvertBambu = async () => {
+    try {
+      const conv = await ConvertBambuTag();
+      const req = conv.request;
+      setDate(req.date);
+      setSupplier(req.supplier);
+      setMaterial(req.material);
+      setColor(req.color);
+      setLength(req.length);
+      setSerial(req.serial);
+      setMinTemp(req.minTemp ? String(req.minTemp) : "");
+      setMaxTemp(req.maxTemp ? String(req.maxTemp) : "");
+      setFormat("cfs");
+      setTarget("cfs");
+      setWriteCount(0);
+      if (!req.material) {
+        toast.warning(`${conv.material} sem equivalente CFS — selecione o material`);
+        return;
+      }
+      toast.success(`${conv.material} convertido — coloque uma tag CFS virgem e grave`);
+    } catch (err: any) {
+      toast.error(err?.message || String(err));
+    }
+  };
+
+  // Bobina do Spoolman: preenche o formulário; a gravação liga a tag a ela
+  const handleSpoolmanChange = async (value: string) => {
+    const id = parseInt(value, 10) || 0;
+    setSpoolmanId(id);
+    if (!id) return;
+    try {
+      const req = await SpoolmanRequest(id);
+      setDate(req.date);
+      setSupplier(req.supplier);
+      setMaterial(req.material);
+      setColor(req.color);
+      setLength(req.length);
+      setSerial(req.serial);
+      setMaxTemp(req.maxTemp ? String(req.maxTemp) : "");
+      setWriteCount(0);
+    } catch (err: any) {
+      setSpoolmanId(0);
+      toast.error(err?.message || String(err));
     }
   };
 
@@ -83,16 +247,35 @@ export function SpoolForm() {
     if (!material) { toast.error("Selecione um material"); return; }
     if (color.length !== 6) { toast.error("Cor deve ter 6 caracteres hex"); return; }
     setIsWriting(true);
+    mismatchShown.current = false;
     try {
       const lengthValue = length === "CUSTOM" ? customGrams : length;
-      await WriteTag({ date, supplier, material, color, length: lengthValue, serial: serial || "000001" });
+      const verification = await WriteTag({
+        date, supplier, material, color, length: lengthValue, serial: serial || "000001",
+        minTemp: parseInt(minTemp, 10) || 0, maxTemp: parseInt(maxTemp, 10) || 0,
+        format: target, spoolmanId,
+      });
       const newCount = writeCount + 1;
       setWriteCount(newCount);
       if (newCount >= 2) {
         incrementSerial();
         setWriteCount(0);
       }
-      toast.success(`Tag gravada! (${newCount}/2)`);
+      toast.success(`Tag gravada e verificada — UID: ${verification.uid} (${newCount}/2)`);
+    } catch (err: any) {
+      // Divergência já mostrada campo a campo pelo write:mismatch
+      if (!mismatchShown.current) toast.error(err?.message || String(err));
+    } finally {
+      setIsWriting(false);
+    }
+  };
+
+  const handleReset = async () => {
+    if (!window.confirm("Apagar os dados da tag e voltar para a key de fábrica?")) return;
+    setIsWriting(true);
+    try {
+      await ResetTag();
+      toast.success("Tag resetada — pronta para reutilizar");
     } catch (err: any) {
       toast.error(err?.message || String(err));
     } finally {
@@ -108,6 +291,12 @@ export function SpoolForm() {
         <span className="text-xs font-medium text-amber-700">Aguardando tag no leitor...</span>
       </div>
     );
+    if (tagStatus === "no_reader") return (
+      <div className="flex items-center gap-2 px-5 py-2 bg-red-50 border-b border-red-200">
+        <div className="w-2 h-2 rounded-full bg-red-500" />
+        <span className="text-xs font-medium text-red-700">Nenhum leitor conectado</span>
+      </div>
+    );
     if (tagStatus === "read") return (
       <div className="flex items-center gap-2 px-5 py-2 bg-green-50 border-b border-green-200">
         <div className="w-2 h-2 rounded-full bg-green-500" />
@@ -130,6 +319,47 @@ export function SpoolForm() {
       <div className="flex-1 p-4 pb-24">
         <Card className="max-w-2xl mx-auto">
           <CardContent className="pt-5 space-y-4">
+            <div onPointerDown={refreshReaders}>
+              <ReaderSelect reader={reader} readers={readers} onReaderChange={handleReaderChange} />
+            </div>
+            {printers.length > 0 && (
+              <div className="space-y-1.5">
+                <Label className="text-xs font-medium text-muted-foreground">Impressora (bobina ativa)</Label>
+                <Select value={printer || "none"} onValueChange={handlePrinterChange}>
+                  <SelectTrigger><SelectValue /></SelectTrigger>
+                  <SelectContent>
+                    <SelectItem value="none">Nenhuma</SelectItem>
+                    {printers.map((name) => (
+                      <SelectItem key={name} value={name}>{name}</SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+            )}
+            {spools.length > 0 && (
+              <div className="space-y-1.5" onPointerDown={refreshSpools}>
+                <Label className="text-xs font-medium text-muted-foreground">Bobina do Spoolman</Label>
+                <Select value={String(spoolmanId)} onValueChange={handleSpoolmanChange}>
+                  <SelectTrigger><SelectValue /></SelectTrigger>
+                  <SelectContent>
+                    <SelectItem value="0">Nenhuma</SelectItem>
+                    {spools.map((s) => (
+                      <SelectItem key={s.id} value={String(s.id)}>
+                        #{s.id} {s.label || s.material}{s.remaining ? ` — ${Math.round(s.remaining)}g` : ""}{s.uid ? " (com tag)" : ""}
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+            )}
+            {format === "bambu" && (
+              <div className="flex items-center justify-between gap-3 rounded-md border border-sky-200 bg-sky-50 px-3 py-2">
+                <span className="text-xs text-sky-800">Tag Bambu Lab (somente leitura)</span>
+                <Button onClick={handleConvertBambu} disabled={isWriting} variant="outline" size="sm">
+                  Converter para CFS
+                </Button>
+              </div>
+            )}
             <MaterialSelect
               supplier={supplier}
               material={material}
@@ -166,17 +396,48 @@ export function SpoolForm() {
                 />
               </div>
             </div>
+            <div className="space-y-1.5">
+              <Label className="text-xs font-medium text-muted-foreground">Formato de gravação</Label>
+              <Select value={target} onValueChange={(v) => setTarget(v as WriteTarget)}>
+                <SelectTrigger><SelectValue /></SelectTrigger>
+                <SelectContent>
+                  {targets.map((f) => (
+                    <SelectItem key={f.name} value={f.name}>{f.label}</SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            {(format !== "cfs" || target !== "cfs") && (
+              <div className="grid grid-cols-2 gap-3">
+                <div className="space-y-1.5">
+                  <Label className="text-xs font-medium text-muted-foreground">Temp. mínima (°C)</Label>
+                  <Input type="number" value={minTemp} onChange={(e) => setMinTemp(e.target.value)} placeholder="padrão do material" />
+                </div>
+                <div className="space-y-1.5">
+                  <Label className="text-xs font-medium text-muted-foreground">Temp. máxima (°C)</Label>
+                  <Input type="number" value={maxTemp} onChange={(e) => setMaxTemp(e.target.value)} placeholder="padrão do material" />
+                </div>
+              </div>
+            )}
           </CardContent>
         </Card>
       </div>
 
       {/* Rodape fixo com botao Gravar */}
       <div className="fixed bottom-0 left-0 right-0 p-4 bg-background/95 backdrop-blur border-t">
-        <div className="max-w-2xl mx-auto">
-          <Button onClick={handleWrite} disabled={isWriting} className="w-full" size="lg">
+        <div className="max-w-2xl mx-auto flex gap-2">
+          <Button onClick={handleReset} disabled={isWriting} variant="outline" size="lg" title="Resetar tag">
+            <RotateCcw className="h-4 w-4" />
+          </Button>
+          <Button onClick={handleWrite} disabled={isWriting} className="flex-1" size="lg">
             <Save className="mr-2 h-4 w-4" />
             {isWriting ? "Gravando..." : "Gravar Tag"}
           </Button>
+          {isWriting && (
+            <Button onClick={() => CancelOperation()} variant="outline" size="lg" title="Cancelar gravação">
+              <X className="h-4 w-4" />
+            </Button>
+          )}
         </div>
       </div>
 
@@ -1,5 +1,19 @@
+export type TagFormat = "cfs" | "openspool" | "opentag3d" | "tigertag" | "ace" | "bambu";
+
+// Formato de destino em WriteTag: "cfs" segue o tipo da tag; os demais
+// exigem o cartão do formato (GetTagFormats)
+export type WriteTarget = Exclude<TagFormat, "bambu">;
+
+export interface FormatInfo {
+  name: TagFormat;
+  label: string;
+  card: string;
+  writable: boolean;
+}
+
 export interface TagData {
   uid: string;
+  format: TagFormat;
   date: string;
   dateDisplay: string;
   supplierCode: string;
@@ -10,6 +24,9 @@ export interface TagData {
   lengthCode: string;
   lengthDisplay: string;
   serial: string;
+  isBlank: boolean;
+  minTemp?: number;
+  maxTemp?: number;
 }
 
 export interface WriteRequest {
@@ -19,6 +36,20 @@ export interface WriteRequest {
   color: string;
   length: string;
   serial: string;
+  minTemp: number;
+  maxTemp: number;
+  format?: WriteTarget;
+  spoolmanId?: number;
+}
+
+// Bobina do Spoolman para preencher a gravação
+export interface SpoolmanSpool {
+  id: number;
+  label: string;
+  material: string;
+  color: string;
+  remaining: number;
+  uid: string;
 }
 
 export interface MaterialOption {
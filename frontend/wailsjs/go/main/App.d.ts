@@ -1,17 +1,94 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
+import {rfid} from '../models';
+
+export function ArmQueue():Promise<main.QueueState>;
+
+export function CancelOperation():Promise<boolean>;
+
+export function ClearQueue():Promise<void>;
+
+export function ConvertBambuTag():Promise<main.BambuConversion>;
+
+export function DisarmQueue():Promise<main.QueueState>;
+
+export function DiscardPendingWrite():Promise<void>;
+
+export function DumpTag(arg1:Array<string>):Promise<main.DumpResult>;
+
+export function GetAPDURecording():Promise<boolean>;
+
+export function GetFeedbackSettings():Promise<main.FeedbackSettings>;
+
+export function GetLogSettings():Promise<main.LogSettings>;
 
 export function GetOptions():Promise<main.OptionsResponse>;
 
+export function GetPendingWrite():Promise<main.PendingWrite>;
+
+export function GetPrinters():Promise<Array<main.PrinterSettings>>;
+
+export function GetQueue():Promise<main.QueueState>;
+
+export function GetSelectedPrinter():Promise<string>;
+
+export function GetSelectedReader():Promise<string>;
+
+export function GetSpoolmanSettings():Promise<main.SpoolmanSettings>;
+
+export function GetTagFormats():Promise<Array<main.FormatInfo>>;
+
 export function GetVersion():Promise<string>;
 
+export function IdentifyTag():Promise<rfid.CardInfo>;
+
+export function ListReaders():Promise<Array<string>>;
+
+export function ListSpoolmanSpools():Promise<Array<main.SpoolmanSpool>>;
+
+export function LoadQueueCSV(arg1:string):Promise<main.QueueState>;
+
 export function ReadTag():Promise<main.TagData>;
 
+export function ResetTag():Promise<void>;
+
+export function ResolvePendingWrite(arg1:boolean):Promise<void>;
+
+export function RestoreTag(arg1:string,arg2:Array<string>):Promise<rfid.RestoreReport>;
+
+export function RetryQueueItem(arg1:number):Promise<main.QueueState>;
+
+export function SaveQueueReport():Promise<string>;
+
+export function SelectDumpFile():Promise<string>;
+
+export function SelectPrinter(arg1:string):Promise<void>;
+
+export function SelectQueueFile():Promise<string>;
+
+export function SelectReader(arg1:string):Promise<void>;
+
+export function SetAPDURecording(arg1:boolean):Promise<string>;
+
+export function SetFeedbackSettings(arg1:main.FeedbackSettings):Promise<void>;
+
+export function SetLogSettings(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetPrinters(arg1:Array<main.PrinterSettings>):Promise<void>;
+
+export function SetQueue(arg1:Array<main.WriteRequest>):Promise<main.QueueState>;
+
+export function SetSpoolmanSettings(arg1:main.SpoolmanSettings):Promise<void>;
+
+export function SkipQueueItem(arg1:number):Promise<main.QueueState>;
+
+export function SpoolmanRequest(arg1:number):Promise<main.WriteRequest>;
+
 export function StartTagWatcher():Promise<void>;
 
 export function StopTagWatcher():Promise<void>;
 
 export function ValidateColor(arg1:string):Promise<string>;
 
-export function WriteTag(arg1:main.WriteRequest):Promise<void>;
+export function WriteTag(arg1:main.WriteRequest):Promise<rfid.WriteVerification>;
@@ -2,18 +2,170 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ArmQueue() {
+  return window['go']['main']['App']['ArmQueue']();
+}
+
+export function CancelOperation() {
+  return window['go']['main']['App']['CancelOperation']();
+}
+
+export function ClearQueue() {
+  return window['go']['main']['App']['ClearQueue']();
+}
+
+export function ConvertBambuTag() {
+  return window['go']['main']['App']['ConvertBambuTag']();
+}
+
+export function DisarmQueue() {
+  return window['go']['main']['App']['DisarmQueue']();
+}
+
+export function DiscardPendingWrite() {
+  return window['go']['main']['App']['DiscardPendingWrite']();
+}
+
+export function DumpTag(arg1) {
+  return window['go']['main']['App']['DumpTag'](arg1);
+}
+
+export function GetAPDURecording() {
+  return window['go']['main']['App']['GetAPDURecording']();
+}
+
+export function GetFeedbackSettings() {
+  return window['go']['main']['App']['GetFeedbackSettings']();
+}
+
+export function GetLogSettings() {
+  return window['go']['main']['App']['GetLogSettings']();
+}
+
 export function GetOptions() {
   return window['go']['main']['App']['GetOptions']();
 }
 
+export function GetPendingWrite() {
+  return window['go']['main']['App']['GetPendingWrite']();
+}
+
+export function GetPrinters() {
+  return window['go']['main']['App']['GetPrinters']();
+}
+
+export function GetQueue() {
+  return window['go']['main']['App']['GetQueue']();
+}
+
+export function GetSelectedPrinter() {
+  return window['go']['main']['App']['GetSelectedPrinter']();
+}
+
+export function GetSelectedReader() {
+  return window['go']['main']['App']['GetSelectedReader']();
+}
+
+export function GetSpoolmanSettings() {
+  return window['go']['main']['App']['GetSpoolmanSettings']();
+}
+
+export function GetTagFormats() {
+  return window['go']['main']['App']['GetTagFormats']();
+}
+
 export function GetVersion() {
   return window['go']['main']['App']['GetVersion']();
 }
 
+export function IdentifyTag() {
+  return window['go']['main']['App']['IdentifyTag']();
+}
+
+export function ListReaders() {
+  return window['go']['main']['App']['ListReaders']();
+}
+
+export function ListSpoolmanSpools() {
+  return window['go']['main']['App']['ListSpoolmanSpools']();
+}
+
+export function LoadQueueCSV(arg1) {
+  return window['go']['main']['App']['LoadQueueCSV'](arg1);
+}
+
 export function ReadTag() {
   return window['go']['main']['App']['ReadTag']();
 }
 
+export function ResetTag() {
+  return window['go']['main']['App']['ResetTag']();
+}
+
+export function ResolvePendingWrite(arg1) {
+  return window['go']['main']['App']['ResolvePendingWrite'](arg1);
+}
+
+export function RestoreTag(arg1, arg2) {
+  return window['go']['main']['App']['RestoreTag'](arg1, arg2);
+}
+
+export function RetryQueueItem(arg1) {
+  return window['go']['main']['App']['RetryQueueItem'](arg1);
+}
+
+export function SaveQueueReport() {
+  return window['go']['main']['App']['SaveQueueReport']();
+}
+
+export function SelectDumpFile() {
+  return window['go']['main']['App']['SelectDumpFile']();
+}
+
+export function SelectPrinter(arg1) {
+  return window['go']['main']['App']['SelectPrinter'](arg1);
+}
+
+export function SelectQueueFile() {
+  return window['go']['main']['App']['SelectQueueFile']();
+}
+
+export function SelectReader(arg1) {
+  return window['go']['main']['App']['SelectReader'](arg1);
+}
+
+export function SetAPDURecording(arg1) {
+  return window['go']['main']['App']['SetAPDURecording'](arg1);
+}
+
+export function SetFeedbackSettings(arg1) {
+  return window['go']['main']['App']['SetFeedbackSettings'](arg1);
+}
+
+export function SetLogSettings(arg1, arg2) {
+  return window['go']['main']['App']['SetLogSettings'](arg1, arg2);
+}
+
+export function SetPrinters(arg1) {
+  return window['go']['main']['App']['SetPrinters'](arg1);
+}
+
+export function SetQueue(arg1) {
+  return window['go']['main']['App']['SetQueue'](arg1);
+}
+
+export function SetSpoolmanSettings(arg1) {
+  return window['go']['main']['App']['SetSpoolmanSettings'](arg1);
+}
+
+export function SkipQueueItem(arg1) {
+  return window['go']['main']['App']['SkipQueueItem'](arg1);
+}
+
+export function SpoolmanRequest(arg1) {
+  return window['go']['main']['App']['SpoolmanRequest'](arg1);
+}
+
 export function StartTagWatcher() {
   return window['go']['main']['App']['StartTagWatcher']();
 }
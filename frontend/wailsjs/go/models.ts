@@ -1,3 +1,34 @@
+export namespace creality {
+	
+	export class Fields {
+	    Batch: string;
+	    Date: string;
+	    Supplier: string;
+	    Material: string;
+	    Color: string;
+	    Length: string;
+	    Serial: string;
+	    Reserve: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Fields(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Batch = source["Batch"];
+	        this.Date = source["Date"];
+	        this.Supplier = source["Supplier"];
+	        this.Material = source["Material"];
+	        this.Color = source["Color"];
+	        this.Length = source["Length"];
+	        this.Serial = source["Serial"];
+	        this.Reserve = source["Reserve"];
+	    }
+	}
+
+}
+
 export namespace main {
 	
 	export class LengthOption {
@@ -82,6 +113,7 @@ export namespace main {
 	}
 	export class TagData {
 	    uid: string;
+	    format: string;
 	    date: string;
 	    dateDisplay: string;
 	    supplierCode: string;
@@ -93,6 +125,8 @@ export namespace main {
 	    lengthDisplay: string;
 	    serial: string;
 	    isBlank: boolean;
+	    minTemp?: number;
+	    maxTemp?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new TagData(source);
@@ -101,6 +135,7 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.uid = source["uid"];
+	        this.format = source["format"];
 	        this.date = source["date"];
 	        this.dateDisplay = source["dateDisplay"];
 	        this.supplierCode = source["supplierCode"];
@@ -112,6 +147,8 @@ export namespace main {
 	        this.lengthDisplay = source["lengthDisplay"];
 	        this.serial = source["serial"];
 	        this.isBlank = source["isBlank"];
+	        this.minTemp = source["minTemp"];
+	        this.maxTemp = source["maxTemp"];
 	    }
 	}
 	
@@ -122,6 +159,9 @@ export namespace main {
 	    color: string;
 	    length: string;
 	    serial: string;
+	    minTemp: number;
+	    maxTemp: number;
+	    format?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new WriteRequest(source);
@@ -135,6 +175,530 @@ export namespace main {
 	        this.color = source["color"];
 	        this.length = source["length"];
 	        this.serial = source["serial"];
+	        this.minTemp = source["minTemp"];
+	        this.maxTemp = source["maxTemp"];
+	        this.format = source["format"];
+	        this.spoolmanId = source["spoolmanId"];
+	    }
+	}
+	export class DumpSector {
+	    sector: number;
+	    keyA: string;
+	    keyB: string;
+	    read: boolean;
+	    accessBits: string;
+	    access: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DumpSector(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sector = source["sector"];
+	        this.keyA = source["keyA"];
+	        this.keyB = source["keyB"];
+	        this.read = source["read"];
+	        this.accessBits = source["accessBits"];
+	        this.access = source["access"];
+	    }
+	}
+	export class DumpResult {
+	    uid: string;
+	    complete: boolean;
+	    sectors: DumpSector[];
+	    files: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DumpResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uid = source["uid"];
+	        this.complete = source["complete"];
+	        this.sectors = this.convertValues(source["sectors"], DumpSector);
+	        this.files = source["files"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+	export class LogSettings {
+	    level: string;
+	    trace: boolean;
+	    file: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.trace = source["trace"];
+	        this.file = source["file"];
+	    }
+	}
+
+	export class PendingWrite {
+	    uid: string;
+	    step: number;
+	    steps: number;
+	    rollback: boolean;
+	    canRollback: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PendingWrite(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uid = source["uid"];
+	        this.step = source["step"];
+	        this.steps = source["steps"];
+	        this.rollback = source["rollback"];
+	        this.canRollback = source["canRollback"];
+	    }
+	}
+
+	export class FeedbackSettings {
+	    buzzer: boolean;
+	    detectBeep: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FeedbackSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.buzzer = source["buzzer"];
+	        this.detectBeep = source["detectBeep"];
+	    }
+	}
+
+	export class QueueState {
+	    armed: boolean;
+	    items: queue.Item[];
+	    progress: queue.Progress;
+	    report: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.armed = source["armed"];
+	        this.items = this.convertValues(source["items"], queue.Item);
+	        this.progress = this.convertValues(source["progress"], queue.Progress);
+	        this.report = source["report"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace queue {
+	
+	export class Entry {
+	    date: string;
+	    supplier: string;
+	    material: string;
+	    color: string;
+	    length: string;
+	    serial: string;
+	    minTemp: number;
+	    maxTemp: number;
+	    format?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.supplier = source["supplier"];
+	        this.material = source["material"];
+	        this.color = source["color"];
+	        this.length = source["length"];
+	        this.serial = source["serial"];
+	        this.minTemp = source["minTemp"];
+	        this.maxTemp = source["maxTemp"];
+	        this.format = source["format"];
+	        this.spoolmanId = source["spoolmanId"];
+	    }
+	}
+
+	export class Item {
+	    entry: Entry;
+	    line: number;
+	    status: string;
+	    uid: string;
+	    attempts: number;
+	    error: string;
+	    at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Item(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = this.convertValues(source["entry"], Entry);
+	        this.line = source["line"];
+	        this.status = source["status"];
+	        this.uid = source["uid"];
+	        this.attempts = source["attempts"];
+	        this.error = source["error"];
+	        this.at = this.convertValues(source["at"], null);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+	export class Progress {
+	    total: number;
+	    done: number;
+	    failed: number;
+	    skipped: number;
+	    pending: number;
+	    current: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Progress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.done = source["done"];
+	        this.failed = source["failed"];
+	        this.skipped = source["skipped"];
+	        this.pending = source["pending"];
+	        this.current = source["current"];
+	    }
+	}
+
+	export class BambuConversion {
+	    uid: string;
+	    materialId: string;
+	    material: string;
+	    color: string;
+	    weight: number;
+	    diameter: number;
+	    bedTemp: number;
+	    produced: string;
+	    request: WriteRequest;
+	
+	    static createFrom(source: any = {}) {
+	        return new BambuConversion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uid = source["uid"];
+	        this.materialId = source["materialId"];
+	        this.material = source["material"];
+	        this.color = source["color"];
+	        this.weight = source["weight"];
+	        this.diameter = source["diameter"];
+	        this.bedTemp = source["bedTemp"];
+	        this.produced = source["produced"];
+	        this.request = this.convertValues(source["request"], WriteRequest);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+	export class FormatInfo {
+	    name: string;
+	    label: string;
+	    card: string;
+	    writable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.card = source["card"];
+	        this.writable = source["writable"];
+	    }
+	}
+
+	export class SpoolmanSettings {
+	    url: string;
+	    timeout?: number;
+	    field?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SpoolmanSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.timeout = source["timeout"];
+	        this.field = source["field"];
+	    }
+	}
+
+	export class SpoolmanSpool {
+	    id: number;
+	    label: string;
+	    material: string;
+	    color: string;
+	    remaining: number;
+	    uid: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SpoolmanSpool(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.material = source["material"];
+	        this.color = source["color"];
+	        this.remaining = source["remaining"];
+	        this.uid = source["uid"];
+	    }
+	}
+
+	export class PrinterSettings {
+	    name: string;
+	    url: string;
+	    apiKey?: string;
+	    mode?: string;
+	    tool?: string;
+	    macro?: string;
+	    timeout?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PrinterSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.apiKey = source["apiKey"];
+	        this.mode = source["mode"];
+	        this.tool = source["tool"];
+	        this.macro = source["macro"];
+	        this.timeout = source["timeout"];
+	    }
+	}
+
+}
+
+export namespace rfid {
+	
+	export class BlockIssue {
+	    block: number;
+	    reason: string;
+	    expected?: string;
+	    actual?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BlockIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.block = source["block"];
+	        this.reason = source["reason"];
+	        this.expected = source["expected"];
+	        this.actual = source["actual"];
+	    }
+	}
+	export class RestoreReport {
+	    uid: string;
+	    written: number[];
+	    skipped: BlockIssue[];
+	    mismatches: BlockIssue[];
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RestoreReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uid = source["uid"];
+	        this.written = source["written"];
+	        this.skipped = this.convertValues(source["skipped"], BlockIssue);
+	        this.mismatches = this.convertValues(source["mismatches"], BlockIssue);
+	        this.warnings = source["warnings"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FieldMismatch {
+	    field: string;
+	    expected: string;
+	    actual: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldMismatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.expected = source["expected"];
+	        this.actual = source["actual"];
+	    }
+	}
+	export class WriteVerification {
+	    uid: string;
+	    blocks: string[];
+	    trailer: string;
+	    accessBits: string;
+	    fields: creality.Fields;
+	    mismatches: FieldMismatch[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WriteVerification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uid = source["uid"];
+	        this.blocks = source["blocks"];
+	        this.trailer = source["trailer"];
+	        this.accessBits = source["accessBits"];
+	        this.fields = this.convertValues(source["fields"], creality.Fields);
+	        this.mismatches = this.convertValues(source["mismatches"], FieldMismatch);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CardInfo {
+	    type: string;
+	    name: string;
+	    uid: string;
+	    atr: string;
+	    atqa?: string;
+	    sak?: string;
+	    magic?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CardInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.name = source["name"];
+	        this.uid = source["uid"];
+	        this.atr = source["atr"];
+	        this.atqa = source["atqa"];
+	        this.sak = source["sak"];
+	        this.magic = source["magic"];
 	    }
 	}
 
@@ -0,0 +1,219 @@
+// Package access codifica e decodifica as condições de acesso (access bits)
+// do trailer de setor do MIFARE Classic.
+//
+// Bytes 6–9 do trailer:
+//
+//	byte 6: ~C2 (nibble alto) | ~C1 (nibble baixo)
+//	byte 7:  C1 (nibble alto) | ~C3 (nibble baixo)
+//	byte 8:  C3 (nibble alto) |  C2 (nibble baixo)
+//	byte 9:  GPB (general purpose byte, livre)
+//
+// O bit i de cada nibble pertence ao bloco i do setor (3 = trailer).
+package access
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMalformed     = errors.New("access bits inválidos (cópias invertidas não conferem)")
+	ErrPermanentLock = errors.New("trailer trava o setor permanentemente")
+)
+
+// Perm máscara das keys que permitem uma operação.
+type Perm uint8
+
+const (
+	PermNone Perm = 0
+	PermA    Perm = 1 << 0
+	PermB    Perm = 1 << 1
+	PermAB        = PermA | PermB
+)
+
+func (p Perm) String() string {
+	switch p {
+	case PermA:
+		return "A"
+	case PermB:
+		return "B"
+	case PermAB:
+		return "A|B"
+	}
+	return "nunca"
+}
+
+// Condition C1C2C3 de um bloco (0–7), C1 no bit mais significativo.
+type Condition uint8
+
+// DataAccess permissões de um bloco de dados.
+type DataAccess struct {
+	Read, Write, Increment, Decrement Perm // Decrement inclui transfer/restore
+}
+
+// TrailerAccess permissões de cada parte do trailer.
+type TrailerAccess struct {
+	WriteKeyA, ReadAccess, WriteAccess, ReadKeyB, WriteKeyB Perm
+}
+
+// Tabelas do datasheet NXP (MF1S50yyX), indexadas por C1C2C3.
+var (
+	dataTable = [8]DataAccess{
+		{PermAB, PermAB, PermAB, PermAB},       // 000 (transporte)
+		{PermAB, PermNone, PermNone, PermAB},   // 001
+		{PermAB, PermNone, PermNone, PermNone}, // 010
+		{PermB, PermB, PermNone, PermNone},     // 011
+		{PermAB, PermB, PermNone, PermNone},    // 100
+		{PermB, PermNone, PermNone, PermNone},  // 101
+		{PermAB, PermB, PermB, PermAB},         // 110
+		{PermNone, PermNone, PermNone, PermNone},
+	}
+	trailerTable = [8]TrailerAccess{
+		{PermA, PermA, PermNone, PermA, PermA},           // 000
+		{PermA, PermA, PermA, PermA, PermA},              // 001 (transporte)
+		{PermNone, PermA, PermNone, PermA, PermNone},     // 010
+		{PermB, PermAB, PermB, PermNone, PermB},          // 011
+		{PermB, PermAB, PermNone, PermNone, PermB},       // 100
+		{PermNone, PermAB, PermB, PermNone, PermNone},    // 101
+		{PermNone, PermAB, PermNone, PermNone, PermNone}, // 110
+		{PermNone, PermAB, PermNone, PermNone, PermNone}, // 111
+	}
+)
+
+// Data permissões da condição aplicada a um bloco de dados.
+func (c Condition) Data() DataAccess {
+	return dataTable[c&7]
+}
+
+// Trailer permissões da condição aplicada ao trailer.
+func (c Condition) Trailer() TrailerAccess {
+	return trailerTable[c&7]
+}
+
+func (c Condition) String() string {
+	return fmt.Sprintf("%03b", uint8(c&7))
+}
+
+// Bits condições de acesso dos 4 blocos do setor mais o GPB.
+type Bits struct {
+	Blocks [4]Condition // 0–2 dados, 3 trailer
+	GPB    byte
+}
+
+// Transport condições de fábrica (FF078069): dados livres com A|B e
+// trailer gerenciado pela KeyA.
+var Transport = Bits{Blocks: [4]Condition{0, 0, 0, 1}, GPB: 0x69}
+
+// Decode decodifica os 4 bytes de access bits + GPB (bytes 6–9 do trailer).
+func Decode(b []byte) (Bits, error) {
+	var bits Bits
+	if len(b) != 4 {
+		return bits, fmt.Errorf("access bits devem ter 4 bytes, recebido %d", len(b))
+	}
+	c1, c2, c3 := b[1]>>4, b[2]&0x0F, b[2]>>4
+	if ^b[0]&0x0F != c1 || ^b[0]>>4 != c2 || ^b[1]&0x0F != c3 {
+		return bits, fmt.Errorf("%w: %X", ErrMalformed, b[:3])
+	}
+	for i := uint(0); i < 4; i++ {
+		bits.Blocks[i] = Condition((c1>>i&1)<<2 | (c2>>i&1)<<1 | c3>>i&1)
+	}
+	bits.GPB = b[3]
+	return bits, nil
+}
+
+// Parse decodifica access bits em hex (8 caracteres, ex.: "FF078069").
+func Parse(s string) (Bits, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return Bits{}, fmt.Errorf("access bits em hex inválidos: %q", s)
+	}
+	return Decode(b)
+}
+
+// Encode gera os 4 bytes (access bits + GPB) com as cópias invertidas.
+func (b Bits) Encode() [4]byte {
+	var c1, c2, c3 byte
+	for i, c := range b.Blocks {
+		c1 |= byte(c>>2&1) << i
+		c2 |= byte(c>>1&1) << i
+		c3 |= byte(c&1) << i
+	}
+	return [4]byte{
+		^c2<<4 | ^c1&0x0F,
+		c1<<4 | ^c3&0x0F,
+		c3<<4 | c2,
+		b.GPB,
+	}
+}
+
+func (b Bits) String() string {
+	e := b.Encode()
+	return strings.ToUpper(hex.EncodeToString(e[:]))
+}
+
+// KeyBReadable indica que a KeyB é legível; nesse caso ela não serve
+// como key de autenticação (comportamento NXP).
+func (b Bits) KeyBReadable() bool {
+	return b.Blocks[3].Trailer().ReadKeyB != PermNone
+}
+
+// Effective remove a KeyB de p quando ela é legível.
+func (b Bits) Effective(p Perm) Perm {
+	if b.KeyBReadable() {
+		return p &^ PermB
+	}
+	return p
+}
+
+// Locked indica que nenhuma key utilizável consegue regravar os access
+// bits: as condições do setor ficam congeladas para sempre.
+func (b Bits) Locked() bool {
+	return b.Effective(b.Blocks[3].Trailer().WriteAccess) == PermNone
+}
+
+// Describe descreve em texto as permissões de cada bloco do setor.
+func (b Bits) Describe() []string {
+	out := make([]string, 0, 4)
+	for i := 0; i < 3; i++ {
+		d := b.Blocks[i].Data()
+		out = append(out, fmt.Sprintf("bloco %d (%s): leitura %s, escrita %s, incremento %s, decremento %s",
+			i, b.Blocks[i], b.Effective(d.Read), b.Effective(d.Write),
+			b.Effective(d.Increment), b.Effective(d.Decrement)))
+	}
+	t := b.Blocks[3].Trailer()
+	line := fmt.Sprintf("trailer (%s): KeyA escrita %s; access bits leitura %s, escrita %s; KeyB leitura %s, escrita %s",
+		b.Blocks[3], b.Effective(t.WriteKeyA), b.Effective(t.ReadAccess), b.Effective(t.WriteAccess),
+		b.Effective(t.ReadKeyB), b.Effective(t.WriteKeyB))
+	if b.KeyBReadable() {
+		line += " (KeyB legível: não serve como key)"
+	}
+	return append(out, line)
+}
+
+// Trailer monta um trailer de 16 bytes: KeyA + access bits/GPB + KeyB.
+func Trailer(keyA []byte, bits Bits, keyB []byte) []byte {
+	e := bits.Encode()
+	out := make([]byte, 0, 16)
+	out = append(out, keyA...)
+	out = append(out, e[:]...)
+	return append(out, keyB...)
+}
+
+// CheckTrailer valida um trailer de 16 bytes antes da gravação. Access bits
+// malformados são sempre recusados (o cartão bloquearia o setor); um
+// trailer que congela as condições só passa com force.
+func CheckTrailer(trailer []byte, force bool) (Bits, error) {
+	if len(trailer) != 16 {
+		return Bits{}, fmt.Errorf("trailer deve ter 16 bytes, recebido %d", len(trailer))
+	}
+	bits, err := Decode(trailer[6:10])
+	if err != nil {
+		return bits, err
+	}
+	if bits.Locked() && !force {
+		return bits, fmt.Errorf("%w: com access bits %s nenhuma key poderá alterá-los", ErrPermanentLock, bits)
+	}
+	return bits, nil
+}
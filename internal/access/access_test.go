@@ -0,0 +1,128 @@
+package access
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDecodeEncode(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado [4]Condition
+		gpb      byte
+	}{
+		{"FF078069", [4]Condition{0, 0, 0, 1}, 0x69}, // transporte
+		{"FF0780", [4]Condition{}, 0},                // tamanho inválido (tratado abaixo)
+		{"F78F0000", [4]Condition{0, 0, 0, 4}, 0x00},
+		{"878877C1", [4]Condition{3, 3, 3, 4}, 0xC1},
+		{"00F0FF00", [4]Condition{7, 7, 7, 7}, 0x00},
+		{"78778869", [4]Condition{4, 4, 4, 3}, 0x69},
+	}
+
+	for _, tt := range testes {
+		bits, err := Parse(tt.entrada)
+		if len(tt.entrada) != 8 {
+			if err == nil {
+				t.Errorf("Parse(%q) deveria retornar erro", tt.entrada)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) retornou erro inesperado: %v", tt.entrada, err)
+			continue
+		}
+		if bits.Blocks != tt.esperado || bits.GPB != tt.gpb {
+			t.Errorf("Parse(%q) = %v/%02X, esperado %v/%02X", tt.entrada, bits.Blocks, bits.GPB, tt.esperado, tt.gpb)
+		}
+		if bits.String() != tt.entrada {
+			t.Errorf("Encode(Parse(%q)) = %s", tt.entrada, bits)
+		}
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	for _, entrada := range []string{"FFFFFF69", "00000000", "FF078169"} {
+		if _, err := Parse(entrada); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Parse(%q) = %v, esperado ErrMalformed", entrada, err)
+		}
+	}
+}
+
+func TestEncodeAllConditions(t *testing.T) {
+	// Toda combinação de condições deve sobreviver à ida e volta
+	for c := Condition(0); c < 8; c++ {
+		bits := Bits{Blocks: [4]Condition{c, 7 - c, c ^ 5, c}, GPB: byte(c)}
+		e := bits.Encode()
+		got, err := Decode(e[:])
+		if err != nil {
+			t.Fatalf("Decode(Encode(%v)) erro: %v", bits, err)
+		}
+		if got != bits {
+			t.Errorf("ida e volta %v -> %X -> %v", bits, e, got)
+		}
+	}
+}
+
+func TestLocked(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado bool
+	}{
+		{"FF078069", false}, // 001: access bits graváveis por A
+		{"F78F0000", true},  // 100: access bits nunca graváveis
+		{"878877C1", true},
+		{"78778869", false}, // trailer 011: KeyB regrava
+		{"00F0FF00", true},  // 111 em tudo
+		{"FF0F0000", true},  // 000: KeyA não regrava access bits
+	}
+
+	for _, tt := range testes {
+		bits, err := Parse(tt.entrada)
+		if err != nil {
+			t.Fatalf("Parse(%q) erro: %v", tt.entrada, err)
+		}
+		if bits.Locked() != tt.esperado {
+			t.Errorf("Locked(%s) = %v, esperado %v", tt.entrada, bits.Locked(), tt.esperado)
+		}
+	}
+}
+
+func TestKeyBReadable(t *testing.T) {
+	// Transporte: KeyB legível pela A, então B não concede acesso
+	if !Transport.KeyBReadable() {
+		t.Error("KeyB deveria ser legível nas condições de transporte")
+	}
+	if got := Transport.Effective(Transport.Blocks[0].Data().Write); got != PermA {
+		t.Errorf("escrita efetiva no transporte = %s, esperado A", got)
+	}
+}
+
+func TestCheckTrailer(t *testing.T) {
+	key := []byte{0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6}
+	locked := Bits{Blocks: [4]Condition{0, 0, 0, 4}}
+
+	if _, err := CheckTrailer(Trailer(key, Transport, key), false); err != nil {
+		t.Errorf("trailer de transporte recusado: %v", err)
+	}
+	if _, err := CheckTrailer(Trailer(key, locked, key), false); !errors.Is(err, ErrPermanentLock) {
+		t.Errorf("esperado ErrPermanentLock, obtido %v", err)
+	}
+	if _, err := CheckTrailer(Trailer(key, locked, key), true); err != nil {
+		t.Errorf("force deveria aceitar trailer travado: %v", err)
+	}
+
+	malformed := Trailer(key, Transport, key)
+	malformed[7] ^= 0x01
+	if _, err := CheckTrailer(malformed, true); !errors.Is(err, ErrMalformed) {
+		t.Errorf("access bits malformados devem ser recusados mesmo com force: %v", err)
+	}
+	if _, err := CheckTrailer(make([]byte, 15), true); err == nil {
+		t.Error("trailer com 15 bytes deveria retornar erro")
+	}
+
+	want := append(append(append([]byte{}, key...), 0xFF, 0x07, 0x80, 0x69), key...)
+	if got := Trailer(key, Transport, key); !bytes.Equal(got, want) {
+		t.Errorf("Trailer = %X, esperado %X", got, want)
+	}
+}
@@ -0,0 +1,157 @@
+package ace
+
+// Tags das bobinas Anycubic (ACE Pro): NTAG213 com os dados em claro
+// direto nas páginas, sem NDEF. Textos ASCII completados com 00,
+// inteiros little-endian.
+//
+//   pág.  4     cabeçalho 7B 00 65 00
+//   pág.  5–8   SKU                  "AHPLBK-101"
+//   pág. 10–14  marca                "AC"
+//   pág. 15–19  tipo do material     "PLA"
+//   pág. 20     cor A B G R
+//   pág. 24     temperatura do bico mínima, máxima (°C, 2 + 2)
+//   pág. 29     temperatura da mesa mínima, máxima (°C, 2 + 2)
+//   pág. 30     diâmetro (centésimos de mm), comprimento (m)
+//   pág. 31     peso (g)
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Size bytes da página 4 até o fim da página 31.
+const Size = (31 - firstPage + 1) * 4
+
+const firstPage = 4
+
+var header = []byte{0x7B, 0x00, 0x65, 0x00}
+
+var (
+	ErrNotACE  = errors.New("tag sem dados Anycubic ACE")
+	ErrShort   = errors.New("dados Anycubic menores que o layout")
+	ErrTooLong = errors.New("valor não cabe no layout Anycubic")
+)
+
+// field texto: página inicial e número de páginas.
+type field struct{ page, pages int }
+
+var (
+	fieldSKU   = field{5, 4}
+	fieldBrand = field{10, 5}
+	fieldType  = field{15, 5}
+)
+
+const (
+	pageColor    = 20
+	pageTemps    = 24
+	pageBedTemps = 29
+	pageSize     = 30
+	pageWeight   = 31
+)
+
+// off deslocamento da página nos dados lidos a partir da página 4.
+func off(page int) int {
+	return (page - firstPage) * 4
+}
+
+// Spool conteúdo de uma tag Anycubic.
+type Spool struct {
+	SKU        string
+	Brand      string
+	Type       string
+	Color      [4]byte // RGBA
+	MinTemp    int     // °C do bico
+	MaxTemp    int
+	BedMinTemp int // °C
+	BedMaxTemp int
+	Diameter   int // centésimos de mm (175 = 1,75 mm)
+	Length     int // m
+	Weight     int // g
+}
+
+// ColorHex cor sem o alfa, em 6 hex maiúsculos.
+func (s Spool) ColorHex() string {
+	return fmt.Sprintf("%02X%02X%02X", s.Color[0], s.Color[1], s.Color[2])
+}
+
+// Marshal monta as páginas 4 a 31.
+func (s Spool) Marshal() ([]byte, error) {
+	b := make([]byte, Size)
+	copy(b, header)
+	for _, t := range []struct {
+		f     field
+		name  string
+		value string
+	}{
+		{fieldSKU, "SKU", s.SKU},
+		{fieldBrand, "marca", s.Brand},
+		{fieldType, "tipo", s.Type},
+	} {
+		if len(t.value) > t.f.pages*4 {
+			return nil, fmt.Errorf("%w: %s %q (máx. %d bytes)", ErrTooLong, t.name, t.value, t.f.pages*4)
+		}
+		copy(b[off(t.f.page):], t.value)
+	}
+	b[off(pageColor)] = s.Color[3]
+	b[off(pageColor)+1] = s.Color[2]
+	b[off(pageColor)+2] = s.Color[1]
+	b[off(pageColor)+3] = s.Color[0]
+	for _, n := range []struct {
+		at    int
+		name  string
+		value int
+	}{
+		{off(pageTemps), "temperatura mínima do bico", s.MinTemp},
+		{off(pageTemps) + 2, "temperatura máxima do bico", s.MaxTemp},
+		{off(pageBedTemps), "temperatura mínima da mesa", s.BedMinTemp},
+		{off(pageBedTemps) + 2, "temperatura máxima da mesa", s.BedMaxTemp},
+		{off(pageSize), "diâmetro", s.Diameter},
+		{off(pageSize) + 2, "comprimento", s.Length},
+		{off(pageWeight), "peso", s.Weight},
+	} {
+		if n.value < 0 || n.value > 0xFFFF {
+			return nil, fmt.Errorf("%w: %s %d", ErrTooLong, n.name, n.value)
+		}
+		binary.LittleEndian.PutUint16(b[n.at:], uint16(n.value))
+	}
+	return b, nil
+}
+
+// Unmarshal lê os dados a partir da página 4; ErrNotACE sem o cabeçalho.
+func Unmarshal(b []byte) (*Spool, error) {
+	if len(b) >= len(header) && !bytes.Equal(b[:len(header)], header) {
+		return nil, fmt.Errorf("%w: cabeçalho % X", ErrNotACE, b[:len(header)])
+	}
+	if len(b) < Size {
+		return nil, fmt.Errorf("%w: %d bytes", ErrShort, len(b))
+	}
+	u16 := func(at int) int { return int(binary.LittleEndian.Uint16(b[at:])) }
+	c := b[off(pageColor):]
+	return &Spool{
+		SKU:        text(b, fieldSKU),
+		Brand:      text(b, fieldBrand),
+		Type:       text(b, fieldType),
+		Color:      [4]byte{c[3], c[2], c[1], c[0]},
+		MinTemp:    u16(off(pageTemps)),
+		MaxTemp:    u16(off(pageTemps) + 2),
+		BedMinTemp: u16(off(pageBedTemps)),
+		BedMaxTemp: u16(off(pageBedTemps) + 2),
+		Diameter:   u16(off(pageSize)),
+		Length:     u16(off(pageSize) + 2),
+		Weight:     u16(off(pageWeight)),
+	}, nil
+}
+
+// IsACE os dados começam com o cabeçalho Anycubic.
+func IsACE(b []byte) bool {
+	return len(b) >= len(header) && bytes.Equal(b[:len(header)], header)
+}
+
+// text campo de texto sem o preenchimento.
+func text(b []byte, f field) string {
+	raw := b[off(f.page) : off(f.page)+f.pages*4]
+	return strings.TrimRight(string(raw), "\x00 ")
+}
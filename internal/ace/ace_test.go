@@ -0,0 +1,104 @@
+package ace
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+var testSpool = Spool{
+	SKU: "AHPLBK-101", Brand: "AC", Type: "PLA", Color: [4]byte{0x11, 0x22, 0x33, 0xFF},
+	MinTemp: 190, MaxTemp: 230, BedMinTemp: 50, BedMaxTemp: 60, Diameter: 175, Length: 330, Weight: 1000,
+}
+
+func TestMarshalLayout(t *testing.T) {
+	b, err := testSpool.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != Size {
+		t.Fatalf("%d bytes, esperado %d", len(b), Size)
+	}
+	testes := []struct {
+		campo    string
+		pagina   int
+		n        int
+		esperado string
+	}{
+		{"cabeçalho", 4, 4, "7B006500"},
+		{"SKU", 5, 10, hex.EncodeToString([]byte("AHPLBK-101"))},
+		{"marca", 10, 4, hex.EncodeToString([]byte("AC\x00\x00"))},
+		{"tipo", 15, 4, hex.EncodeToString([]byte("PLA\x00"))},
+		{"cor ABGR", 20, 4, "FF332211"},
+		{"bico", 24, 4, "BE00E600"},
+		{"mesa", 29, 4, "32003C00"},
+		{"diâmetro e comprimento", 30, 4, "AF004A01"},
+		{"peso", 31, 2, "E803"},
+	}
+	for _, tt := range testes {
+		at := (tt.pagina - 4) * 4
+		if got := hex.EncodeToString(b[at : at+tt.n]); !strings.EqualFold(got, tt.esperado) {
+			t.Errorf("%s = %s, esperado %s", tt.campo, got, tt.esperado)
+		}
+	}
+
+	got, err := Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != testSpool {
+		t.Errorf("Unmarshal = %+v, esperado %+v", *got, testSpool)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  []byte
+		esperado error
+	}{
+		{"NDEF", append([]byte{0x03, 0x00, 0xFE, 0x00}, make([]byte, Size)...), ErrNotACE},
+		{"curto", []byte{0x7B, 0x00, 0x65, 0x00, 0x41}, ErrShort},
+	}
+	for _, tt := range testes {
+		if _, err := Unmarshal(tt.entrada); !errors.Is(err, tt.esperado) {
+			t.Errorf("%s: %v, esperado %v", tt.nome, err, tt.esperado)
+		}
+	}
+	long := testSpool
+	long.SKU = "SKU-COM-MAIS-DE-16"
+	if _, err := long.Marshal(); !errors.Is(err, ErrTooLong) {
+		t.Errorf("SKU longo: %v", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  Spool
+		esperado creality.Fields
+	}{
+		{"comprimento gravado", testSpool,
+			creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0000", Material: "00001", Color: "0112233", Length: "014A"}},
+		{"só o peso", Spool{Brand: "Creality", Type: "PETG", Weight: 500},
+			creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0276", Material: "00003", Color: "0000000", Length: "00A5"}},
+	}
+	for _, tt := range testes {
+		if got := tt.entrada.Fields(); got != tt.esperado {
+			t.Errorf("%s: Fields = %+v, esperado %+v", tt.nome, got, tt.esperado)
+		}
+	}
+}
+
+func TestFromFields(t *testing.T) {
+	f := creality.Fields{Supplier: "0276", Material: "06002", Color: "077BB41", Length: "014A"}
+	got := FromFields(f, 0, 255)
+	want := Spool{Brand: "Creality", Type: "PETG", Color: [4]byte{0x77, 0xBB, 0x41, 0xFF},
+		MinTemp: 220, MaxTemp: 255, BedMinTemp: 80, BedMaxTemp: 80, Diameter: 175, Length: 330, Weight: 1000}
+	if got != want {
+		t.Errorf("FromFields = %+v, esperado %+v", got, want)
+	}
+}
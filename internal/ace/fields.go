@@ -0,0 +1,63 @@
+package ace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// defaultDiameter diâmetro CFS em centésimos de mm, a unidade da tag.
+const defaultDiameter = creality.Diameter * 100
+
+// FromFields converte campos CFS para o layout Anycubic. Temperaturas
+// zeradas usam a faixa padrão do material; o comprimento CFS já é em
+// metros.
+func FromFields(f creality.Fields, minTemp, maxTemp int) Spool {
+	typ := creality.BaseType(f.GetMaterialName())
+	s := Spool{
+		Brand:    f.Brand(),
+		Type:     typ,
+		Diameter: defaultDiameter,
+		Weight:   f.Grams(),
+	}
+	if m, err := strconv.ParseUint(f.Length, 16, 16); err == nil {
+		s.Length = int(m)
+	}
+	if len(f.Color) == 7 {
+		var c [3]byte
+		if _, err := fmt.Sscanf(f.Color[1:], "%02x%02x%02x", &c[0], &c[1], &c[2]); err == nil {
+			s.Color = [4]byte{c[0], c[1], c[2], 0xFF}
+		}
+	}
+	s.MinTemp, s.MaxTemp = creality.DefaultTemps(typ)
+	if minTemp > 0 {
+		s.MinTemp = minTemp
+	}
+	if maxTemp > 0 {
+		s.MaxTemp = maxTemp
+	}
+	bed := creality.DefaultBedTemp(typ)
+	s.BedMinTemp, s.BedMaxTemp = bed, bed
+	return s
+}
+
+// Fields campos CFS equivalentes: material genérico do tipo, fornecedor,
+// cor e comprimento (pelo comprimento gravado ou, sem ele, pelo peso).
+func (s Spool) Fields() creality.Fields {
+	f := creality.NewFields()
+	f.Supplier = "0000"
+	if strings.EqualFold(s.Brand, "Creality") {
+		f.Supplier = "0276"
+	}
+	f.Material = creality.GenericCode(s.Type)
+	f.Color = "0" + s.ColorHex()
+	switch {
+	case s.Length > 0:
+		f.Length = fmt.Sprintf("%04X", s.Length)
+	case s.Weight > 0:
+		f.Length = creality.LengthFromGrams(s.Weight)
+	}
+	return f
+}
@@ -0,0 +1,134 @@
+package bambu
+
+// Tags das bobinas Bambu Lab: MIFARE Classic 1K com as keys de cada setor
+// derivadas do UID por HKDF-SHA256 (só a key A é usada aqui; o app não
+// grava nessas tags).
+//
+// Blocos lidos (inteiros little-endian, textos ASCII completados com 00):
+//
+//   1   0–7  variante do material    "A00-K0"
+//       8–15 ID do material          "GFA00"
+//   2        tipo do filamento       "PLA"
+//   4        tipo detalhado          "PLA Basic"
+//   5   0–3  cor RGBA
+//       4–5  peso do filamento (g)
+//       8–11 diâmetro (mm, float32)
+//   6   0–1  temperatura de secagem (°C)
+//       2–3  tempo de secagem (h)
+//       6–7  temperatura da mesa (°C)
+//       8–9  temperatura máxima do bico (°C)
+//      10–11 temperatura mínima do bico (°C)
+//  12        data de produção        "2024_03_11_09_24"
+//  14   4–5  comprimento (m)
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Sectors setores de um MIFARE Classic 1K, uma key por setor.
+const Sectors = 16
+
+// Blocks blocos com dados, todos nos setores 0, 1 e 3.
+var Blocks = []int{1, 2, 4, 5, 6, 12, 14}
+
+var (
+	ErrNotBambu = errors.New("tag sem dados Bambu Lab")
+	ErrShort    = errors.New("bloco Bambu incompleto")
+)
+
+// masterSalt salt do HKDF usado pela Bambu Lab nas keys das tags.
+var masterSalt = []byte{
+	0x9A, 0x75, 0x9C, 0xF2, 0xC4, 0xF7, 0xCA, 0xFF,
+	0x22, 0x2C, 0xB9, 0x76, 0x9B, 0x41, 0xBC, 0x96,
+}
+
+// Keys keys A dos 16 setores (12 hex cada) da tag com o UID dado.
+func Keys(uidHex string) ([Sectors]string, error) {
+	var keys [Sectors]string
+	uid, err := hex.DecodeString(uidHex)
+	if err != nil || len(uid) != 4 {
+		return keys, fmt.Errorf("UID inválido %q", uidHex)
+	}
+	okm, err := hkdf.Key(sha256.New, uid, masterSalt, "RFID-A\x00", Sectors*6)
+	if err != nil {
+		return keys, err
+	}
+	for i := range keys {
+		keys[i] = strings.ToUpper(hex.EncodeToString(okm[i*6 : i*6+6]))
+	}
+	return keys, nil
+}
+
+// SectorOf setor do bloco.
+func SectorOf(block int) int {
+	return block / 4
+}
+
+// Tag conteúdo decodificado de uma tag Bambu Lab.
+type Tag struct {
+	Variant      string  // "A00-K0"
+	MaterialID   string  // "GFA00"
+	Type         string  // "PLA"
+	DetailedType string  // "PLA Basic"
+	Color        [4]byte // RGBA
+	Weight       int     // g
+	Diameter     float32 // mm
+	DryingTemp   int     // °C
+	DryingHours  int
+	BedTemp      int // °C
+	MinTemp      int // °C do bico
+	MaxTemp      int
+	Produced     time.Time // zero se ilegível
+	Length       int       // m
+}
+
+// ColorHex cor sem o alfa, em 6 hex maiúsculos.
+func (t Tag) ColorHex() string {
+	return strings.ToUpper(hex.EncodeToString(t.Color[:3]))
+}
+
+// Parse decodifica os blocos de Blocks (número do bloco → 16 bytes).
+func Parse(blocks map[int][]byte) (*Tag, error) {
+	for _, b := range Blocks {
+		if len(blocks[b]) != 16 {
+			return nil, fmt.Errorf("%w: bloco %d com %d bytes", ErrShort, b, len(blocks[b]))
+		}
+	}
+	b1, b5, b6 := blocks[1], blocks[5], blocks[6]
+	t := &Tag{
+		Variant:      text(b1[0:8]),
+		MaterialID:   text(b1[8:16]),
+		Type:         text(blocks[2]),
+		DetailedType: text(blocks[4]),
+		Weight:       int(binary.LittleEndian.Uint16(b5[4:])),
+		Diameter:     math.Float32frombits(binary.LittleEndian.Uint32(b5[8:])),
+		DryingTemp:   int(binary.LittleEndian.Uint16(b6[0:])),
+		DryingHours:  int(binary.LittleEndian.Uint16(b6[2:])),
+		BedTemp:      int(binary.LittleEndian.Uint16(b6[6:])),
+		MaxTemp:      int(binary.LittleEndian.Uint16(b6[8:])),
+		MinTemp:      int(binary.LittleEndian.Uint16(b6[10:])),
+		Length:       int(binary.LittleEndian.Uint16(blocks[14][4:])),
+	}
+	copy(t.Color[:], b5[0:4])
+	if t.Type == "" || !strings.HasPrefix(t.MaterialID, "GF") {
+		return nil, fmt.Errorf("%w: material %q, tipo %q", ErrNotBambu, t.MaterialID, t.Type)
+	}
+	if d, err := time.Parse("2006_01_02_15_04", text(blocks[12])); err == nil {
+		t.Produced = d
+	}
+	return t, nil
+}
+
+// text campo ASCII sem o preenchimento.
+func text(b []byte) string {
+	return string(bytes.TrimRight(b, "\x00 "))
+}
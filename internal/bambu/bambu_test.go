@@ -0,0 +1,120 @@
+package bambu
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// testBlocks blocos de uma bobina PLA Matte de 1kg.
+func testBlocks() map[int][]byte {
+	block := func(s string) []byte {
+		b := make([]byte, 16)
+		copy(b, s)
+		return b
+	}
+	b1 := make([]byte, 16)
+	copy(b1, "A01-K1")
+	copy(b1[8:], "GFA01")
+	b5 := make([]byte, 16)
+	copy(b5, []byte{0x11, 0x22, 0x33, 0xFF})
+	binary.LittleEndian.PutUint16(b5[4:], 1000)
+	binary.LittleEndian.PutUint32(b5[8:], math.Float32bits(1.75))
+	b6 := make([]byte, 16)
+	for i, v := range []uint16{55, 8, 1, 60, 230, 190} {
+		binary.LittleEndian.PutUint16(b6[2*i:], v)
+	}
+	b14 := make([]byte, 16)
+	binary.LittleEndian.PutUint16(b14[4:], 330)
+	return map[int][]byte{
+		1: b1, 2: block("PLA"), 4: block("PLA Matte"), 5: b5, 6: b6,
+		12: block("2024_03_11_09_24"), 14: b14,
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys, err := Keys("A1B2C3D4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		setor    int
+		esperado string
+	}{
+		{0, "03A4F0B4F9C7"},
+		{1, "056E45495385"},
+		{3, "EF2EDC12848F"},
+		{15, "E0CFF2654F2A"},
+	}
+	for _, tt := range testes {
+		if keys[tt.setor] != tt.esperado {
+			t.Errorf("key do setor %d = %s, esperado %s", tt.setor, keys[tt.setor], tt.esperado)
+		}
+	}
+	if _, err := Keys("04A1B2C3D4E5F6"); err == nil {
+		t.Error("UID de 7 bytes deveria falhar")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tag, err := Parse(testBlocks())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Tag{
+		Variant: "A01-K1", MaterialID: "GFA01", Type: "PLA", DetailedType: "PLA Matte",
+		Color: [4]byte{0x11, 0x22, 0x33, 0xFF}, Weight: 1000, Diameter: 1.75,
+		DryingTemp: 55, DryingHours: 8, BedTemp: 60, MinTemp: 190, MaxTemp: 230,
+		Produced: time.Date(2024, 3, 11, 9, 24, 0, 0, time.UTC), Length: 330,
+	}
+	if *tag != want {
+		t.Errorf("Parse = %+v, esperado %+v", *tag, want)
+	}
+	if tag.ColorHex() != "112233" {
+		t.Errorf("ColorHex = %s", tag.ColorHex())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	curto := testBlocks()
+	curto[6] = curto[6][:8]
+	vazio := testBlocks()
+	vazio[1], vazio[2] = make([]byte, 16), make([]byte, 16)
+	testes := []struct {
+		nome     string
+		entrada  map[int][]byte
+		esperado error
+	}{
+		{"bloco curto", curto, ErrShort},
+		{"sem material", vazio, ErrNotBambu},
+	}
+	for _, tt := range testes {
+		if _, err := Parse(tt.entrada); !errors.Is(err, tt.esperado) {
+			t.Errorf("%s: %v, esperado %v", tt.nome, err, tt.esperado)
+		}
+	}
+}
+
+func TestCrealityCode(t *testing.T) {
+	testes := []struct {
+		entrada  Tag
+		esperado string
+	}{
+		{Tag{MaterialID: "GFA00", Type: "PLA", DetailedType: "PLA Basic"}, "01001"},
+		{Tag{MaterialID: "GFG02", Type: "PETG", DetailedType: "PETG HF"}, "06002"},
+		{Tag{MaterialID: "GFA99", Type: "PLA", DetailedType: "PLA Wood"}, "17001"},
+		{Tag{MaterialID: "GFA99", Type: "PLA", DetailedType: "PLA Tough"}, "01001"},
+		{Tag{MaterialID: "GFN99", Type: "PA6-CF", DetailedType: "PA6-CF"}, "12005"},
+		{Tag{MaterialID: "GFS99", Type: "PLA", DetailedType: "Support for PLA"}, "00024"},
+		{Tag{MaterialID: "GFS99", Type: "PVA", DetailedType: "PVA"}, "00011"},
+		{Tag{MaterialID: "GFT99", Type: "PET-CF", DetailedType: "Novo"}, "00013"},
+		{Tag{MaterialID: "GFX99", Type: "PEEK", DetailedType: "PEEK"}, ""},
+	}
+	for _, tt := range testes {
+		if got := tt.entrada.CrealityCode(); got != tt.esperado {
+			t.Errorf("%s %q: CrealityCode = %q, esperado %q", tt.entrada.MaterialID, tt.entrada.DetailedType, got, tt.esperado)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package bambu
+
+// Material Creality mais próximo de um filamento Bambu Lab, para regravar
+// a bobina como CFS.
+
+import (
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// crealityByID ID do material Bambu (bloco 1) → código Creality.
+var crealityByID = map[string]string{
+	"GFA00": "01001", // PLA Basic → Hyper PLA
+	"GFA01": "14001", // PLA Matte → CR-PLA Matte
+	"GFA05": "05001", // PLA Silk → CR-Silk
+	"GFA50": "02001", // PLA-CF → Hyper PLA-CF
+	"GFB00": "03001", // ABS → Hyper ABS
+	"GFB01": "19001", // ASA → HP-ASA
+	"GFC00": "07002", // PC → Hyper PC
+	"GFG00": "06002", // PETG Basic → Hyper PETG
+	"GFG02": "06002", // PETG HF → Hyper PETG
+	"GFG50": "06003", // PETG-CF → Hyper PETG-CF
+	"GFN04": "12003", // PAHT-CF → Hyper PAHT-CF
+	"GFU01": "16001", // TPU 95A → CR-TPU
+}
+
+// crealityByFinish acabamentos de PLA pelo nome detalhado ("PLA Wood").
+var crealityByFinish = []struct{ finish, code string }{
+	{"MATTE", "14001"}, {"SILK", "05001"}, {"WOOD", "17001"}, {"MARBLE", "29001"},
+	{"GLOW", "01003"}, {"SPARKLE", "01004"}, {"GALAXY", "01004"},
+}
+
+// crealityByType material base → linha Creality equivalente; tipos fora
+// da lista ficam com o material genérico.
+var crealityByType = map[string]string{
+	"PLA": "01001", "PLA-CF": "02001", "ABS": "03001", "PETG": "06002",
+	"PETG-CF": "06003", "PC": "07002", "TPU": "16001", "ASA": "19001",
+	"PPA-CF": "12002", "PAHT-CF": "12003", "PA612-CF": "12004", "PA6-CF": "12005",
+}
+
+// CrealityCode código do material Creality mais próximo: pelo ID do
+// material, pelo acabamento do PLA, pela linha Creality do material base
+// e por fim pelo genérico ("" se nenhum serve).
+func (t Tag) CrealityCode() string {
+	if code, ok := crealityByID[t.MaterialID]; ok {
+		return code
+	}
+	name := strings.ToUpper(t.DetailedType)
+	switch {
+	case strings.HasPrefix(name, "SUPPORT FOR PLA"):
+		return "00024"
+	case strings.HasPrefix(name, "SUPPORT FOR PA"):
+		return "00023"
+	}
+	typ := creality.BaseType(t.DetailedType)
+	if creality.GenericCode(typ) == "" && crealityByType[typ] == "" {
+		typ = creality.BaseType(t.Type)
+	}
+	if typ == "PLA" {
+		for _, f := range crealityByFinish {
+			if strings.Contains(name, f.finish) {
+				return f.code
+			}
+		}
+	}
+	if code, ok := crealityByType[typ]; ok {
+		return code
+	}
+	return creality.GenericCode(typ)
+}
@@ -0,0 +1,172 @@
+package creality
+
+// Material base (PLA, PETG, PA6-CF...) dos nomes comerciais, usado pelos
+// formatos abertos (OpenSpool, OpenTag3D) que gravam o tipo e não o
+// código Creality.
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Diameter diâmetro do filamento CFS, em mm.
+const Diameter = 1.75
+
+// genericCodes material base → código do material genérico CFS (00xxx).
+var genericCodes = map[string]string{
+	"PLA": "00001", "PETG": "00003", "ABS": "00004", "TPU": "00005",
+	"PLA-CF": "00006", "ASA": "00007", "PA": "00008", "PA-CF": "00009",
+	"BVOH": "00010", "PVA": "00011", "HIPS": "00012", "PET-CF": "00013",
+	"PETG-CF": "00014", "PA6-CF": "00015", "PAHT-CF": "00016", "PPS": "00017",
+	"PPS-CF": "00018", "PP": "00019", "PET": "00020", "PC": "00021",
+	"PA612-CF": "00022", "PA12-CF": "00025", "PETG-GF": "00027", "PP-CF": "00031",
+	"PCTG": "00032", "ASA-CF": "00033", "PA6-GF": "00034",
+}
+
+// extraTypes materiais base sem genérico CFS, reconhecidos só pelo nome
+// (PPA-CF não pode virar PA-CF).
+var extraTypes = []string{"PPA-CF"}
+
+// typeOverrides nomes comerciais que não citam o material base.
+var typeOverrides = map[string]string{
+	"CR-Silk": "PLA", "CR-Wood": "PLA", "CR-Nylon": "PA",
+	"Hyper Luminous": "PLA", "Hyper Stardust": "PLA", "Hyper Marble": "PLA",
+}
+
+// types tipos conhecidos, do mais longo ao mais curto (PLA-CF antes de PLA).
+var types = func() []string {
+	out := append(make([]string, 0, len(genericCodes)+len(extraTypes)), extraTypes...)
+	for t := range genericCodes {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i]) != len(out[j]) {
+			return len(out[i]) > len(out[j])
+		}
+		return out[i] < out[j]
+	})
+	return out
+}()
+
+// defaultTemps faixa de temperatura do bico por material base.
+var defaultTemps = map[string][2]int{
+	"PLA": {190, 220}, "PETG": {220, 250}, "PET": {230, 260}, "PCTG": {230, 260},
+	"ABS": {240, 260}, "ASA": {240, 260}, "HIPS": {230, 250}, "TPU": {210, 230},
+	"PA": {250, 280}, "PC": {260, 290}, "PP": {220, 250}, "PPS": {300, 330},
+	"PVA": {190, 210}, "BVOH": {190, 210},
+}
+
+// BaseType material base do nome comercial ("Hyper PLA-CF" → "PLA-CF").
+// Nomes sem material conhecido são devolvidos como estão.
+func BaseType(materialName string) string {
+	if t, ok := typeOverrides[materialName]; ok {
+		return t
+	}
+	upper := strings.ToUpper(materialName)
+	for _, t := range types {
+		for from := 0; ; {
+			i := strings.Index(upper[from:], t)
+			if i < 0 {
+				break
+			}
+			i += from
+			if boundary(upper, i-1) && boundary(upper, i+len(t)) {
+				return t
+			}
+			from = i + 1
+		}
+	}
+	return materialName
+}
+
+// boundary a posição i está fora do nome ou não é letra nem dígito.
+func boundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	c := s[i]
+	return !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9')
+}
+
+// GenericCode código do material genérico CFS para o tipo ("" se não há).
+func GenericCode(typ string) string {
+	return genericCodes[BaseType(typ)]
+}
+
+// DefaultTemps faixa de temperatura sugerida para o tipo (0, 0 se
+// desconhecido). Variantes com fibra usam a faixa do material base.
+func DefaultTemps(typ string) (min, max int) {
+	t := BaseType(typ)
+	if r, ok := defaultTemps[t]; ok {
+		return r[0], r[1]
+	}
+	if base, _, ok := strings.Cut(t, "-"); ok {
+		base = strings.TrimRight(base, "0123456789")
+		if r, ok := defaultTemps[base]; ok {
+			return r[0], r[1]
+		}
+	}
+	return 0, 0
+}
+
+// Brand marca do material a partir do código CFS.
+func (f Fields) Brand() string {
+	switch {
+	case strings.HasPrefix(f.Material, "E"):
+		return "eSUN"
+	case strings.HasPrefix(f.Material, "P"):
+		return "Polymaker"
+	case f.Supplier == "0276":
+		return "Creality"
+	}
+	return "Generic"
+}
+
+// defaultBedTemps temperatura da mesa por material base.
+var defaultBedTemps = map[string]int{
+	"PLA": 60, "PETG": 80, "PET": 80, "PCTG": 80, "ABS": 100, "ASA": 100,
+	"HIPS": 100, "TPU": 50, "PA": 90, "PC": 110, "PP": 90, "PPS": 110,
+	"PVA": 60, "BVOH": 60,
+}
+
+// DefaultBedTemp temperatura da mesa sugerida para o tipo (0 se desconhecido).
+func DefaultBedTemp(typ string) int {
+	t := BaseType(typ)
+	if temp, ok := defaultBedTemps[t]; ok {
+		return temp
+	}
+	base, _, _ := strings.Cut(t, "-")
+	return defaultBedTemps[strings.TrimRight(base, "0123456789")]
+}
+
+// lengthGrams códigos de comprimento de fábrica → peso da bobina.
+var lengthGrams = map[string]int{"0053": 250, "00A5": 500, "014A": 1000, "0294": 2000}
+
+// Grams peso do filamento a partir do comprimento gravado (hex, em
+// unidades de 3 g); 0 se inválido.
+func (f Fields) Grams() int {
+	if g, ok := lengthGrams[strings.ToUpper(f.Length)]; ok {
+		return g
+	}
+	n, err := strconv.ParseUint(f.Length, 16, 16)
+	if err != nil {
+		return 0
+	}
+	return int(n) * 3
+}
+
+// LengthFromGrams código de comprimento para o peso (inverso de Grams).
+func LengthFromGrams(grams int) string {
+	for code, g := range lengthGrams {
+		if g == grams {
+			return code
+		}
+	}
+	cm := grams / 3
+	if cm > 0xFFFF {
+		cm = 0xFFFF
+	}
+	return fmt.Sprintf("%04X", cm)
+}
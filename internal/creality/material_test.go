@@ -0,0 +1,69 @@
+package creality
+
+import "testing"
+
+func TestBaseType(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado string
+	}{
+		{"PLA", "PLA"},
+		{"Hyper PLA-CF", "PLA-CF"},
+		{"Hyper PA6-CF", "PA6-CF"},
+		{"Hyper PPA-CF", "PPA-CF"},
+		{"CR-PLA Matte", "PLA"},
+		{"EN-PLA+", "PLA"},
+		{"eSUN PETG+HS", "PETG"},
+		{"HP-TPU", "TPU"},
+		{"Hyper PC", "PC"},
+		{"PolySonic PLA Pro", "PLA"},
+		{"CR-Nylon", "PA"},
+		{"CR-Silk", "PLA"},
+		{"PEEK", "PEEK"},
+	}
+	for _, tt := range testes {
+		if got := BaseType(tt.entrada); got != tt.esperado {
+			t.Errorf("BaseType(%q) = %q, esperado %q", tt.entrada, got, tt.esperado)
+		}
+	}
+}
+
+func TestGenericCode(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado string
+	}{
+		{"PLA", "00001"},
+		{"Hyper PETG", "00003"},
+		{"Hyper PA6-CF", "00015"},
+		{"Hyper PPA-CF", ""},
+		{"PEEK", ""},
+	}
+	for _, tt := range testes {
+		if got := GenericCode(tt.entrada); got != tt.esperado {
+			t.Errorf("GenericCode(%q) = %q, esperado %q", tt.entrada, got, tt.esperado)
+		}
+	}
+}
+
+func TestGrams(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado int
+	}{
+		{"014A", 1000},
+		{"0053", 250},
+		{"0064", 300},
+		{"zz", 0},
+	}
+	for _, tt := range testes {
+		if got := (Fields{Length: tt.entrada}).Grams(); got != tt.esperado {
+			t.Errorf("Grams(%q) = %d, esperado %d", tt.entrada, got, tt.esperado)
+		}
+	}
+	for _, g := range []int{250, 1000, 2000, 300} {
+		if got := (Fields{Length: LengthFromGrams(g)}).Grams(); got != g {
+			t.Errorf("LengthFromGrams(%d) relido como %d", g, got)
+		}
+	}
+}
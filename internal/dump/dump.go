@@ -0,0 +1,88 @@
+package dump
+
+// Dump completo de MIFARE Classic 1K e formatos de arquivo padrão:
+//
+//   .bin/.mfd  — 1024 bytes crus (blocos 0–63)
+//   .mct       — texto do MIFARE Classic Tool ("+Sector: N" + 4 linhas hex)
+//   .json      — formato "mfcard" do Proxmark3
+//
+//   d.WriteMCT(f)
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	Sectors         = 16
+	BlocksPerSector = 4
+	Blocks          = Sectors * BlocksPerSector
+	BlockSize       = 16
+)
+
+// SectorKeys keys que abriram o setor (12 hex; "" = não encontrada).
+type SectorKeys struct {
+	KeyA string `json:"keyA"`
+	KeyB string `json:"keyB"`
+}
+
+// Dump conteúdo lido de uma tag MIFARE Classic 1K.
+type Dump struct {
+	UID    string
+	Blocks [Blocks][BlockSize]byte
+	Read   [Blocks]bool // false = bloco não pôde ser lido
+	Keys   [Sectors]SectorKeys
+}
+
+// TrailerBlock retorna o número do trailer do setor.
+func TrailerBlock(sector int) int {
+	return sector*BlocksPerSector + BlocksPerSector - 1
+}
+
+// SectorOf retorna o setor de um bloco.
+func SectorOf(block int) int {
+	return block / BlocksPerSector
+}
+
+// IsTrailer indica se o bloco é o trailer do seu setor.
+func IsTrailer(block int) bool {
+	return block%BlocksPerSector == BlocksPerSector-1
+}
+
+// BlockHex retorna o bloco em 32 hex maiúsculos.
+func (d *Dump) BlockHex(block int) string {
+	return strings.ToUpper(hex.EncodeToString(d.Blocks[block][:]))
+}
+
+// ATQA e SAK gravados pelo fabricante no bloco 0 (UID de 4 bytes).
+func (d *Dump) ATQA() string {
+	return strings.ToUpper(hex.EncodeToString(d.Blocks[0][6:8]))
+}
+
+func (d *Dump) SAK() string {
+	return strings.ToUpper(hex.EncodeToString(d.Blocks[0][5:6]))
+}
+
+// FillTrailerKeys grava no trailer as keys descobertas — a tag sempre
+// devolve KeyA (e às vezes KeyB) como zeros na leitura.
+func (d *Dump) FillTrailerKeys() {
+	for s := 0; s < Sectors; s++ {
+		t := &d.Blocks[TrailerBlock(s)]
+		if k, err := hex.DecodeString(d.Keys[s].KeyA); err == nil && len(k) == 6 {
+			copy(t[0:6], k)
+		}
+		if k, err := hex.DecodeString(d.Keys[s].KeyB); err == nil && len(k) == 6 {
+			copy(t[10:16], k)
+		}
+	}
+}
+
+// Complete indica se todos os blocos foram lidos.
+func (d *Dump) Complete() bool {
+	for _, ok := range d.Read {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
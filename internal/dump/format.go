@@ -0,0 +1,148 @@
+package dump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Formatos de arquivo suportados.
+const (
+	FormatBin  = "bin"
+	FormatMCT  = "mct"
+	FormatJSON = "json"
+)
+
+// FormatFromPath deduz o formato pela extensão (.bin/.mfd/.dump, .mct, .json).
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".bin", ".mfd", ".dump":
+		return FormatBin, nil
+	case ".mct":
+		return FormatMCT, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("extensão de dump não suportada: %q", filepath.Ext(path))
+}
+
+// Write grava o dump no formato indicado.
+func (d *Dump) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatBin:
+		return d.WriteBin(w)
+	case FormatMCT:
+		return d.WriteMCT(w)
+	case FormatJSON:
+		return d.WriteProxmarkJSON(w)
+	}
+	return fmt.Errorf("formato de dump desconhecido: %q", format)
+}
+
+// WriteBin grava os 1024 bytes crus (blocos não lidos saem zerados).
+func (d *Dump) WriteBin(w io.Writer) error {
+	for i := range d.Blocks {
+		if _, err := w.Write(d.Blocks[i][:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMCT grava no formato texto do MIFARE Classic Tool. Bytes desconhecidos
+// (blocos não lidos, keys não descobertas) saem como "-".
+func (d *Dump) WriteMCT(w io.Writer) error {
+	for s := 0; s < Sectors; s++ {
+		if _, err := fmt.Fprintf(w, "+Sector: %d\n", s); err != nil {
+			return err
+		}
+		if !d.sectorRead(s) {
+			if _, err := io.WriteString(w, "No keys found (or dead sector)\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		for b := s * BlocksPerSector; b <= TrailerBlock(s); b++ {
+			if _, err := io.WriteString(w, d.mctLine(b)+"\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Dump) sectorRead(sector int) bool {
+	for b := sector * BlocksPerSector; b <= TrailerBlock(sector); b++ {
+		if d.Read[b] {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dump) mctLine(block int) string {
+	unknown := strings.Repeat("-", 2*BlockSize)
+	if !d.Read[block] {
+		return unknown
+	}
+	line := d.BlockHex(block)
+	if !IsTrailer(block) {
+		return line
+	}
+	keys := d.Keys[SectorOf(block)]
+	if keys.KeyA == "" {
+		line = unknown[:12] + line[12:]
+	}
+	if keys.KeyB == "" {
+		line = line[:20] + unknown[:12]
+	}
+	return line
+}
+
+// proxmarkJSON estrutura do dump "mfcard" do Proxmark3.
+type proxmarkJSON struct {
+	Created    string                    `json:"Created"`
+	FileType   string                    `json:"FileType"`
+	Card       proxmarkCard              `json:"Card"`
+	Blocks     map[string]string         `json:"blocks"`
+	SectorKeys map[string]proxmarkSector `json:"SectorKeys"`
+}
+
+type proxmarkCard struct {
+	UID  string `json:"UID"`
+	ATQA string `json:"ATQA"`
+	SAK  string `json:"SAK"`
+}
+
+type proxmarkSector struct {
+	KeyA             string `json:"KeyA"`
+	KeyB             string `json:"KeyB"`
+	AccessConditions string `json:"AccessConditions"`
+}
+
+// WriteProxmarkJSON grava no formato JSON "mfcard" do Proxmark3.
+func (d *Dump) WriteProxmarkJSON(w io.Writer) error {
+	out := proxmarkJSON{
+		Created:    "cfs_spool",
+		FileType:   "mfcard",
+		Card:       proxmarkCard{UID: strings.ToUpper(d.UID), ATQA: d.ATQA(), SAK: d.SAK()},
+		Blocks:     make(map[string]string, Blocks),
+		SectorKeys: make(map[string]proxmarkSector, Sectors),
+	}
+	for b := 0; b < Blocks; b++ {
+		out.Blocks[strconv.Itoa(b)] = d.BlockHex(b)
+	}
+	for s := 0; s < Sectors; s++ {
+		out.SectorKeys[strconv.Itoa(s)] = proxmarkSector{
+			KeyA:             d.Keys[s].KeyA,
+			KeyB:             d.Keys[s].KeyB,
+			AccessConditions: d.BlockHex(TrailerBlock(s))[12:20],
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,103 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testDump() *Dump {
+	d := &Dump{UID: "a1b2c3d4"}
+	copy(d.Blocks[0][:], []byte{0xA1, 0xB2, 0xC3, 0xD4, 0x04, 0x08, 0x04, 0x00})
+	for b := 0; b < Blocks; b++ {
+		d.Read[b] = true
+	}
+	for s := 0; s < Sectors; s++ {
+		copy(d.Blocks[TrailerBlock(s)][6:10], []byte{0xFF, 0x07, 0x80, 0x69})
+		d.Keys[s] = SectorKeys{KeyA: "FFFFFFFFFFFF", KeyB: "FFFFFFFFFFFF"}
+	}
+	d.Blocks[4][0] = 0x42
+	// Setor 2 sem key conhecida; setor 3 só com KeyA
+	d.Keys[2] = SectorKeys{}
+	for b := 8; b <= 11; b++ {
+		d.Read[b] = false
+	}
+	d.Keys[3].KeyB = ""
+	d.FillTrailerKeys()
+	return d
+}
+
+func TestWriteBin(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDump().WriteBin(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 1024 {
+		t.Errorf("bin tem %d bytes, esperado 1024", buf.Len())
+	}
+	if buf.Bytes()[64] != 0x42 {
+		t.Errorf("byte 0 do bloco 4 = %02X, esperado 42", buf.Bytes()[64])
+	}
+}
+
+func TestWriteMCT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDump().WriteMCT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if lines[0] != "+Sector: 0" || lines[5] != "+Sector: 1" {
+		t.Errorf("cabeçalhos de setor inesperados: %q / %q", lines[0], lines[5])
+	}
+	if lines[4] != "FFFFFFFFFFFFFF078069FFFFFFFFFFFF" {
+		t.Errorf("trailer setor 0 = %s", lines[4])
+	}
+	if lines[10] != "+Sector: 2" || lines[11] != "No keys found (or dead sector)" {
+		t.Errorf("setor 2 sem key: %q / %q", lines[10], lines[11])
+	}
+	if lines[16] != "FFFFFFFFFFFFFF078069------------" {
+		t.Errorf("trailer setor 3 com KeyB desconhecida = %s", lines[16])
+	}
+}
+
+func TestWriteProxmarkJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDump().WriteProxmarkJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var out proxmarkJSON
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	if out.FileType != "mfcard" || out.Card.UID != "A1B2C3D4" || out.Card.SAK != "08" || out.Card.ATQA != "0400" {
+		t.Errorf("cabeçalho inesperado: %+v", out)
+	}
+	if len(out.Blocks) != Blocks || out.Blocks["4"][:2] != "42" {
+		t.Errorf("blocos inesperados: %d, bloco 4 = %s", len(out.Blocks), out.Blocks["4"])
+	}
+	if out.SectorKeys["1"].AccessConditions != "FF078069" || out.SectorKeys["2"].KeyA != "" {
+		t.Errorf("SectorKeys inesperado: %+v", out.SectorKeys)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	testes := []struct {
+		path     string
+		esperado string
+		erro     bool
+	}{
+		{"tag.bin", FormatBin, false},
+		{"tag.MFD", FormatBin, false},
+		{"tag.mct", FormatMCT, false},
+		{"hf-mf-A1B2C3D4-dump.json", FormatJSON, false},
+		{"tag.txt", "", true},
+	}
+	for _, tt := range testes {
+		resultado, err := FormatFromPath(tt.path)
+		if tt.erro != (err != nil) || resultado != tt.esperado {
+			t.Errorf("FormatFromPath(%q) = %q, %v", tt.path, resultado, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package dump
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Load lê um dump do disco deduzindo o formato pela extensão.
+func Load(path string) (*Dump, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f, format)
+}
+
+// Read decodifica um dump no formato indicado.
+func Read(r io.Reader, format string) (*Dump, error) {
+	switch format {
+	case FormatBin:
+		return ReadBin(r)
+	case FormatMCT:
+		return ReadMCT(r)
+	case FormatJSON:
+		return ReadProxmarkJSON(r)
+	}
+	return nil, fmt.Errorf("formato de dump desconhecido: %q", format)
+}
+
+// ReadBin decodifica 1024 bytes crus. Keys vêm dos próprios trailers.
+func ReadBin(r io.Reader) (*Dump, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != Blocks*BlockSize {
+		return nil, fmt.Errorf("dump .bin deve ter %d bytes, recebido: %d", Blocks*BlockSize, len(raw))
+	}
+	d := &Dump{}
+	for b := 0; b < Blocks; b++ {
+		copy(d.Blocks[b][:], raw[b*BlockSize:])
+		d.Read[b] = true
+	}
+	d.UID = strings.ToUpper(hex.EncodeToString(d.Blocks[0][:4]))
+	d.keysFromTrailers()
+	return d, nil
+}
+
+// ReadMCT decodifica o texto do MIFARE Classic Tool. Linhas com "-" marcam
+// blocos desconhecidos; no trailer, apenas a key correspondente.
+func ReadMCT(r io.Reader) (*Dump, error) {
+	d := &Dump{}
+	sector, line := -1, 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(text, "+Sector:"); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(rest))
+			if err != nil || n < 0 || n >= Sectors {
+				return nil, fmt.Errorf("cabeçalho de setor inválido: %q", text)
+			}
+			sector, line = n, 0
+			continue
+		}
+		if sector < 0 {
+			return nil, fmt.Errorf("linha fora de setor: %q", text)
+		}
+		if strings.HasPrefix(text, "No keys found") {
+			line = BlocksPerSector
+			continue
+		}
+		if line >= BlocksPerSector || len(text) != 2*BlockSize {
+			return nil, fmt.Errorf("setor %d: linha inválida %q", sector, text)
+		}
+		block := sector*BlocksPerSector + line
+		line++
+		if err := d.setMCTLine(block, text); err != nil {
+			return nil, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if d.Read[0] {
+		d.UID = strings.ToUpper(hex.EncodeToString(d.Blocks[0][:4]))
+	}
+	return d, nil
+}
+
+func (d *Dump) setMCTLine(block int, text string) error {
+	if !IsTrailer(block) {
+		if strings.Contains(text, "-") {
+			return nil
+		}
+		raw, err := hex.DecodeString(text)
+		if err != nil {
+			return fmt.Errorf("bloco %d: %v", block, err)
+		}
+		copy(d.Blocks[block][:], raw)
+		d.Read[block] = true
+		return nil
+	}
+
+	// Trailer: KeyA (0–5), access bits + GPB (6–9), KeyB (10–15)
+	if strings.Contains(text[12:20], "-") {
+		return nil
+	}
+	sector := SectorOf(block)
+	t := &d.Blocks[block]
+	parts := []struct {
+		hex string
+		dst []byte
+		key *string
+	}{
+		{text[0:12], t[0:6], &d.Keys[sector].KeyA},
+		{text[12:20], t[6:10], nil},
+		{text[20:32], t[10:16], &d.Keys[sector].KeyB},
+	}
+	for _, p := range parts {
+		if strings.Contains(p.hex, "-") {
+			continue
+		}
+		raw, err := hex.DecodeString(p.hex)
+		if err != nil {
+			return fmt.Errorf("bloco %d: %v", block, err)
+		}
+		copy(p.dst, raw)
+		if p.key != nil {
+			*p.key = strings.ToUpper(p.hex)
+		}
+	}
+	d.Read[block] = true
+	return nil
+}
+
+// ReadProxmarkJSON decodifica o JSON "mfcard" do Proxmark3.
+func ReadProxmarkJSON(r io.Reader) (*Dump, error) {
+	var in proxmarkJSON
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, err
+	}
+	if len(in.Blocks) == 0 {
+		return nil, errors.New("JSON sem blocos")
+	}
+	d := &Dump{UID: strings.ToUpper(in.Card.UID)}
+	for key, value := range in.Blocks {
+		b, err := strconv.Atoi(key)
+		if err != nil || b < 0 || b >= Blocks {
+			return nil, fmt.Errorf("bloco inválido no JSON: %q", key)
+		}
+		raw, err := hex.DecodeString(value)
+		if err != nil || len(raw) != BlockSize {
+			return nil, fmt.Errorf("bloco %d: conteúdo inválido %q", b, value)
+		}
+		copy(d.Blocks[b][:], raw)
+		d.Read[b] = true
+	}
+	d.keysFromTrailers()
+	for key, sk := range in.SectorKeys {
+		s, err := strconv.Atoi(key)
+		if err != nil || s < 0 || s >= Sectors {
+			continue
+		}
+		if sk.KeyA != "" {
+			d.Keys[s].KeyA = strings.ToUpper(sk.KeyA)
+		}
+		if sk.KeyB != "" {
+			d.Keys[s].KeyB = strings.ToUpper(sk.KeyB)
+		}
+	}
+	d.FillTrailerKeys()
+	if d.UID == "" && d.Read[0] {
+		d.UID = strings.ToUpper(hex.EncodeToString(d.Blocks[0][:4]))
+	}
+	return d, nil
+}
+
+// keysFromTrailers preenche Keys a partir dos bytes dos trailers lidos.
+func (d *Dump) keysFromTrailers() {
+	for s := 0; s < Sectors; s++ {
+		b := TrailerBlock(s)
+		if !d.Read[b] {
+			continue
+		}
+		d.Keys[s].KeyA = strings.ToUpper(hex.EncodeToString(d.Blocks[b][0:6]))
+		d.Keys[s].KeyB = strings.ToUpper(hex.EncodeToString(d.Blocks[b][10:16]))
+	}
+}
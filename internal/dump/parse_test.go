@@ -0,0 +1,60 @@
+package dump
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadRoundTrip(t *testing.T) {
+	orig := testDump()
+
+	for _, format := range []string{FormatBin, FormatMCT, FormatJSON} {
+		var buf bytes.Buffer
+		if err := orig.Write(&buf, format); err != nil {
+			t.Fatalf("%s: Write erro: %v", format, err)
+		}
+		got, err := Read(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: Read erro: %v", format, err)
+		}
+		if got.UID != "A1B2C3D4" {
+			t.Errorf("%s: UID = %q", format, got.UID)
+		}
+		if got.Blocks[4] != orig.Blocks[4] || !got.Read[4] {
+			t.Errorf("%s: bloco 4 = %X", format, got.Blocks[4])
+		}
+		if got.Keys[1] != orig.Keys[1] {
+			t.Errorf("%s: keys setor 1 = %+v, esperado %+v", format, got.Keys[1], orig.Keys[1])
+		}
+	}
+}
+
+func TestReadMCTUnknownParts(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDump().WriteMCT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	d, err := ReadMCT(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Read[8] || d.Read[11] || d.Keys[2].KeyA != "" {
+		t.Errorf("setor 2 deveria continuar desconhecido: read=%v keys=%+v", d.Read[8], d.Keys[2])
+	}
+	if !d.Read[15] || d.Keys[3].KeyA != "FFFFFFFFFFFF" || d.Keys[3].KeyB != "" {
+		t.Errorf("trailer setor 3: read=%v keys=%+v", d.Read[15], d.Keys[3])
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	if _, err := ReadBin(bytes.NewReader(make([]byte, 100))); err == nil {
+		t.Error("ReadBin com 100 bytes deveria retornar erro")
+	}
+	if _, err := ReadMCT(strings.NewReader("+Sector: 0\nXYZ\n")); err == nil {
+		t.Error("ReadMCT com linha inválida deveria retornar erro")
+	}
+	if _, err := ReadProxmarkJSON(strings.NewReader(`{"blocks": {"0": "zz"}}`)); err == nil {
+		t.Error("ReadProxmarkJSON com bloco inválido deveria retornar erro")
+	}
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado slog.Level
+		erro     bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"trace", LevelTrace, false},
+		{"DEBUG", slog.LevelDebug, false},
+		{" warn ", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+	for _, tt := range testes {
+		got, err := ParseLevel(tt.entrada)
+		if got != tt.esperado || (err != nil) != tt.erro {
+			t.Errorf("ParseLevel(%q) = %v, %v; esperado %v (erro %v)", tt.entrada, got, err, tt.esperado, tt.erro)
+		}
+	}
+}
+
+func TestTraceLevelName(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, LevelTrace).Log(context.Background(), LevelTrace, "apdu")
+	if !strings.Contains(buf.String(), "level=TRACE") {
+		t.Errorf("nível TRACE não aparece no log: %q", buf.String())
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	f, err := OpenRotating(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotating erro: %v", err)
+	}
+	defer f.Close()
+
+	// Cada escrita de 6 bytes passa do limite de 10 e provoca rotação
+	for _, s := range []string{"aaaaa\n", "bbbbb\n", "ccccc\n", "ddddd\n"} {
+		if _, err := f.Write([]byte(s)); err != nil {
+			t.Fatalf("Write erro: %v", err)
+		}
+	}
+
+	testes := []struct {
+		entrada  string
+		esperado string
+	}{
+		{path, "ddddd\n"},
+		{path + ".1", "ccccc\n"},
+		{path + ".2", "bbbbb\n"},
+	}
+	for _, tt := range testes {
+		data, err := os.ReadFile(tt.entrada)
+		if err != nil || string(data) != tt.esperado {
+			t.Errorf("%s = %q, %v; esperado %q", filepath.Base(tt.entrada), data, err, tt.esperado)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("backup além do limite não foi removido")
+	}
+}
@@ -0,0 +1,77 @@
+package moonraker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Modos de marcar a bobina ativa
+const (
+	ModeSpoolman = "spoolman" // POST /server/spoolman/spool_id (padrão)
+	ModeGcode    = "gcode"    // macro SET_ACTIVE_SPOOL do Klipper
+)
+
+// ErrNoSpool nada a enviar: bobina sem ID do Spoolman e sem macro de
+// filamento.
+var ErrNoSpool = errors.New("bobina sem ID do Spoolman")
+
+// Target como a impressora recebe a bobina.
+type Target struct {
+	Mode  string // ModeSpoolman ("") ou ModeGcode
+	Tool  string // extruder/slot no Klipper ("T1"); só no modo gcode e na macro
+	Macro string // macro do usuário que recebe tipo e cor ("" = nenhuma)
+}
+
+// Filament bobina colocada no leitor.
+type Filament struct {
+	SpoolID int    // bobina no Spoolman (0 = sem Spoolman)
+	Type    string // material base: "PLA"
+	Color   string // 6 hex
+}
+
+// Activate marca a bobina como ativa e chama a macro de filamento. No
+// modo gcode com Tool, o ID vai para a variável spool_id da macro do
+// extruder (o padrão do Spoolman para várias ferramentas).
+func (c *Client) Activate(ctx context.Context, t Target, f Filament) error {
+	if t.Mode != "" && t.Mode != ModeSpoolman && t.Mode != ModeGcode {
+		return fmt.Errorf("modo do Moonraker inválido: %q", t.Mode)
+	}
+	if f.SpoolID == 0 && t.Macro == "" {
+		return ErrNoSpool
+	}
+	var script []string
+	if f.SpoolID > 0 {
+		switch {
+		case t.Mode != ModeGcode:
+			if err := c.SetActiveSpool(ctx, f.SpoolID); err != nil {
+				return err
+			}
+		case t.Tool != "":
+			script = append(script, fmt.Sprintf("SET_GCODE_VARIABLE MACRO=%s VARIABLE=spool_id VALUE=%d", Param(t.Tool), f.SpoolID))
+		default:
+			script = append(script, fmt.Sprintf("SET_ACTIVE_SPOOL ID=%d", f.SpoolID))
+		}
+	}
+	if t.Macro != "" {
+		script = append(script, FilamentMacro(t, f))
+	}
+	if len(script) == 0 {
+		return nil
+	}
+	return c.RunGcode(ctx, strings.Join(script, "\n"))
+}
+
+// FilamentMacro chamada da macro de filamento:
+// "<MACRO> TYPE=PLA COLOR=77BB41 [TOOL=T1] [SPOOL=3]".
+func FilamentMacro(t Target, f Filament) string {
+	line := fmt.Sprintf("%s TYPE=%s COLOR=%s", strings.ToUpper(Param(t.Macro)), Param(f.Type), Param(f.Color))
+	if t.Tool != "" {
+		line += " TOOL=" + Param(t.Tool)
+	}
+	if f.SpoolID > 0 {
+		line += fmt.Sprintf(" SPOOL=%d", f.SpoolID)
+	}
+	return line
+}
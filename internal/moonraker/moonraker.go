@@ -0,0 +1,165 @@
+package moonraker
+
+// Cliente da API HTTP do Moonraker (Klipper). As respostas seguem o
+// envelope JSON-RPC do Moonraker: {"result": ...} no sucesso e
+// {"error": {"code": ..., "message": ...}} na falha.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout prazo de cada requisição sem outro configurado.
+const DefaultTimeout = 5 * time.Second
+
+var (
+	ErrBadURL       = errors.New("endereço do Moonraker inválido")
+	ErrUnauthorized = errors.New("Moonraker recusou a API key")
+)
+
+// APIError erro devolvido pelo Moonraker (ou pelo Klipper, em G-code).
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("Moonraker respondeu %d", e.Status)
+	}
+	return fmt.Sprintf("Moonraker respondeu %d: %s", e.Status, e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrUnauthorized && (e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden)
+}
+
+// Client acesso a um Moonraker.
+type Client struct {
+	base   string
+	apiKey string
+	http   *http.Client
+}
+
+// NewClient cliente do Moonraker em baseURL ("http://k1.local:7125");
+// apiKey vazia para instâncias sem autorização; timeout 0 usa
+// DefaultTimeout.
+func NewClient(baseURL, apiKey string, timeout time.Duration) (*Client, error) {
+	u, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrBadURL, baseURL)
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		base:   strings.TrimRight(u.String(), "/"),
+		apiKey: apiKey,
+		http:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// SetActiveSpool marca a bobina id do Spoolman como ativa no Moonraker
+// (0 limpa a bobina ativa).
+func (c *Client) SetActiveSpool(ctx context.Context, id int) error {
+	body := map[string]any{"spool_id": id}
+	if id == 0 {
+		body["spool_id"] = nil
+	}
+	return c.do(ctx, http.MethodPost, "/server/spoolman/spool_id", body, nil)
+}
+
+// ActiveSpool bobina ativa no Moonraker (0 = nenhuma).
+func (c *Client) ActiveSpool(ctx context.Context) (int, error) {
+	var out struct {
+		SpoolID *int `json:"spool_id"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/server/spoolman/spool_id", nil, &out); err != nil {
+		return 0, err
+	}
+	if out.SpoolID == nil {
+		return 0, nil
+	}
+	return *out.SpoolID, nil
+}
+
+// RunGcode executa o script no Klipper e espera ele terminar.
+func (c *Client) RunGcode(ctx context.Context, script string) error {
+	return c.do(ctx, http.MethodPost, "/printer/gcode/script", map[string]string{"script": script}, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-Api-Key", c.apiKey)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	decodeErr := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&env)
+	if resp.StatusCode/100 != 2 || env.Error != nil {
+		apiErr := &APIError{Status: resp.StatusCode}
+		if env.Error != nil {
+			apiErr.Message = env.Error.Message
+		}
+		return apiErr
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("resposta inválida do Moonraker em %s %s: %v", method, path, decodeErr)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(env.Result, out); err != nil {
+		return fmt.Errorf("resposta inválida do Moonraker em %s %s: %v", method, path, err)
+	}
+	return nil
+}
+
+// Param valor de parâmetro de G-code: Klipper separa parâmetros por
+// espaço e não tem aspas, então espaços viram "_" e o resto fora de
+// [A-Za-z0-9._-] some.
+func Param(s string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r == ' ':
+			b.WriteByte('_')
+		case r < 128 && (r == '.' || r == '_' || r == '-' ||
+			('0' <= r && r <= '9') || ('A' <= r && r <= 'Z') || ('a' <= r && r <= 'z')):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package moonraker_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/moonraker"
+	"github.com/robertocorreajr/cfs_spool/internal/moonraker/moonrakertest"
+)
+
+func newTestClient(t *testing.T, apiKey string) (*moonraker.Client, *moonrakertest.Fake) {
+	t.Helper()
+	fake := moonrakertest.NewFake()
+	fake.APIKey = "segredo"
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	c, err := moonraker.NewClient(srv.URL+"/", apiKey, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, fake
+}
+
+func TestNewClient(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado error
+	}{
+		{"http://k1.local:7125", nil},
+		{"https://10.0.0.7/", nil},
+		{"k1.local:7125", moonraker.ErrBadURL},
+		{"", moonraker.ErrBadURL},
+	}
+	for _, tt := range testes {
+		if _, err := moonraker.NewClient(tt.entrada, "", 0); !errors.Is(err, tt.esperado) {
+			t.Errorf("NewClient(%q) = %v, esperado %v", tt.entrada, err, tt.esperado)
+		}
+	}
+}
+
+func TestParam(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado string
+	}{
+		{"PLA", "PLA"},
+		{"PA6-CF", "PA6-CF"},
+		{" Hyper PLA ", "Hyper_PLA"},
+		{"PLA;M112", "PLAM112"},
+		{"PETG\nFIRMWARE_RESTART", "PETGFIRMWARE_RESTART"},
+		{"Seda Metálica", "Seda_Metlica"},
+	}
+	for _, tt := range testes {
+		if got := moonraker.Param(tt.entrada); got != tt.esperado {
+			t.Errorf("Param(%q) = %q, esperado %q", tt.entrada, got, tt.esperado)
+		}
+	}
+}
+
+func TestActivate(t *testing.T) {
+	f := moonraker.Filament{SpoolID: 3, Type: "PLA", Color: "77BB41"}
+	testes := []struct {
+		nome     string
+		target   moonraker.Target
+		filament moonraker.Filament
+		spool    int
+		scripts  []string
+	}{
+		{"endpoint do Spoolman", moonraker.Target{}, f, 3, nil},
+		{"SET_ACTIVE_SPOOL", moonraker.Target{Mode: moonraker.ModeGcode}, f, 0, []string{"SET_ACTIVE_SPOOL ID=3"}},
+		{"extruder", moonraker.Target{Mode: moonraker.ModeGcode, Tool: "T1"}, f, 0,
+			[]string{"SET_GCODE_VARIABLE MACRO=T1 VARIABLE=spool_id VALUE=3"}},
+		{"macro de filamento", moonraker.Target{Tool: "T1", Macro: "set_filament"}, f, 3,
+			[]string{"SET_FILAMENT TYPE=PLA COLOR=77BB41 TOOL=T1 SPOOL=3"}},
+		{"sem Spoolman", moonraker.Target{Macro: "SET_FILAMENT"}, moonraker.Filament{Type: "PETG", Color: "FFFFFF"}, 0,
+			[]string{"SET_FILAMENT TYPE=PETG COLOR=FFFFFF"}},
+	}
+	for _, tt := range testes {
+		c, fake := newTestClient(t, "segredo")
+		if err := c.Activate(context.Background(), tt.target, tt.filament); err != nil {
+			t.Errorf("%s: %v", tt.nome, err)
+			continue
+		}
+		if got := fake.SpoolID(); got != tt.spool {
+			t.Errorf("%s: bobina ativa = %d, esperado %d", tt.nome, got, tt.spool)
+		}
+		if got := fake.Scripts(); !reflect.DeepEqual(got, tt.scripts) {
+			t.Errorf("%s: scripts = %q, esperado %q", tt.nome, got, tt.scripts)
+		}
+	}
+}
+
+func TestActivateErrors(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestClient(t, "segredo")
+	if err := c.Activate(ctx, moonraker.Target{}, moonraker.Filament{Type: "PLA"}); !errors.Is(err, moonraker.ErrNoSpool) {
+		t.Errorf("sem bobina nem macro: %v", err)
+	}
+	if err := c.Activate(ctx, moonraker.Target{Mode: "mqtt"}, moonraker.Filament{SpoolID: 1}); err == nil {
+		t.Error("modo inválido deveria falhar")
+	}
+	var apiErr *moonraker.APIError
+	if err := c.Activate(ctx, moonraker.Target{Macro: "ERRO_MACRO"}, moonraker.Filament{Type: "PLA"}); !errors.As(err, &apiErr) || apiErr.Message == "" {
+		t.Errorf("macro inexistente: %v", err)
+	}
+
+	bad, _ := newTestClient(t, "errada")
+	if err := bad.SetActiveSpool(ctx, 1); !errors.Is(err, moonraker.ErrUnauthorized) {
+		t.Errorf("API key errada: %v", err)
+	}
+}
+
+func TestActiveSpool(t *testing.T) {
+	c, _ := newTestClient(t, "segredo")
+	ctx := context.Background()
+	for _, id := range []int{7, 0} {
+		if err := c.SetActiveSpool(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.ActiveSpool(ctx); err != nil || got != id {
+			t.Errorf("ActiveSpool = %d, %v; esperado %d", got, err, id)
+		}
+	}
+}
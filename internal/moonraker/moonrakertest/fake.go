@@ -0,0 +1,93 @@
+// Package moonrakertest Moonraker falso para testes.
+package moonrakertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Fake Moonraker em memória com os endpoints usados pelo moonraker.Client, para
+// testes com httptest.NewServer. Scripts que começam com "ERRO" falham
+// como um G-code desconhecido no Klipper.
+type Fake struct {
+	APIKey string // exigida em X-Api-Key quando não vazia
+
+	mu      sync.Mutex
+	mux     *http.ServeMux
+	spoolID *int
+	scripts []string
+}
+
+// NewFake cria o servidor sem bobina ativa.
+func NewFake() *Fake {
+	f := &Fake{mux: http.NewServeMux()}
+	f.mux.HandleFunc("GET /server/spoolman/spool_id", func(w http.ResponseWriter, r *http.Request) {
+		result(w, map[string]*int{"spool_id": f.spoolID})
+	})
+	f.mux.HandleFunc("POST /server/spoolman/spool_id", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SpoolID *int `json:"spool_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f.spoolID = body.SpoolID
+		result(w, map[string]*int{"spool_id": f.spoolID})
+	})
+	f.mux.HandleFunc("POST /printer/gcode/script", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Script string `json:"script"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Script == "" {
+			fail(w, http.StatusBadRequest, "No data for argument: script")
+			return
+		}
+		if len(body.Script) >= 4 && body.Script[:4] == "ERRO" {
+			fail(w, http.StatusBadRequest, "Unknown command:\""+body.Script+"\"")
+			return
+		}
+		f.scripts = append(f.scripts, body.Script)
+		result(w, "ok")
+	})
+	return f
+}
+
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.APIKey != "" && r.Header.Get("X-Api-Key") != f.APIKey {
+		fail(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	f.mux.ServeHTTP(w, r)
+}
+
+// SpoolID bobina ativa (0 = nenhuma).
+func (f *Fake) SpoolID() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.spoolID == nil {
+		return 0
+	}
+	return *f.spoolID
+}
+
+// Scripts G-code executados, em ordem.
+func (f *Fake) Scripts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.scripts...)
+}
+
+func result(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": v})
+}
+
+func fail(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": status, "message": msg}})
+}
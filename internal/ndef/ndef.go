@@ -0,0 +1,251 @@
+package ndef
+
+// Mensagens NDEF (NFC Forum) e o TLV que as guarda em tags Tipo 2 (NTAG21x).
+//
+//   msg := ndef.Encode(ndef.MediaRecord("application/json", payload))
+//   area := ndef.WrapTLV(msg)          // 03 <len> <msg> FE, a partir da página 4
+//
+//   msg, _ := ndef.UnwrapTLV(area)
+//   recs, _ := ndef.Decode(msg)
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TNF (Type Name Format) do cabeçalho do registro.
+const (
+	TNFEmpty     = 0x00
+	TNFWellKnown = 0x01
+	TNFMedia     = 0x02 // tipo MIME (RFC 2046)
+	TNFURI       = 0x03
+	TNFExternal  = 0x04
+	TNFUnknown   = 0x05
+	TNFUnchanged = 0x06
+)
+
+// Bits do cabeçalho do registro.
+const (
+	flagMB = 0x80 // primeiro registro da mensagem
+	flagME = 0x40 // último registro
+	flagCF = 0x20 // registro fragmentado
+	flagSR = 0x10 // payload com tamanho de 1 byte
+	flagIL = 0x08 // tem ID
+)
+
+// Tipos de TLV da área de dados de tags Tipo 2.
+const (
+	tlvNull        = 0x00
+	tlvLock        = 0x01
+	tlvMemory      = 0x02
+	tlvNDEF        = 0x03
+	tlvProprietary = 0xFD
+	tlvTerminator  = 0xFE
+)
+
+var (
+	ErrNoMessage = errors.New("tag sem mensagem NDEF")
+	ErrMalformed = errors.New("NDEF malformado")
+)
+
+// Record registro NDEF.
+type Record struct {
+	TNF     byte
+	Type    []byte
+	ID      []byte
+	Payload []byte
+}
+
+// MediaRecord registro com tipo MIME (ex.: "application/json").
+func MediaRecord(mime string, payload []byte) Record {
+	return Record{TNF: TNFMedia, Type: []byte(mime), Payload: payload}
+}
+
+// IsMedia indica se o registro tem o tipo MIME dado.
+func (r Record) IsMedia(mime string) bool {
+	return r.TNF == TNFMedia && string(r.Type) == mime
+}
+
+// Encode monta a mensagem com os registros na ordem dada.
+func Encode(records ...Record) []byte {
+	var out []byte
+	for i, r := range records {
+		hdr := r.TNF & 0x07
+		if i == 0 {
+			hdr |= flagMB
+		}
+		if i == len(records)-1 {
+			hdr |= flagME
+		}
+		short := len(r.Payload) < 256
+		if short {
+			hdr |= flagSR
+		}
+		if len(r.ID) > 0 {
+			hdr |= flagIL
+		}
+		out = append(out, hdr, byte(len(r.Type)))
+		if short {
+			out = append(out, byte(len(r.Payload)))
+		} else {
+			n := len(r.Payload)
+			out = append(out, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+		}
+		if len(r.ID) > 0 {
+			out = append(out, byte(len(r.ID)))
+		}
+		out = append(out, r.Type...)
+		out = append(out, r.ID...)
+		out = append(out, r.Payload...)
+	}
+	return out
+}
+
+// Decode separa os registros da mensagem. Registros fragmentados (CF) não
+// são usados em tags e são recusados.
+func Decode(msg []byte) ([]Record, error) {
+	var records []Record
+	for pos := 0; pos < len(msg); {
+		hdr := msg[pos]
+		pos++
+		if hdr&flagCF != 0 {
+			return nil, fmt.Errorf("%w: registro fragmentado", ErrMalformed)
+		}
+		if pos >= len(msg) {
+			return nil, fmt.Errorf("%w: cabeçalho truncado", ErrMalformed)
+		}
+		typeLen := int(msg[pos])
+		pos++
+
+		var payloadLen int
+		if hdr&flagSR != 0 {
+			if pos+1 > len(msg) {
+				return nil, fmt.Errorf("%w: cabeçalho truncado", ErrMalformed)
+			}
+			payloadLen = int(msg[pos])
+			pos++
+		} else {
+			if pos+4 > len(msg) {
+				return nil, fmt.Errorf("%w: cabeçalho truncado", ErrMalformed)
+			}
+			payloadLen = int(msg[pos])<<24 | int(msg[pos+1])<<16 | int(msg[pos+2])<<8 | int(msg[pos+3])
+			pos += 4
+		}
+		idLen := 0
+		if hdr&flagIL != 0 {
+			if pos+1 > len(msg) {
+				return nil, fmt.Errorf("%w: cabeçalho truncado", ErrMalformed)
+			}
+			idLen = int(msg[pos])
+			pos++
+		}
+		if payloadLen < 0 || pos+typeLen+idLen+payloadLen > len(msg) {
+			return nil, fmt.Errorf("%w: registro %d passa do fim da mensagem", ErrMalformed, len(records))
+		}
+
+		r := Record{TNF: hdr & 0x07}
+		r.Type = clone(msg[pos : pos+typeLen])
+		pos += typeLen
+		r.ID = clone(msg[pos : pos+idLen])
+		pos += idLen
+		r.Payload = clone(msg[pos : pos+payloadLen])
+		pos += payloadLen
+		records = append(records, r)
+
+		if hdr&flagME != 0 {
+			break
+		}
+	}
+	if len(records) == 0 {
+		return nil, ErrNoMessage
+	}
+	return records, nil
+}
+
+// WrapTLV coloca a mensagem no TLV NDEF seguido do terminador.
+func WrapTLV(msg []byte) []byte {
+	out := []byte{tlvNDEF}
+	if len(msg) < 0xFF {
+		out = append(out, byte(len(msg)))
+	} else {
+		out = append(out, 0xFF, byte(len(msg)>>8), byte(len(msg)))
+	}
+	out = append(out, msg...)
+	return append(out, tlvTerminator)
+}
+
+// UnwrapTLV acha o primeiro TLV NDEF na área de dados, pulando os TLVs
+// de controle (lock, memória, proprietário).
+func UnwrapTLV(area []byte) ([]byte, error) {
+	for pos := 0; pos < len(area); {
+		t := area[pos]
+		pos++
+		switch t {
+		case tlvNull:
+			continue
+		case tlvTerminator:
+			return nil, ErrNoMessage
+		}
+		n, size, err := tlvLength(area[pos:])
+		if err != nil {
+			return nil, err
+		}
+		pos += size
+		if pos+n > len(area) {
+			return nil, fmt.Errorf("%w: TLV %02X com %d bytes passa do fim da área", ErrMalformed, t, n)
+		}
+		if t == tlvNDEF {
+			if n == 0 {
+				return nil, ErrNoMessage
+			}
+			return clone(area[pos : pos+n]), nil
+		}
+		pos += n
+	}
+	return nil, ErrNoMessage
+}
+
+// TLVSize bytes necessários para ler o primeiro TLV NDEF a partir do
+// início da área; 0 se head ainda não traz o cabeçalho dele.
+func TLVSize(head []byte) int {
+	for pos := 0; pos < len(head); {
+		t := head[pos]
+		pos++
+		switch t {
+		case tlvNull:
+			continue
+		case tlvTerminator:
+			return pos
+		}
+		n, size, err := tlvLength(head[pos:])
+		if err != nil {
+			return 0
+		}
+		pos += size + n
+		if t == tlvNDEF {
+			return pos
+		}
+	}
+	return 0
+}
+
+// tlvLength lê o campo de tamanho: 1 byte, ou FF + 2 bytes.
+func tlvLength(b []byte) (n, size int, err error) {
+	if len(b) < 1 {
+		return 0, 0, fmt.Errorf("%w: TLV truncado", ErrMalformed)
+	}
+	if b[0] != 0xFF {
+		return int(b[0]), 1, nil
+	}
+	if len(b) < 3 {
+		return 0, 0, fmt.Errorf("%w: TLV truncado", ErrMalformed)
+	}
+	return int(b[1])<<8 | int(b[2]), 3, nil
+}
+
+func clone(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,107 @@
+package ndef
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  []Record
+		esperado string
+	}{
+		{"mime curto", []Record{MediaRecord("a/b", []byte("{}"))}, "D20302612F627B7D"},
+		{"dois registros com ID", []Record{
+			{TNF: TNFWellKnown, Type: []byte("T"), ID: []byte("1"), Payload: []byte{0x02}},
+			MediaRecord("x/y", nil),
+		}, "99010101543102" + "520300782F79"},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			if got := strings.ToUpper(hex.EncodeToString(Encode(tt.entrada...))); got != tt.esperado {
+				t.Errorf("Encode = %s, esperado %s", got, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	long := bytes.Repeat([]byte("z"), 300)
+	in := []Record{
+		MediaRecord("application/json", []byte(`{"type":"PLA"}`)),
+		{TNF: TNFExternal, Type: []byte("cfs:spool"), ID: []byte("id"), Payload: long},
+	}
+	out, err := Decode(Encode(in...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("%d registros, esperado 2", len(out))
+	}
+	if !out[0].IsMedia("application/json") || string(out[0].Payload) != `{"type":"PLA"}` {
+		t.Errorf("registro 0 = %+v", out[0])
+	}
+	if out[1].TNF != TNFExternal || string(out[1].ID) != "id" || !bytes.Equal(out[1].Payload, long) {
+		t.Errorf("registro 1: TNF %d ID %q payload %d bytes", out[1].TNF, out[1].ID, len(out[1].Payload))
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  string
+		esperado error
+	}{
+		{"vazia", "", ErrNoMessage},
+		{"payload passa do fim", "D2030A612F627B7D", ErrMalformed},
+		{"cabeçalho truncado", "D2", ErrMalformed},
+		{"fragmentado", "F20302612F627B7D", ErrMalformed},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			msg, _ := hex.DecodeString(tt.entrada)
+			if _, err := Decode(msg); !errors.Is(err, tt.esperado) {
+				t.Errorf("Decode(%s) = %v, esperado %v", tt.entrada, err, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestTLV(t *testing.T) {
+	msg := Encode(MediaRecord("a/b", []byte("{}")))
+	long := Encode(MediaRecord("a/b", bytes.Repeat([]byte("z"), 300)))
+
+	testes := []struct {
+		nome     string
+		entrada  []byte
+		esperado []byte
+		erro     error
+	}{
+		{"só NDEF", WrapTLV(msg), msg, nil},
+		{"tamanho de 3 bytes", WrapTLV(long), long, nil},
+		{"lock e null antes", append([]byte{0x01, 0x03, 0xA0, 0x10, 0x44, 0x00}, WrapTLV(msg)...), msg, nil},
+		{"vazia", []byte{0x03, 0x00, 0xFE}, nil, ErrNoMessage},
+		{"só terminador", []byte{0xFE, 0x03, 0x01}, nil, ErrNoMessage},
+		{"truncada", WrapTLV(msg)[:5], nil, ErrMalformed},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			got, err := UnwrapTLV(tt.entrada)
+			if !errors.Is(err, tt.erro) || !bytes.Equal(got, tt.esperado) {
+				t.Errorf("UnwrapTLV = % X, %v; esperado % X, %v", got, err, tt.esperado, tt.erro)
+			}
+		})
+	}
+
+	area := WrapTLV(long)
+	if n := TLVSize(area[:16]); n != len(area)-1 {
+		t.Errorf("TLVSize = %d, esperado %d", n, len(area)-1)
+	}
+	if n := TLVSize([]byte{0x03}); n != 0 {
+		t.Errorf("TLVSize sem tamanho = %d, esperado 0", n)
+	}
+}
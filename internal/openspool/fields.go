@@ -0,0 +1,49 @@
+package openspool
+
+import (
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// FromFields converte campos CFS para OpenSpool; temperaturas 0 recebem a
+// faixa padrão do material.
+func FromFields(f creality.Fields, minTemp, maxTemp int) Spool {
+	typ := creality.BaseType(f.GetMaterialName())
+	dmin, dmax := creality.DefaultTemps(typ)
+	if minTemp == 0 {
+		minTemp = dmin
+	}
+	if maxTemp == 0 {
+		maxTemp = dmax
+	}
+	color := f.Color
+	if len(color) == 7 && color[0] == '0' {
+		color = color[1:]
+	}
+	return Spool{
+		Protocol: Protocol,
+		Version:  Version,
+		Type:     typ,
+		ColorHex: strings.ToUpper(color),
+		Brand:    f.Brand(),
+		MinTemp:  Temp(minTemp),
+		MaxTemp:  Temp(maxTemp),
+	}
+}
+
+// Fields campos CFS equivalentes: material genérico do tipo, fornecedor
+// Creality ou genérico, e cor. Data, comprimento e serial não existem no
+// OpenSpool e ficam vazios.
+func (s Spool) Fields() creality.Fields {
+	f := creality.NewFields()
+	f.Supplier = "0000"
+	if strings.EqualFold(s.Brand, "Creality") {
+		f.Supplier = "0276"
+	}
+	f.Material = creality.GenericCode(s.Type)
+	if len(s.ColorHex) == 6 {
+		f.Color = "0" + strings.ToUpper(s.ColorHex)
+	}
+	return f
+}
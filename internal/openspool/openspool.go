@@ -0,0 +1,96 @@
+package openspool
+
+// Formato OpenSpool: JSON num registro NDEF "application/json" em tags
+// NTAG215/NTAG216, lido por impressoras com firmware OpenSpool e Klipper.
+//
+//   {"protocol":"openspool","version":"1.0","type":"PLA",
+//    "color_hex":"FFAABB","brand":"Generic","min_temp":"220","max_temp":"240"}
+//
+// A especificação grava as temperaturas como texto; Decode aceita também
+// números.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+)
+
+const (
+	Protocol = "openspool"
+	Version  = "1.0"
+	MIME     = "application/json"
+)
+
+// ErrNotOpenSpool a mensagem NDEF não traz um registro OpenSpool.
+var ErrNotOpenSpool = errors.New("tag sem dados OpenSpool")
+
+// Spool conteúdo da tag OpenSpool.
+type Spool struct {
+	Protocol string `json:"protocol"`
+	Version  string `json:"version"`
+	Type     string `json:"type"`      // material base: PLA, PETG, ...
+	ColorHex string `json:"color_hex"` // 6 chars hex, sem #
+	Brand    string `json:"brand"`
+	MinTemp  Temp   `json:"min_temp"` // °C do bico
+	MaxTemp  Temp   `json:"max_temp"`
+}
+
+// Temp temperatura em °C, gravada como texto e lida de texto ou número.
+type Temp int
+
+func (t Temp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.Itoa(int(t)))
+}
+
+func (t *Temp) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(bytes.TrimSpace(b)), `"`)
+	if s == "" || s == "null" {
+		*t = 0
+		return nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("temperatura inválida %q", s)
+	}
+	*t = Temp(n)
+	return nil
+}
+
+// Encode monta a mensagem NDEF com o registro OpenSpool.
+func Encode(s Spool) ([]byte, error) {
+	s.Protocol, s.Version = Protocol, Version
+	s.ColorHex = strings.ToUpper(strings.TrimPrefix(s.ColorHex, "#"))
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return ndef.Encode(ndef.MediaRecord(MIME, payload)), nil
+}
+
+// Decode acha o registro OpenSpool na mensagem NDEF.
+func Decode(msg []byte) (*Spool, error) {
+	records, err := ndef.Decode(msg)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if !r.IsMedia(MIME) {
+			continue
+		}
+		var s Spool
+		if err := json.Unmarshal(r.Payload, &s); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotOpenSpool, err)
+		}
+		if !strings.EqualFold(s.Protocol, Protocol) {
+			continue
+		}
+		s.ColorHex = strings.ToUpper(strings.TrimPrefix(s.ColorHex, "#"))
+		return &s, nil
+	}
+	return nil, ErrNotOpenSpool
+}
@@ -0,0 +1,107 @@
+package openspool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	in := Spool{Type: "PETG", ColorHex: "#ffaabb", Brand: "Generic", MinTemp: 220, MaxTemp: 250}
+	msg, err := Encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, _ := ndef.Decode(msg)
+	want := `{"protocol":"openspool","version":"1.0","type":"PETG","color_hex":"FFAABB","brand":"Generic","min_temp":"220","max_temp":"250"}`
+	if len(records) != 1 || !records[0].IsMedia(MIME) || string(records[0].Payload) != want {
+		t.Fatalf("registro = %+v, esperado payload %s", records, want)
+	}
+
+	out, err := Decode(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Type != "PETG" || out.ColorHex != "FFAABB" || out.MinTemp != 220 || out.MaxTemp != 250 {
+		t.Errorf("Decode = %+v", out)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  []ndef.Record
+		esperado *Spool
+		erro     error
+	}{
+		{"temperaturas numéricas", []ndef.Record{
+			ndef.MediaRecord(MIME, []byte(`{"protocol":"openspool","version":"1.0","type":"PLA","color_hex":"#00ff00","brand":"Bambu","min_temp":200,"max_temp":230}`)),
+		}, &Spool{Protocol: "openspool", Version: "1.0", Type: "PLA", ColorHex: "00FF00", Brand: "Bambu", MinTemp: 200, MaxTemp: 230}, nil},
+		{"depois de um registro URI", []ndef.Record{
+			{TNF: ndef.TNFWellKnown, Type: []byte("U"), Payload: []byte("\x04example.com")},
+			ndef.MediaRecord(MIME, []byte(`{"protocol":"openspool","type":"ABS","color_hex":"000000"}`)),
+		}, &Spool{Protocol: "openspool", Type: "ABS", ColorHex: "000000"}, nil},
+		{"outro JSON", []ndef.Record{
+			ndef.MediaRecord(MIME, []byte(`{"protocol":"other","type":"PLA"}`)),
+		}, nil, ErrNotOpenSpool},
+		{"temperatura inválida", []ndef.Record{
+			ndef.MediaRecord(MIME, []byte(`{"protocol":"openspool","min_temp":"quente"}`)),
+		}, nil, ErrNotOpenSpool},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			got, err := Decode(ndef.Encode(tt.entrada...))
+			if !errors.Is(err, tt.erro) {
+				t.Fatalf("Decode erro = %v, esperado %v", err, tt.erro)
+			}
+			if tt.esperado != nil && *got != *tt.esperado {
+				t.Errorf("Decode = %+v, esperado %+v", *got, *tt.esperado)
+			}
+		})
+	}
+}
+
+func TestFromFields(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  creality.Fields
+		min, max int
+		esperado Spool
+	}{
+		{"Creality com temperaturas padrão", creality.Fields{Supplier: "0276", Material: "06002", Color: "077BB41"}, 0, 0,
+			Spool{Type: "PETG", ColorHex: "77BB41", Brand: "Creality", MinTemp: 220, MaxTemp: 250}},
+		{"eSUN com temperaturas informadas", creality.Fields{Supplier: "0276", Material: "E1001", Color: "0FFFFFF"}, 205, 225,
+			Spool{Type: "PLA", ColorHex: "FFFFFF", Brand: "eSUN", MinTemp: 205, MaxTemp: 225}},
+		{"fibra usa a faixa do material base", creality.Fields{Supplier: "0000", Material: "00015", Color: "0000000"}, 0, 0,
+			Spool{Type: "PA6-CF", ColorHex: "000000", Brand: "Generic", MinTemp: 250, MaxTemp: 280}},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			tt.esperado.Protocol, tt.esperado.Version = Protocol, Version
+			if got := FromFields(tt.entrada, tt.min, tt.max); got != tt.esperado {
+				t.Errorf("FromFields = %+v, esperado %+v", got, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestSpoolFields(t *testing.T) {
+	testes := []struct {
+		entrada  Spool
+		esperado creality.Fields
+	}{
+		{Spool{Type: "PLA", ColorHex: "ffaabb", Brand: "Creality"},
+			creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0276", Material: "00001", Color: "0FFAABB"}},
+		{Spool{Type: "PETG-CF", ColorHex: "000000", Brand: "Polymaker"},
+			creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0000", Material: "00014", Color: "0000000"}},
+		{Spool{Type: "PEEK", Brand: "Generic"},
+			creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0000"}},
+	}
+	for _, tt := range testes {
+		if got := tt.entrada.Fields(); got != tt.esperado {
+			t.Errorf("%+v.Fields() = %+v, esperado %+v", tt.entrada, got, tt.esperado)
+		}
+	}
+}
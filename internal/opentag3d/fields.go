@@ -0,0 +1,53 @@
+package opentag3d
+
+import (
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// defaultDiameter diâmetro CFS em µm, a unidade da tag.
+const defaultDiameter = creality.Diameter * 1000
+
+// FromFields converte campos CFS para OpenTag3D. As temperaturas vêm da
+// faixa padrão do material (meio da faixa no bico).
+func FromFields(f creality.Fields) Tag {
+	typ := creality.BaseType(f.GetMaterialName())
+	material, modifiers, _ := strings.Cut(typ, "-")
+	t := Tag{
+		Version:      Version,
+		Material:     material,
+		Modifiers:    modifiers,
+		Manufacturer: f.Brand(),
+		Diameter:     defaultDiameter,
+		Weight:       f.Grams(),
+		BedTemp:      creality.DefaultBedTemp(typ),
+	}
+	if min, max := creality.DefaultTemps(typ); max > 0 {
+		t.PrintTemp = (min + max) / 2 / 5 * 5
+	}
+	if len(f.Color) == 7 {
+		t.Colors[0], _ = ParseRGB(f.Color[1:])
+	}
+	return t
+}
+
+// Fields campos CFS equivalentes: material genérico do tipo (ou do
+// material base, se a variante não existir no CFS), fornecedor, cor e
+// comprimento pelo peso. Data e serial não existem no OpenTag3D.
+func (t Tag) Fields() creality.Fields {
+	f := creality.NewFields()
+	f.Supplier = "0000"
+	if strings.EqualFold(t.Manufacturer, "Creality") {
+		f.Supplier = "0276"
+	}
+	f.Material = creality.GenericCode(t.Type())
+	if f.Material == "" {
+		f.Material = creality.GenericCode(t.Material)
+	}
+	f.Color = "0" + t.Colors[0].Hex()
+	if t.Weight > 0 {
+		f.Length = creality.LengthFromGrams(t.Weight)
+	}
+	return f
+}
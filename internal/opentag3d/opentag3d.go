@@ -0,0 +1,217 @@
+package opentag3d
+
+// Formato OpenTag3D: bloco binário de tamanho fixo num registro NDEF
+// "application/opentag3d" (NTAG21x ou ISO 15693; aqui só NTAG, que é o
+// que os leitores do app enxergam).
+//
+// Layout do núcleo (v1.000), inteiros big-endian e textos UTF-8
+// completados com 00:
+//
+//   0x00  2  versão do formato (1000 = 1.000)
+//   0x02  5  material base          "PLA", "PETG", "PA6"
+//   0x07  5  modificadores          "CF", "Silk", "HS"
+//   0x0C 15  reservado
+//   0x1B 16  fabricante
+//   0x2B 32  nome da cor
+//   0x4B 16  cores 1–4, RGBA
+//   0x5B  1  reservado
+//   0x5C  2  diâmetro (µm)
+//   0x5E  2  peso do filamento (g)
+//   0x60  1  temperatura do bico (°C / 5)
+//   0x61  1  temperatura da mesa (°C / 5)
+//   0x62  2  densidade (mg/cm³)
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+)
+
+const (
+	MIME    = "application/opentag3d"
+	Version = 1000
+	Size    = 0x64 // bytes do núcleo
+)
+
+var (
+	ErrNotOpenTag3D = errors.New("tag sem dados OpenTag3D")
+	ErrShort        = errors.New("dados OpenTag3D menores que o núcleo")
+	ErrTooLong      = errors.New("campo não cabe no layout OpenTag3D")
+)
+
+// Campos de texto: deslocamento e tamanho.
+var (
+	fieldMaterial     = field{0x02, 5}
+	fieldModifiers    = field{0x07, 5}
+	fieldManufacturer = field{0x1B, 16}
+	fieldColorName    = field{0x2B, 32}
+)
+
+const (
+	offVersion   = 0x00
+	offColors    = 0x4B
+	offDiameter  = 0x5C
+	offWeight    = 0x5E
+	offPrintTemp = 0x60
+	offBedTemp   = 0x61
+	offDensity   = 0x62
+)
+
+type field struct{ off, size int }
+
+// RGBA cor com transparência (A = FF opaca).
+type RGBA [4]byte
+
+// ParseRGB cor de 6 hex (sem #), opaca.
+func ParseRGB(s string) (RGBA, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "#"))
+	if err != nil || len(b) != 3 {
+		return RGBA{}, fmt.Errorf("cor inválida %q", s)
+	}
+	return RGBA{b[0], b[1], b[2], 0xFF}, nil
+}
+
+// Hex cor sem o alfa, em 6 hex maiúsculos.
+func (c RGBA) Hex() string {
+	return strings.ToUpper(hex.EncodeToString(c[:3]))
+}
+
+// Tag conteúdo do núcleo OpenTag3D.
+type Tag struct {
+	Version      int
+	Material     string // material base
+	Modifiers    string
+	Manufacturer string
+	ColorName    string
+	Colors       [4]RGBA // Colors[0] é a cor principal
+	Diameter     int     // µm
+	Weight       int     // g
+	PrintTemp    int     // °C, múltiplo de 5
+	BedTemp      int     // °C, múltiplo de 5
+	Density      int     // mg/cm³
+}
+
+// Type material base com os modificadores ("PLA-CF").
+func (t Tag) Type() string {
+	if t.Modifiers == "" {
+		return t.Material
+	}
+	return t.Material + "-" + t.Modifiers
+}
+
+// Marshal monta o núcleo binário.
+func (t Tag) Marshal() ([]byte, error) {
+	b := make([]byte, Size)
+	version := t.Version
+	if version == 0 {
+		version = Version
+	}
+	binary.BigEndian.PutUint16(b[offVersion:], uint16(version))
+	for _, s := range []struct {
+		f     field
+		name  string
+		value string
+	}{
+		{fieldMaterial, "material", t.Material},
+		{fieldModifiers, "modificadores", t.Modifiers},
+		{fieldManufacturer, "fabricante", t.Manufacturer},
+		{fieldColorName, "nome da cor", t.ColorName},
+	} {
+		if len(s.value) > s.f.size {
+			return nil, fmt.Errorf("%w: %s %q (máx. %d bytes)", ErrTooLong, s.name, s.value, s.f.size)
+		}
+		copy(b[s.f.off:s.f.off+s.f.size], s.value)
+	}
+	for i, c := range t.Colors {
+		copy(b[offColors+4*i:], c[:])
+	}
+	for _, n := range []struct {
+		off   int
+		name  string
+		value int
+	}{
+		{offDiameter, "diâmetro", t.Diameter},
+		{offWeight, "peso", t.Weight},
+		{offDensity, "densidade", t.Density},
+	} {
+		if n.value < 0 || n.value > 0xFFFF {
+			return nil, fmt.Errorf("%w: %s %d", ErrTooLong, n.name, n.value)
+		}
+		binary.BigEndian.PutUint16(b[n.off:], uint16(n.value))
+	}
+	for _, n := range []struct {
+		off   int
+		name  string
+		value int
+	}{
+		{offPrintTemp, "temperatura do bico", t.PrintTemp},
+		{offBedTemp, "temperatura da mesa", t.BedTemp},
+	} {
+		if n.value < 0 || n.value > 255*5 {
+			return nil, fmt.Errorf("%w: %s %d °C", ErrTooLong, n.name, n.value)
+		}
+		b[n.off] = byte((n.value + 2) / 5)
+	}
+	return b, nil
+}
+
+// Unmarshal lê o núcleo; bytes além dele (extensões) são ignorados.
+func Unmarshal(b []byte) (*Tag, error) {
+	if len(b) < Size {
+		return nil, fmt.Errorf("%w: %d bytes", ErrShort, len(b))
+	}
+	t := &Tag{
+		Version:      int(binary.BigEndian.Uint16(b[offVersion:])),
+		Material:     text(b, fieldMaterial),
+		Modifiers:    text(b, fieldModifiers),
+		Manufacturer: text(b, fieldManufacturer),
+		ColorName:    text(b, fieldColorName),
+		Diameter:     int(binary.BigEndian.Uint16(b[offDiameter:])),
+		Weight:       int(binary.BigEndian.Uint16(b[offWeight:])),
+		PrintTemp:    int(b[offPrintTemp]) * 5,
+		BedTemp:      int(b[offBedTemp]) * 5,
+		Density:      int(binary.BigEndian.Uint16(b[offDensity:])),
+	}
+	for i := range t.Colors {
+		copy(t.Colors[i][:], b[offColors+4*i:])
+	}
+	return t, nil
+}
+
+// text campo de texto sem o preenchimento; bytes inválidos são trocados.
+func text(b []byte, f field) string {
+	raw := bytes.TrimRight(b[f.off:f.off+f.size], "\x00 ")
+	if !utf8.Valid(raw) {
+		return strings.ToValidUTF8(string(raw), "?")
+	}
+	return string(raw)
+}
+
+// Encode monta a mensagem NDEF com o núcleo.
+func Encode(t Tag) ([]byte, error) {
+	b, err := t.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return ndef.Encode(ndef.MediaRecord(MIME, b)), nil
+}
+
+// Decode acha o registro OpenTag3D na mensagem NDEF.
+func Decode(msg []byte) (*Tag, error) {
+	records, err := ndef.Decode(msg)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if r.IsMedia(MIME) {
+			return Unmarshal(r.Payload)
+		}
+	}
+	return nil, ErrNotOpenTag3D
+}
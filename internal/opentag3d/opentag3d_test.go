@@ -0,0 +1,130 @@
+package opentag3d
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+)
+
+var testTag = Tag{
+	Version:      Version,
+	Material:     "PLA",
+	Modifiers:    "CF",
+	Manufacturer: "Polymaker",
+	ColorName:    "Jet Black",
+	Colors:       [4]RGBA{{0x11, 0x22, 0x33, 0xFF}},
+	Diameter:     1750,
+	Weight:       1000,
+	PrintTemp:    215,
+	BedTemp:      60,
+	Density:      1240,
+}
+
+func TestMarshalLayout(t *testing.T) {
+	b, err := testTag.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		campo    string
+		off, n   int
+		esperado string
+	}{
+		{"versão", 0x00, 2, "03E8"},
+		{"material", 0x02, 5, hex.EncodeToString([]byte("PLA\x00\x00"))},
+		{"modificadores", 0x07, 5, hex.EncodeToString([]byte("CF\x00\x00\x00"))},
+		{"fabricante", 0x1B, 9, hex.EncodeToString([]byte("Polymaker"))},
+		{"cor 1", 0x4B, 4, "112233FF"},
+		{"diâmetro", 0x5C, 2, "06D6"},
+		{"peso", 0x5E, 2, "03E8"},
+		{"bico", 0x60, 1, "2B"},
+		{"mesa", 0x61, 1, "0C"},
+		{"densidade", 0x62, 2, "04D8"},
+	}
+	if len(b) != Size {
+		t.Fatalf("%d bytes, esperado %d", len(b), Size)
+	}
+	for _, tt := range testes {
+		if got := hex.EncodeToString(b[tt.off : tt.off+tt.n]); !strings.EqualFold(got, tt.esperado) {
+			t.Errorf("%s = %s, esperado %s", tt.campo, got, tt.esperado)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	msg, err := Encode(testTag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Decode(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != testTag {
+		t.Errorf("Decode = %+v, esperado %+v", *got, testTag)
+	}
+
+	// Extensões depois do núcleo são ignoradas
+	b, _ := testTag.Marshal()
+	ext := ndef.Encode(ndef.MediaRecord(MIME, append(b, bytes.Repeat([]byte{0xAA}, 40)...)))
+	if got, err := Decode(ext); err != nil || got.Type() != "PLA-CF" {
+		t.Errorf("Decode com extensão = %+v, %v", got, err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  []byte
+		esperado error
+	}{
+		{"outro MIME", ndef.Encode(ndef.MediaRecord("application/json", []byte("{}"))), ErrNotOpenTag3D},
+		{"núcleo curto", ndef.Encode(ndef.MediaRecord(MIME, make([]byte, 20))), ErrShort},
+	}
+	for _, tt := range testes {
+		if _, err := Decode(tt.entrada); !errors.Is(err, tt.esperado) {
+			t.Errorf("%s: %v, esperado %v", tt.nome, err, tt.esperado)
+		}
+	}
+	long := testTag
+	long.Manufacturer = "Fabricante Com Nome Longo"
+	if _, err := long.Marshal(); !errors.Is(err, ErrTooLong) {
+		t.Errorf("fabricante longo: %v", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  Tag
+		esperado creality.Fields
+	}{
+		{"variante existente", testTag,
+			creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0000", Material: "00006", Color: "0112233", Length: "014A"}},
+		{"variante desconhecida usa o material base", Tag{Material: "PETG", Modifiers: "HS", Manufacturer: "Creality"},
+			creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0276", Material: "00003", Color: "0000000"}},
+	}
+	for _, tt := range testes {
+		if got := tt.entrada.Fields(); got != tt.esperado {
+			t.Errorf("%s: Fields = %+v, esperado %+v", tt.nome, got, tt.esperado)
+		}
+	}
+}
+
+func TestFromFields(t *testing.T) {
+	f := creality.Fields{Supplier: "0276", Material: "02001", Color: "077BB41", Length: "00A5"}
+	got := FromFields(f)
+	want := Tag{Version: Version, Material: "PLA", Modifiers: "CF", Manufacturer: "Creality",
+		Colors: [4]RGBA{{0x77, 0xBB, 0x41, 0xFF}}, Diameter: 1750, Weight: 500, PrintTemp: 205, BedTemp: 60}
+	if got != want {
+		t.Errorf("FromFields = %+v, esperado %+v", got, want)
+	}
+	if back := got.Fields(); back.Material != "00006" || back.Color != f.Color || back.Length != f.Length {
+		t.Errorf("ida e volta = %+v", back)
+	}
+}
@@ -0,0 +1,137 @@
+package queue
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// columns nomes aceitos no cabeçalho do CSV (sem diferenciar maiúsculas).
+var columns = map[string]string{
+	"date": "date", "data": "date",
+	"supplier": "supplier", "vendor": "supplier", "fornecedor": "supplier",
+	"material": "material",
+	"color":    "color", "colour": "color", "cor": "color",
+	"length": "length", "weight": "length", "comprimento": "length", "peso": "length",
+	"serial": "serial", "serie": "serial", "série": "serial",
+	"min_temp": "min_temp", "temp_min": "min_temp",
+	"max_temp": "max_temp", "temp_max": "max_temp",
+	"format": "format", "formato": "format",
+	"spoolman_id": "spoolman_id", "spoolman": "spoolman_id",
+}
+
+// ErrNoColumn cabeçalho sem uma coluna obrigatória (material, color).
+var ErrNoColumn = errors.New("coluna obrigatória ausente no CSV")
+
+// ParseCSV lê a fila de um CSV com cabeçalho. O separador é vírgula ou
+// ponto e vírgula (Excel em português); linhas iniciadas por # são
+// ignoradas.
+func ParseCSV(r io.Reader) (*Queue, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4096)
+	first, _, _ := strings.Cut(string(head), "\n")
+
+	cr := csv.NewReader(br)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		cr.Comma = ';'
+	}
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: CSV vazio", ErrNoColumn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if col, ok := columns[name]; ok {
+			index[col] = i
+		}
+	}
+	for _, col := range []string{"material", "color"} {
+		if _, ok := index[col]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNoColumn, col)
+		}
+	}
+
+	q := newQueue()
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(col string) string {
+			if i, ok := index[col]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		e := Entry{
+			Date:     get("date"),
+			Supplier: get("supplier"),
+			Material: get("material"),
+			Color:    strings.TrimPrefix(get("color"), "#"),
+			Length:   get("length"),
+			Serial:   get("serial"),
+			Format:   strings.ToLower(get("format")),
+		}
+		line, _ := cr.FieldPos(0)
+		for col, dst := range map[string]*int{"min_temp": &e.MinTemp, "max_temp": &e.MaxTemp, "spoolman_id": &e.SpoolmanID} {
+			if v := get(col); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, fmt.Errorf("linha %d: %s inválido %q", line, col, v)
+				}
+				*dst = n
+			}
+		}
+		if e == (Entry{}) {
+			continue
+		}
+		q.items = append(q.items, &Item{Entry: e, Line: line, Status: StatusPending})
+	}
+	return q, nil
+}
+
+// reportHeader colunas do relatório gerado por WriteReport.
+var reportHeader = []string{"line", "date", "supplier", "material", "color", "length", "serial",
+	"status", "uid", "attempts", "error", "at"}
+
+// WriteReport grava o resultado de cada entrada em CSV.
+func (q *Queue) WriteReport(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reportHeader); err != nil {
+		return err
+	}
+	for _, it := range q.Items() {
+		at := ""
+		if !it.At.IsZero() {
+			at = it.At.Format(time.RFC3339)
+		}
+		line := ""
+		if it.Line > 0 {
+			line = strconv.Itoa(it.Line)
+		}
+		e := it.Entry
+		rec := []string{line, e.Date, e.Supplier, e.Material, e.Color, e.Length, e.Serial,
+			string(it.Status), it.UID, strconv.Itoa(it.Attempts), it.Error, at}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
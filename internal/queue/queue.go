@@ -0,0 +1,241 @@
+package queue
+
+// Fila de produção: várias bobinas gravadas em sequência, uma por tag.
+//
+//   q, _ := queue.ParseCSV(f)          // ou queue.New(entries) a partir da UI
+//   i, e, err := q.Next(uid)           // entrada a gravar na tag uid
+//   ... grava e confere ...
+//   q.Done(i, uid)                     // ou q.Fail(i, uid, err)
+//   q.WriteReport(w)                   // CSV com o resultado de cada linha
+//
+// A fila segue a ordem das entradas: a primeira pendente (ou que falhou) é
+// a próxima. Uma tag já gravada pela fila é recusada (ErrUIDUsed) para não
+// gravar duas bobinas na mesma tag.
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status situação de uma entrada da fila.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed" // volta a ser tentada na próxima tag
+	StatusSkipped Status = "skipped"
+)
+
+var (
+	ErrEmpty   = errors.New("fila concluída")
+	ErrUIDUsed = errors.New("tag já gravada por esta fila")
+	ErrIndex   = errors.New("entrada inexistente")
+	ErrStatus  = errors.New("operação inválida para a situação da entrada")
+)
+
+// Entry dados de uma bobina, nos mesmos campos do formulário de gravação.
+type Entry struct {
+	Date     string `json:"date"`     // YYYY-MM-DD ("" = hoje)
+	Supplier string `json:"supplier"` // código 4 chars
+	Material string `json:"material"` // código 5 chars
+	Color    string `json:"color"`    // 6 chars hex
+	Length   string `json:"length"`   // código 4 chars ou gramas
+	Serial   string `json:"serial"`   // até 6 dígitos
+	MinTemp  int    `json:"minTemp"`  // °C do bico em tags OpenSpool (0 = padrão)
+	MaxTemp  int    `json:"maxTemp"`
+	Format   string `json:"format,omitempty"` // formato gravável do registro; "" = "cfs" (segue a tag)
+
+	SpoolmanID int `json:"spoolmanId,omitempty"` // bobina do Spoolman que recebe o UID da tag (0 = nenhuma)
+}
+
+// Item entrada da fila com o resultado da gravação.
+type Item struct {
+	Entry    Entry     `json:"entry"`
+	Line     int       `json:"line,omitempty"` // linha no CSV (0 = lista da UI)
+	Status   Status    `json:"status"`
+	UID      string    `json:"uid,omitempty"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+	At       time.Time `json:"at,omitempty"` // última tentativa
+}
+
+// Progress contagem por situação; Current é o índice da próxima entrada
+// (-1 com a fila concluída).
+type Progress struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+	Pending int `json:"pending"`
+	Current int `json:"current"`
+}
+
+// Queue fila de produção; segura para uso concorrente.
+type Queue struct {
+	mu    sync.Mutex
+	items []*Item
+	used  map[string]int // UID → entrada gravada nela
+	now   func() time.Time
+}
+
+// New cria a fila com as entradas na ordem dada.
+func New(entries []Entry) *Queue {
+	q := newQueue()
+	for _, e := range entries {
+		q.items = append(q.items, &Item{Entry: e, Status: StatusPending})
+	}
+	return q
+}
+
+func newQueue() *Queue {
+	return &Queue{used: map[string]int{}, now: time.Now}
+}
+
+// Len número de entradas.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Next devolve a entrada a gravar na tag uid e conta a tentativa.
+func (q *Queue) Next(uid string) (int, Entry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	uid = strings.ToUpper(uid)
+	if i, ok := q.used[uid]; ok {
+		return -1, Entry{}, fmt.Errorf("%w: UID %s%s", ErrUIDUsed, uid, q.items[i].where(i))
+	}
+	i := q.current()
+	if i < 0 {
+		return -1, Entry{}, ErrEmpty
+	}
+	it := q.items[i]
+	it.Attempts++
+	it.UID = uid
+	it.At = q.now()
+	return i, it.Entry, nil
+}
+
+// Done marca a entrada i como gravada na tag uid.
+func (q *Queue) Done(i int, uid string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if i < 0 || i >= len(q.items) {
+		return ErrIndex
+	}
+	it := q.items[i]
+	it.Status, it.UID, it.Error, it.At = StatusDone, strings.ToUpper(uid), "", q.now()
+	q.used[it.UID] = i
+	return nil
+}
+
+// Fail registra a falha da entrada i; ela continua sendo a próxima.
+func (q *Queue) Fail(i int, uid string, err error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if i < 0 || i >= len(q.items) {
+		return ErrIndex
+	}
+	it := q.items[i]
+	it.Status, it.UID, it.At = StatusFailed, strings.ToUpper(uid), q.now()
+	if err != nil {
+		it.Error = err.Error()
+	}
+	return nil
+}
+
+// Skip pula a entrada i (pendente ou com falha).
+func (q *Queue) Skip(i int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if i < 0 || i >= len(q.items) {
+		return ErrIndex
+	}
+	it := q.items[i]
+	if it.Status != StatusPending && it.Status != StatusFailed {
+		return fmt.Errorf("%w: %s", ErrStatus, it.Status)
+	}
+	it.Status = StatusSkipped
+	return nil
+}
+
+// Retry devolve a entrada i à fila. Uma entrada já gravada libera a tag
+// para ser regravada com ela.
+func (q *Queue) Retry(i int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if i < 0 || i >= len(q.items) {
+		return ErrIndex
+	}
+	it := q.items[i]
+	if it.Status == StatusPending {
+		return fmt.Errorf("%w: %s", ErrStatus, it.Status)
+	}
+	if it.Status == StatusDone && q.used[it.UID] == i {
+		delete(q.used, it.UID)
+	}
+	it.Status, it.Error = StatusPending, ""
+	return nil
+}
+
+// Finished indica que não há entradas pendentes nem com falha.
+func (q *Queue) Finished() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.current() < 0
+}
+
+// Items cópia das entradas.
+func (q *Queue) Items() []Item {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]Item, len(q.items))
+	for i, it := range q.items {
+		out[i] = *it
+	}
+	return out
+}
+
+// Progress contagem atual.
+func (q *Queue) Progress() Progress {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	p := Progress{Total: len(q.items), Current: q.current()}
+	for _, it := range q.items {
+		switch it.Status {
+		case StatusDone:
+			p.Done++
+		case StatusFailed:
+			p.Failed++
+		case StatusSkipped:
+			p.Skipped++
+		default:
+			p.Pending++
+		}
+	}
+	return p
+}
+
+// current primeira entrada pendente ou com falha (-1 se não houver).
+func (q *Queue) current() int {
+	for i, it := range q.items {
+		if it.Status == StatusPending || it.Status == StatusFailed {
+			return i
+		}
+	}
+	return -1
+}
+
+// where identifica a entrada nas mensagens: linha do CSV ou posição.
+func (it *Item) where(i int) string {
+	if it.Line > 0 {
+		return fmt.Sprintf(" (linha %d)", it.Line)
+	}
+	return fmt.Sprintf(" (entrada %d)", i+1)
+}
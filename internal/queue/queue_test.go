@@ -0,0 +1,186 @@
+package queue
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCSV = `date,supplier,material,color,length,serial
+2026-04-12,0276,04001,#77BB41,1000,1
+# comentário
+,ESUN,E1001,FFFFFF,0330,2
+
+2026-04-13,POLY,P1001,000000,500,3
+`
+
+func TestParseCSV(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  string
+		esperado []Item
+	}{
+		{"vírgula", testCSV, []Item{
+			{Line: 2, Entry: Entry{"2026-04-12", "0276", "04001", "77BB41", "1000", "1", 0, 0, "", 0}},
+			{Line: 4, Entry: Entry{"", "ESUN", "E1001", "FFFFFF", "0330", "2", 0, 0, "", 0}},
+			{Line: 6, Entry: Entry{"2026-04-13", "POLY", "P1001", "000000", "500", "3", 0, 0, "", 0}},
+		}},
+		{"ponto e vírgula em português", "\ufeffMaterial;Cor;Peso\n04001;77BB41;1000\n", []Item{
+			{Line: 2, Entry: Entry{Material: "04001", Color: "77BB41", Length: "1000"}},
+		}},
+		{"temperaturas OpenSpool", "material,color,min_temp,max_temp\n00003,FFFFFF,225,245\n00001,000000,,\n", []Item{
+			{Line: 2, Entry: Entry{Material: "00003", Color: "FFFFFF", MinTemp: 225, MaxTemp: 245}},
+			{Line: 3, Entry: Entry{Material: "00001", Color: "000000"}},
+		}},
+		{"formato de destino", "material,color,formato\n00003,FFFFFF,ACE\n00001,000000,\n", []Item{
+			{Line: 2, Entry: Entry{Material: "00003", Color: "FFFFFF", Format: "ace"}},
+			{Line: 3, Entry: Entry{Material: "00001", Color: "000000"}},
+		}},
+		{"bobina do Spoolman", "material,color,spoolman_id\n00003,FFFFFF,42\n", []Item{
+			{Line: 2, Entry: Entry{Material: "00003", Color: "FFFFFF", SpoolmanID: 42}},
+		}},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			q, err := ParseCSV(strings.NewReader(tt.entrada))
+			if err != nil {
+				t.Fatal(err)
+			}
+			items := q.Items()
+			if len(items) != len(tt.esperado) {
+				t.Fatalf("%d entradas, esperado %d", len(items), len(tt.esperado))
+			}
+			for i, want := range tt.esperado {
+				if items[i].Entry != want.Entry || items[i].Line != want.Line || items[i].Status != StatusPending {
+					t.Errorf("entrada %d = %+v, esperado %+v", i, items[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado string
+	}{
+		{"date,color\n2026-01-01,FFFFFF\n", "material"},
+		{"material,length\n04001,1000\n", "color"},
+		{"", "vazio"},
+	}
+	for _, tt := range testes {
+		_, err := ParseCSV(strings.NewReader(tt.entrada))
+		if !errors.Is(err, ErrNoColumn) || !strings.Contains(err.Error(), tt.esperado) {
+			t.Errorf("ParseCSV(%q) = %v, esperado erro com %q", tt.entrada, err, tt.esperado)
+		}
+	}
+}
+
+func TestQueueFlow(t *testing.T) {
+	q := New([]Entry{{Material: "04001"}, {Material: "04002"}, {Material: "04003"}})
+
+	// 1ª tag: gravada
+	i, e, err := q.Next("a1b2c3d4")
+	if err != nil || i != 0 || e.Material != "04001" {
+		t.Fatalf("Next = %d %+v %v", i, e, err)
+	}
+	q.Done(i, "a1b2c3d4")
+
+	// A mesma tag de novo é recusada
+	if _, _, err := q.Next("A1B2C3D4"); !errors.Is(err, ErrUIDUsed) {
+		t.Errorf("UID repetido: esperado ErrUIDUsed, obtido %v", err)
+	}
+
+	// 2ª tag falha: a entrada continua sendo a próxima
+	i, _, _ = q.Next("11223344")
+	q.Fail(i, "11223344", errors.New("tag removida"))
+	if i2, _, _ := q.Next("11223344"); i2 != 1 {
+		t.Errorf("após a falha a próxima é %d, esperado 1", i2)
+	}
+	if it := q.Items()[1]; it.Attempts != 2 || it.Status != StatusFailed || it.Error != "tag removida" {
+		t.Errorf("entrada com falha = %+v", it)
+	}
+
+	// Pular a entrada com falha passa para a 3ª
+	if err := q.Skip(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Skip(1); !errors.Is(err, ErrStatus) {
+		t.Errorf("Skip repetido: %v", err)
+	}
+	i, e, _ = q.Next("55667788")
+	if i != 2 || e.Material != "04003" {
+		t.Errorf("Next após Skip = %d %+v", i, e)
+	}
+	q.Done(i, "55667788")
+
+	if !q.Finished() {
+		t.Error("fila deveria estar concluída")
+	}
+	if _, _, err := q.Next("99AABBCC"); !errors.Is(err, ErrEmpty) {
+		t.Errorf("fila concluída: %v", err)
+	}
+	want := Progress{Total: 3, Done: 2, Skipped: 1, Current: -1}
+	if p := q.Progress(); p != want {
+		t.Errorf("Progress = %+v, esperado %+v", p, want)
+	}
+
+	// Retry de uma entrada gravada libera a tag para ela
+	if err := q.Retry(0); err != nil {
+		t.Fatal(err)
+	}
+	if i, _, err := q.Next("A1B2C3D4"); err != nil || i != 0 {
+		t.Errorf("Next após Retry = %d, %v", i, err)
+	}
+	if err := q.Retry(9); !errors.Is(err, ErrIndex) {
+		t.Errorf("Retry fora da fila: %v", err)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	q, err := ParseCSV(strings.NewReader(testCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 4, 12, 10, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return at }
+	i, _, _ := q.Next("A1B2C3D4")
+	q.Done(i, "A1B2C3D4")
+	q.Skip(1)
+
+	var buf bytes.Buffer
+	if err := q.WriteReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		linha    int
+		esperado string
+	}{
+		{0, strings.Join(reportHeader, ",")},
+		{1, "2,2026-04-12,0276,04001,77BB41,1000,1,done,A1B2C3D4,1,,2026-04-12T10:00:00Z"},
+		{2, "4,,ESUN,E1001,FFFFFF,0330,2,skipped,,0,,"},
+		{3, "6,2026-04-13,POLY,P1001,000000,500,3,pending,,0,,"},
+	}
+	if len(recs) != len(testes) {
+		t.Fatalf("%d linhas no relatório", len(recs))
+	}
+	for _, tt := range testes {
+		if got := strings.Join(recs[tt.linha], ","); got != tt.esperado {
+			t.Errorf("linha %d = %q, esperado %q", tt.linha, got, tt.esperado)
+		}
+	}
+}
+
+func TestParseCSVInvalidTemp(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("material,color,max_temp\n00001,FFFFFF,quente\n"))
+	if err == nil || !strings.Contains(err.Error(), "linha 2") || !strings.Contains(err.Error(), "max_temp") {
+		t.Errorf("temperatura inválida: %v", err)
+	}
+}
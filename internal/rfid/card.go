@@ -0,0 +1,291 @@
+package rfid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CardType família do cartão identificada pelo ATR/SAK.
+type CardType string
+
+const (
+	CardUnknown    CardType = "unknown"
+	CardClassic1K  CardType = "classic1k"
+	CardClassic4K  CardType = "classic4k"
+	CardMini       CardType = "mini"
+	CardUltralight CardType = "ultralight" // inclui NTAG21x
+	CardDESFire    CardType = "desfire"
+	CardISO14443_4 CardType = "iso14443-4"
+)
+
+var cardNames = map[CardType]string{
+	CardUnknown:    "desconhecido",
+	CardClassic1K:  "MIFARE Classic 1K",
+	CardClassic4K:  "MIFARE Classic 4K",
+	CardMini:       "MIFARE Mini",
+	CardUltralight: "MIFARE Ultralight / NTAG",
+	CardDESFire:    "MIFARE DESFire",
+	CardISO14443_4: "ISO 14443-4",
+}
+
+// Magic tipo de clone "mágico" (UID regravável).
+type Magic string
+
+const (
+	MagicNone  Magic = ""
+	MagicGen1a Magic = "gen1a" // backdoor 40/43, bloco 0 gravável sem key
+	// MagicGen2Probable padrão de fabricante dos clones Gen2/CUID no bloco
+	// 0; sem gravar o bloco 0 não há como confirmar
+	MagicGen2Probable Magic = "gen2?"
+)
+
+// CardInfo descritor do cartão presente no leitor.
+type CardInfo struct {
+	Type  CardType `json:"type"`
+	Name  string   `json:"name"`
+	UID   string   `json:"uid"`
+	ATR   string   `json:"atr"`
+	ATQA  string   `json:"atqa,omitempty"`
+	SAK   string   `json:"sak,omitempty"`
+	Magic Magic    `json:"magic,omitempty"`
+}
+
+// Supported retorna ErrUnsupportedCard (com o motivo) se o cartão não pode
+// receber uma tag CFS. O setor 1 do 4K é igual ao do 1K. Tipo desconhecido
+// passa: leitores que não informam ATR nem SAK continuam funcionando.
+func (c *CardInfo) Supported() error {
+	switch c.Type {
+	case CardClassic1K, CardClassic4K, CardUnknown:
+	default:
+		return fmt.Errorf("%w: %s (o CFS usa MIFARE Classic 1K/4K)", ErrUnsupportedCard, c.Name)
+	}
+	if len(c.UID) != 8 {
+		return fmt.Errorf("%w: UID de %d bytes (a key CFS é derivada de UID de 4 bytes)", ErrUnsupportedCard, len(c.UID)/2)
+	}
+	return nil
+}
+
+// atrTransport Transport que conhece o ATR do cartão conectado.
+type atrTransport interface {
+	ATR() ([]byte, error)
+}
+
+// pcscRID identificador PC/SC no ATR de cartões de memória sem contato
+// (PC/SC parte 3): 3B 8F 80 01 80 4F 0C A0 00 00 03 06 <SS> <NN NN> ...
+var pcscRID = []byte{0xA0, 0x00, 0x00, 0x03, 0x06}
+
+// ParseATR identifica o cartão pelo ATR montado pelo leitor PC/SC.
+func ParseATR(atr []byte) CardType {
+	if len(atr) >= 15 && atr[0] == 0x3B && atr[4] == 0x80 && atr[5] == 0x4F &&
+		bytes.Equal(atr[7:12], pcscRID) {
+		switch uint16(atr[13])<<8 | uint16(atr[14]) {
+		case 0x0001:
+			return CardClassic1K
+		case 0x0002:
+			return CardClassic4K
+		case 0x0003, 0x003A:
+			return CardUltralight
+		case 0x0026:
+			return CardMini
+		}
+		return CardUnknown
+	}
+	// ISO 14443-4: 3B 8n 80 01 <bytes históricos> TCK
+	if len(atr) >= 4 && atr[0] == 0x3B && atr[1]&0xF0 == 0x80 && atr[2] == 0x80 && atr[3] == 0x01 {
+		n := int(atr[1] & 0x0F)
+		if len(atr) < 4+n {
+			return CardUnknown
+		}
+		hist := atr[4 : 4+n]
+		if bytes.Equal(hist, []byte{0x80}) || bytes.HasPrefix(hist, []byte{0x75, 0x77, 0x81, 0x02}) {
+			return CardDESFire
+		}
+		return CardISO14443_4
+	}
+	return CardUnknown
+}
+
+// CardTypeFromSAK identifica o cartão por ATQA/SAK (NXP AN10833).
+func CardTypeFromSAK(atqa uint16, sak byte) CardType {
+	switch sak {
+	case 0x08, 0x28, 0x88:
+		return CardClassic1K
+	case 0x18, 0x38, 0x98:
+		return CardClassic4K
+	case 0x09:
+		return CardMini
+	case 0x00:
+		return CardUltralight
+	case 0x20:
+		if atqa == 0x0344 {
+			return CardDESFire
+		}
+		return CardISO14443_4
+	}
+	return CardUnknown
+}
+
+// cardCache último cartão identificado numa Session; vale até a tag sair
+// do leitor, para as leituras seguintes não re-selecionarem o cartão.
+type cardCache struct {
+	mu   sync.Mutex
+	info *CardInfo
+}
+
+func (c *cardCache) get(uid string) *CardInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.info == nil || !strings.EqualFold(c.info.UID, uid) {
+		return nil
+	}
+	info := *c.info
+	return &info
+}
+
+func (c *cardCache) put(info *CardInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	saved := *info
+	c.info = &saved
+}
+
+func (c *cardCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info = nil
+}
+
+// Identify monta o descritor do cartão a partir do UID (GET DATA), do ATR
+// e, em leitores PN532 como o ACR122U, do ATQA/SAK da seleção. Na Session
+// o descritor fica guardado enquanto a mesma tag estiver no leitor.
+func (r *Reader) Identify() (*CardInfo, error) {
+	uid, err := r.UID()
+	if err != nil {
+		return nil, err
+	}
+	if r.cards != nil {
+		if info := r.cards.get(uid); info != nil {
+			return info, nil
+		}
+	}
+	info := &CardInfo{Type: CardUnknown, UID: strings.ToUpper(uid)}
+
+	if t, ok := r.t.(atrTransport); ok {
+		if atr, err := t.ATR(); err == nil {
+			info.ATR = strings.ToUpper(hex.EncodeToString(atr))
+			info.Type = ParseATR(atr)
+		}
+	}
+
+	// ATQA/SAK é opcional: outros leitores não entendem o comando PN532
+	if atqa, sak, err := r.selectTarget(); err == nil {
+		info.ATQA = fmt.Sprintf("%04X", atqa)
+		info.SAK = fmt.Sprintf("%02X", sak)
+		if t := CardTypeFromSAK(atqa, sak); t != CardUnknown &&
+			(info.Type == CardUnknown || info.Type == CardISO14443_4) {
+			info.Type = t
+		}
+	}
+
+	info.Name = cardNames[info.Type]
+	if r.cards != nil {
+		r.cards.put(info)
+	}
+	return info, nil
+}
+
+// selectTarget (re)seleciona o cartão com InListPassiveTarget (106 kbps
+// tipo A) e devolve ATQA e SAK.
+func (r *Reader) selectTarget() (atqa uint16, sak byte, err error) {
+	resp, err := r.pn532(0x4A, 0x01, 0x00)
+	if err != nil {
+		return 0, 0, err
+	}
+	// NbTg Tg ATQA(2) SAK NFCIDLength NFCID...
+	if len(resp) < 6 || resp[0] == 0 {
+		return 0, 0, &StatusError{Op: "pn532", Block: -1, Err: ErrCardRemoved}
+	}
+	return uint16(resp[2])<<8 | uint16(resp[3]), resp[4], nil
+}
+
+// DetectMagic testa se o cartão é um clone "mágico" sem gravar nada nele.
+// Gen1a responde ao comando backdoor 40 (7 bits) após HALT; Gen2/CUID só é
+// reconhecido como provável, pelo padrão de fabricante dos clones no
+// bloco 0. Exige leitor PN532 (ACR122U); o cartão fica re-selecionado ao
+// final.
+func (r *Reader) DetectMagic() (Magic, error) {
+	gen1a, err := r.probeGen1a()
+	if err != nil {
+		return MagicNone, err
+	}
+	if gen1a {
+		return MagicGen1a, nil
+	}
+
+	magic := MagicNone
+	if r.auth(0, KeyTypeA, DefaultKey) == nil {
+		if b0, err := r.readBlock(0); err == nil && isCloneBlock0(b0) {
+			magic = MagicGen2Probable
+		}
+	}
+	r.UID()
+	return magic, nil
+}
+
+// probeGen1a envia HALT e o comando 40 de 7 bits com CRC desligado.
+// Um Gen1a responde com o ACK de 4 bits 0A.
+func (r *Reader) probeGen1a() (bool, error) {
+	// CIU_TxMode/CIU_RxMode (63 02/63 03) sem CRC
+	if _, err := r.pn532(0x08, 0x63, 0x02, 0x00, 0x63, 0x03, 0x00); err != nil {
+		return false, err
+	}
+	defer func() {
+		// Restaurar CRC e bit framing e reiniciar o campo para re-selecionar
+		r.pn532(0x08, 0x63, 0x02, 0x80, 0x63, 0x03, 0x80, 0x63, 0x3D, 0x00)
+		r.pn532(0x32, 0x01, 0x00)
+		r.pn532(0x32, 0x01, 0x01)
+		r.selectTarget()
+	}()
+
+	// HALT (50 00 + CRC): o cartão não responde
+	r.pn532(0x42, 0x50, 0x00, 0x57, 0xCD)
+
+	// CIU_BitFraming (63 3D): 7 bits no último byte
+	if _, err := r.pn532(0x08, 0x63, 0x3D, 0x07); err != nil {
+		return false, err
+	}
+	resp, err := r.pn532(0x42, 0x40)
+	if err != nil {
+		return false, err
+	}
+	// Status 00 + ACK 0A
+	return len(resp) == 2 && resp[0] == 0x00 && resp[1]&0x0F == 0x0A, nil
+}
+
+// cloneManufacturer dados de fabricante gravados de fábrica nos clones
+// chineses (bytes 8–15 do bloco 0).
+const cloneManufacturer = "6263646566676869"
+
+func isCloneBlock0(block0Hex string) bool {
+	return len(block0Hex) == 32 && strings.EqualFold(block0Hex[16:], cloneManufacturer)
+}
+
+// pn532 envia um comando PN532 pelo pseudo-APDU FF 00 00 00 (Direct
+// Transmit do ACR122U) e devolve a resposta sem o cabeçalho D5 xx.
+func (r *Reader) pn532(cmd ...byte) ([]byte, error) {
+	apdu := append([]byte{0xFF, 0x00, 0x00, 0x00, byte(len(cmd) + 1), 0xD4}, cmd...)
+	resp, err := r.transmit(apdu)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSW(resp, "pn532", -1); err != nil {
+		return nil, err
+	}
+	if len(resp) < 4 || resp[0] != 0xD5 || resp[1] != cmd[0]+1 {
+		return nil, &StatusError{Op: "pn532", Block: -1, SW1: 0x90, Err: ErrInvalidResponse}
+	}
+	return resp[2 : len(resp)-2], nil
+}
@@ -0,0 +1,126 @@
+package rfid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestParseATR(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado CardType
+	}{
+		{"3B8F8001804F0CA000000306030001000000006A", CardClassic1K},
+		{"3B8F8001804F0CA0000003060300020000000069", CardClassic4K},
+		{"3B8F8001804F0CA0000003060300030000000068", CardUltralight},
+		{"3B8F8001804F0CA000000306030026000000004D", CardMini},
+		{"3B8180018080", CardDESFire},
+		{"3B858001757781028005", CardDESFire},
+		{"3B888001000000000000000009", CardISO14443_4},
+		{"3B8F8001804F0CA000000306031100000000007A", CardUnknown}, // FeliCa/Topaz etc.
+		{"3B00", CardUnknown},
+		{"", CardUnknown},
+	}
+
+	for _, tt := range testes {
+		atr, _ := hex.DecodeString(tt.entrada)
+		if resultado := ParseATR(atr); resultado != tt.esperado {
+			t.Errorf("ParseATR(%s) = %s, esperado %s", tt.entrada, resultado, tt.esperado)
+		}
+	}
+}
+
+func TestCardTypeFromSAK(t *testing.T) {
+	testes := []struct {
+		atqa     uint16
+		sak      byte
+		esperado CardType
+	}{
+		{0x0004, 0x08, CardClassic1K},
+		{0x0044, 0x08, CardClassic1K}, // UID de 7 bytes
+		{0x0002, 0x18, CardClassic4K},
+		{0x0004, 0x09, CardMini},
+		{0x0044, 0x00, CardUltralight},
+		{0x0344, 0x20, CardDESFire},
+		{0x0004, 0x20, CardISO14443_4},
+		{0x0004, 0x10, CardUnknown},
+	}
+
+	for _, tt := range testes {
+		if resultado := CardTypeFromSAK(tt.atqa, tt.sak); resultado != tt.esperado {
+			t.Errorf("CardTypeFromSAK(%04X, %02X) = %s, esperado %s", tt.atqa, tt.sak, resultado, tt.esperado)
+		}
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	rdr, card := newTestReader(t)
+	info, err := rdr.Identify()
+	if err != nil {
+		t.Fatalf("Identify erro: %v", err)
+	}
+	if info.Type != CardClassic1K || info.UID != testUID || info.ATQA != "0004" || info.SAK != "08" {
+		t.Errorf("descritor inesperado: %+v", info)
+	}
+	if err := info.Supported(); err != nil {
+		t.Errorf("Classic 1K deveria ser suportado: %v", err)
+	}
+
+	// Leitura continua funcionando após a re-seleção
+	if _, err := rdr.ReadRange(4, 1, KeyTypeA, DefaultKey); err != nil {
+		t.Errorf("leitura após Identify: %v", err)
+	}
+
+	atr, _ := hex.DecodeString("3B8F8001804F0CA0000003060300030000000068")
+	card.SetIdentity(atr, 0x0044, 0x00)
+	info, err = rdr.Identify()
+	if err != nil {
+		t.Fatalf("Identify erro: %v", err)
+	}
+	if info.Type != CardUltralight {
+		t.Errorf("Type = %s, esperado %s", info.Type, CardUltralight)
+	}
+	if err := info.Supported(); !errors.Is(err, ErrUnsupportedCard) {
+		t.Errorf("Ultralight deveria ser recusado, obtido %v", err)
+	}
+}
+
+func TestSupportedUIDLength(t *testing.T) {
+	info := &CardInfo{Type: CardClassic1K, Name: "MIFARE Classic 1K", UID: "04A1B2C3D4E5F6"}
+	if err := info.Supported(); !errors.Is(err, ErrUnsupportedCard) {
+		t.Errorf("UID de 7 bytes deveria ser recusado, obtido %v", err)
+	}
+	// Leitor sem ATR/SAK: mantém o comportamento antigo
+	info = &CardInfo{Type: CardUnknown, UID: testUID}
+	if err := info.Supported(); err != nil {
+		t.Errorf("tipo desconhecido com UID de 4 bytes deveria passar: %v", err)
+	}
+}
+
+func TestDetectMagic(t *testing.T) {
+	rdr, card := newTestReader(t)
+	if magic, err := rdr.DetectMagic(); err != nil || magic != MagicNone {
+		t.Errorf("cartão original: DetectMagic = %q, %v", magic, err)
+	}
+
+	card.SetGen1a(true)
+	if magic, err := rdr.DetectMagic(); err != nil || magic != MagicGen1a {
+		t.Errorf("Gen1a: DetectMagic = %q, %v", magic, err)
+	}
+	if _, err := rdr.ReadRange(4, 1, KeyTypeA, DefaultKey); err != nil {
+		t.Errorf("cartão deveria estar re-selecionado após a detecção: %v", err)
+	}
+	card.SetGen1a(false)
+
+	block0 := card.Block(0)
+	copy(block0[8:], []byte{0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69})
+	card.SetBlock(0, block0)
+	if magic, err := rdr.DetectMagic(); err != nil || magic != MagicGen2Probable {
+		t.Errorf("Gen2: DetectMagic = %q, %v", magic, err)
+	}
+	if got := card.Block(0); !bytes.Equal(got, block0) {
+		t.Errorf("bloco 0 alterado pela detecção: % X", got)
+	}
+}
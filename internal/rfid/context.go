@@ -0,0 +1,159 @@
+package rfid
+
+// Operações com context.Context.
+//
+//   ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//   defer cancel()
+//   err := rdr.WriteTagCFSContext(ctx, uid, blocks, false)
+//   errors.Is(err, context.DeadlineExceeded)
+//
+// Cada APDU confere o contexto antes de ser enviado. Um Transmit em voo é
+// interrompido com o Cancel do transporte (SCardCancel no PC/SC); se o
+// driver não soltar o APDU em cancelGrace a operação retorna assim mesmo e
+// a conexão é descartada: o APDU órfão continua em voo e o Reader não
+// pode mandar outro pela mesma conexão.
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// cancelGrace quanto esperar o Transmit em voo terminar após o cancelamento.
+const cancelGrace = 500 * time.Millisecond
+
+// canceler Transport capaz de interromper um Transmit bloqueado.
+type canceler interface {
+	Cancel() error
+}
+
+// abandoner Transport que descarta a conexão com um APDU órfão em voo
+// (empréstimo da Session: a conexão é reiniciada antes do próximo).
+type abandoner interface {
+	Abandon() error
+}
+
+// WithContext devolve uma cópia do Reader, sobre a mesma conexão, cujas
+// operações respeitam ctx. Serve para qualquer método sem variante *Context.
+func (r *Reader) WithContext(ctx context.Context) *Reader {
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
+}
+
+// OpenReaderContext é OpenReader limitado por ctx. Se ctx terminar antes,
+// o leitor aberto depois disso é fechado em segundo plano.
+func OpenReaderContext(ctx context.Context, pattern string) (*Reader, error) {
+	type result struct {
+		r   *Reader
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		r, err := OpenReader(pattern)
+		ch <- result{r, err}
+	}()
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return res.r.WithContext(ctx), nil
+	case <-ctx.Done():
+		go func() {
+			if res := <-ch; res.r != nil {
+				res.r.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// OpenContext é Open limitado por ctx.
+func OpenContext(ctx context.Context) (*Reader, error) {
+	return OpenReaderContext(ctx, "")
+}
+
+// UIDContext é UID limitado por ctx.
+func (r *Reader) UIDContext(ctx context.Context) (string, error) {
+	return r.WithContext(ctx).UID()
+}
+
+// TryReadBlockContext é TryReadBlock limitado por ctx.
+func (r *Reader) TryReadBlockContext(ctx context.Context, block byte, keyType byte, keyHex string) (string, error) {
+	return r.WithContext(ctx).TryReadBlock(block, keyType, keyHex)
+}
+
+// WriteBlockDirectlyContext é WriteBlockDirectly limitado por ctx.
+func (r *Reader) WriteBlockDirectlyContext(ctx context.Context, block byte, keyHex, dataHex string, uid ...string) error {
+	return r.WithContext(ctx).WriteBlockDirectly(block, keyHex, dataHex, uid...)
+}
+
+// WriteTagCFSContext é WriteTagCFS limitado por ctx.
+func (r *Reader) WriteTagCFSContext(ctx context.Context, uid string, blocksToWrite []string, encrypted bool) error {
+	return r.WithContext(ctx).WriteTagCFS(uid, blocksToWrite, encrypted)
+}
+
+// ReadRangeContext é ReadRange limitado por ctx.
+func (r *Reader) ReadRangeContext(ctx context.Context, start byte, count int, keyType byte, keyHex string) ([]string, error) {
+	return r.WithContext(ctx).ReadRange(start, count, keyType, keyHex)
+}
+
+// ReadRangeAlternativeContext é ReadRangeAlternative limitado por ctx.
+func (r *Reader) ReadRangeAlternativeContext(ctx context.Context, start byte, count int, keyType byte, keyHex string) ([]string, error) {
+	return r.WithContext(ctx).ReadRangeAlternative(start, count, keyType, keyHex)
+}
+
+// WriteRangeContext é WriteRange limitado por ctx.
+func (r *Reader) WriteRangeContext(ctx context.Context, start byte, blocks []string, keyType byte, keyHex string) error {
+	return r.WithContext(ctx).WriteRange(start, blocks, keyType, keyHex)
+}
+
+// transmitContext envia cmd sem passar do prazo de r.ctx. No cancelamento
+// pede ao transporte para soltar o APDU e espera até cancelGrace.
+func (r *Reader) transmitContext(cmd []byte) ([]byte, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		resp []byte
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		resp, err := r.t.Transmit(cmd)
+		ch <- result{resp, err}
+	}()
+
+	select {
+	case res := <-ch:
+		return res.resp, res.err
+	case <-r.ctx.Done():
+	}
+
+	if c, ok := r.t.(canceler); ok {
+		if err := c.Cancel(); err != nil {
+			log().Warn("cancelamento do APDU falhou", "err", err)
+		}
+	}
+	select {
+	case <-ch:
+	case <-time.After(cancelGrace):
+		log().Warn("APDU em voo não terminou após o cancelamento; conexão descartada")
+		r.abandon()
+	}
+	return nil, fmt.Errorf("APDU %02X interrompido: %w", cmd[1], r.ctx.Err())
+}
+
+// abandon descarta a conexão do APDU órfão: fora da Session ela é fechada.
+func (r *Reader) abandon() {
+	var err error
+	if a, ok := r.t.(abandoner); ok {
+		err = a.Abandon()
+	} else {
+		err = r.t.Close()
+	}
+	if err != nil {
+		log().Debug("descarte da conexão falhou", "err", err)
+	}
+}
@@ -0,0 +1,142 @@
+package rfid
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stuckTransport trava o Transmit do comando ins até Cancel (ou para sempre
+// se cancelable for false), como um leitor que parou de responder.
+type stuckTransport struct {
+	*EmulatedCard
+	ins        byte
+	cancelable bool
+	release    chan struct{}
+	canceled   atomic.Bool
+}
+
+func newStuckTransport(t *testing.T, ins byte, cancelable bool) *stuckTransport {
+	t.Helper()
+	card, err := NewEmulatedCard(testUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &stuckTransport{EmulatedCard: card, ins: ins, cancelable: cancelable, release: make(chan struct{})}
+	t.Cleanup(func() { close(st.release) })
+	return st
+}
+
+func (s *stuckTransport) Transmit(cmd []byte) ([]byte, error) {
+	if len(cmd) > 1 && cmd[1] == s.ins {
+		<-s.release
+		return nil, errors.New("transmit cancelado")
+	}
+	return s.EmulatedCard.Transmit(cmd)
+}
+
+// cancelableStuck expõe Cancel, que solta o Transmit travado.
+type cancelableStuck struct{ *stuckTransport }
+
+func (c cancelableStuck) Cancel() error {
+	if c.canceled.CompareAndSwap(false, true) {
+		c.release <- struct{}{}
+	}
+	return nil
+}
+
+func TestContextAlreadyCanceled(t *testing.T) {
+	rdr, _ := newTestReader(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := rdr.UIDContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("esperado context.Canceled, obtido %v", err)
+	}
+	// O Reader original continua sem prazo
+	if _, err := rdr.UID(); err != nil {
+		t.Errorf("UID sem contexto: %v", err)
+	}
+}
+
+func TestContextCancelStuckWrite(t *testing.T) {
+	st := newStuckTransport(t, 0xD6, true)
+	rdr := NewReader(cancelableStuck{st})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := rdr.WriteTagCFSContext(ctx, testUID, encryptedTestBlocks(t), false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("esperado context.Canceled, obtido %v", err)
+	}
+	if !st.canceled.Load() {
+		t.Error("Cancel do transporte não foi chamado")
+	}
+	if d := time.Since(start); d > cancelGrace {
+		t.Errorf("cancelamento levou %v", d)
+	}
+}
+
+func TestContextDeadlineWithoutCancel(t *testing.T) {
+	// Transporte sem Cancel: a operação volta após cancelGrace mesmo travada
+	rdr := NewReader(newStuckTransport(t, 0xB0, false))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := rdr.ReadRangeContext(ctx, 4, 3, KeyTypeA, DefaultKey)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("esperado context.DeadlineExceeded, obtido %v", err)
+	}
+	if d := time.Since(start); d > 2*cancelGrace {
+		t.Errorf("prazo estourado levou %v", d)
+	}
+}
+
+func TestContextAbandonsStuckLease(t *testing.T) {
+	// APDU preso além de cancelGrace: o empréstimo é descartado e o
+	// próximo Open recebe uma conexão nova
+	dev := newTestDevice(t)
+	dev.present.Store(true)
+	stuck := newStuckTransport(t, 0xB0, false)
+	var connects atomic.Int32
+	dev.connect = func() (Transport, error) {
+		if connects.Add(1) == 1 {
+			return stuck, nil
+		}
+		return dev.card, nil
+	}
+	s := NewSession(dev)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	rdr, err := s.Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rdr.ReadRange(4, 3, KeyTypeA, DefaultKey); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("esperado context.DeadlineExceeded, obtido %v", err)
+	}
+	if _, err := rdr.WithContext(context.Background()).UID(); !errors.Is(err, ErrNoReader) {
+		t.Errorf("conexão descartada ainda transmite: %v", err)
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	next, err := s.Open(ctx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer next.Close()
+	if _, err := next.UID(); err != nil {
+		t.Errorf("novo empréstimo: %v", err)
+	}
+	if n := connects.Load(); n != 2 {
+		t.Errorf("%d conexões, esperado 2", n)
+	}
+}
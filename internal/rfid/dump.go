@@ -0,0 +1,142 @@
+package rfid
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/dump"
+)
+
+// DefaultKey key de fábrica de MIFARE Classic.
+const DefaultKey = "FFFFFFFFFFFF"
+
+// DumpKeys monta o dicionário para Dump: FFFFFFFFFFFF, key Creality
+// derivada do UID e as keys extras informadas (12 hex, sem duplicatas).
+func DumpKeys(uid string, extra ...string) ([]string, error) {
+	keys := []string{DefaultKey}
+	if derived, err := creality.DeriveS1KeyFromUID(uid); err == nil {
+		keys = append(keys, derived)
+	}
+	for _, k := range extra {
+		k = strings.ToUpper(strings.TrimSpace(k))
+		if k == "" {
+			continue
+		}
+		if b, err := hex.DecodeString(k); err != nil || len(b) != 6 {
+			return nil, fmt.Errorf("key %q inválida: deve ter 12 hex", k)
+		}
+		keys = append(keys, k)
+	}
+
+	out := keys[:0]
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+// Dump lê os 16 setores de um MIFARE Classic 1K. Em cada setor tenta todas
+// as keys do dicionário como KeyA e KeyB, registra as que abriram o setor e
+// lê cada bloco com a primeira delas que tiver permissão de leitura.
+// Setores sem key conhecida ficam marcados como não lidos.
+func (r *Reader) Dump(keys []string) (*dump.Dump, error) {
+	uid, err := r.UID()
+	if err != nil {
+		return nil, err
+	}
+	d := &dump.Dump{UID: strings.ToUpper(uid)}
+
+	for s := 0; s < dump.Sectors; s++ {
+		trailer := byte(dump.TrailerBlock(s))
+		for _, keyType := range []byte{KeyTypeA, KeyTypeB} {
+			key, err := r.findKey(trailer, keyType, keys)
+			if err != nil {
+				return nil, err
+			}
+			if keyType == KeyTypeA {
+				d.Keys[s].KeyA = key
+			} else {
+				d.Keys[s].KeyB = key
+			}
+		}
+		if err := r.dumpSector(d, s); err != nil {
+			return nil, err
+		}
+	}
+
+	d.FillTrailerKeys()
+	return d, nil
+}
+
+// findKey devolve a primeira key do dicionário que autentica o bloco
+// ("" se nenhuma). Só retorna erro se a tag sair do leitor.
+func (r *Reader) findKey(block, keyType byte, keys []string) (string, error) {
+	for _, key := range keys {
+		err := r.auth(block, keyType, key)
+		if err == nil {
+			return key, nil
+		}
+		if errors.Is(err, ErrCardRemoved) {
+			return "", err
+		}
+		// Auth falha deixa a tag em HALT — re-selecionar antes da próxima key
+		r.UID()
+	}
+	return "", nil
+}
+
+// dumpSector lê os blocos do setor com KeyA e, para o que faltar, com KeyB.
+func (r *Reader) dumpSector(d *dump.Dump, sector int) error {
+	first := sector * dump.BlocksPerSector
+	last := dump.TrailerBlock(sector)
+	keys := []struct {
+		keyType byte
+		key     string
+	}{
+		{KeyTypeA, d.Keys[sector].KeyA},
+		{KeyTypeB, d.Keys[sector].KeyB},
+	}
+
+	for _, k := range keys {
+		if k.key == "" {
+			continue
+		}
+		authenticated := false
+		for b := first; b <= last; b++ {
+			if d.Read[b] {
+				continue
+			}
+			if !authenticated {
+				if err := r.auth(byte(b), k.keyType, k.key); err != nil {
+					if errors.Is(err, ErrCardRemoved) {
+						return err
+					}
+					r.UID()
+					break
+				}
+				authenticated = true
+			}
+			data, err := r.readBlock(byte(b))
+			if err != nil {
+				if errors.Is(err, ErrCardRemoved) {
+					return err
+				}
+				// Leitura negada pelos access bits — re-autenticar no próximo bloco
+				r.UID()
+				authenticated = false
+				continue
+			}
+			raw, _ := hex.DecodeString(data)
+			copy(d.Blocks[b][:], raw)
+			d.Read[b] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package rfid
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+func TestDumpKeys(t *testing.T) {
+	derived, _ := creality.DeriveS1KeyFromUID(testUID)
+	keys, err := DumpKeys(testUID, "a0a1a2a3a4a5", "FFFFFFFFFFFF", " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperado := []string{"FFFFFFFFFFFF", derived, "A0A1A2A3A4A5"}
+	if len(keys) != len(esperado) {
+		t.Fatalf("DumpKeys = %v, esperado %v", keys, esperado)
+	}
+	for i := range keys {
+		if keys[i] != esperado[i] {
+			t.Errorf("DumpKeys[%d] = %s, esperado %s", i, keys[i], esperado[i])
+		}
+	}
+	if _, err := DumpKeys(testUID, "XYZ"); err == nil {
+		t.Error("key inválida deveria retornar erro")
+	}
+}
+
+func TestReaderDump(t *testing.T) {
+	rdr, card := newTestReader(t)
+	if err := rdr.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatal(err)
+	}
+	// Setor 5 com keys fora do dicionário
+	locked, _ := hex.DecodeString("A0A1A2A3A4A5FF078069B0B1B2B3B4B5")
+	card.SetBlock(23, locked)
+
+	keys, _ := DumpKeys(testUID)
+	d, err := rdr.Dump(keys)
+	if err != nil {
+		t.Fatalf("Dump erro: %v", err)
+	}
+
+	derived, _ := creality.DeriveS1KeyFromUID(testUID)
+	if d.Keys[0].KeyA != "FFFFFFFFFFFF" || d.Keys[1].KeyA != derived || d.Keys[1].KeyB != derived {
+		t.Errorf("keys inesperadas: setor 0 %+v, setor 1 %+v", d.Keys[0], d.Keys[1])
+	}
+	if d.Keys[5].KeyA != "" || d.Read[20] || d.Complete() {
+		t.Errorf("setor 5 não deveria abrir: %+v read=%v", d.Keys[5], d.Read[20])
+	}
+	for b := 4; b <= 6; b++ {
+		if !d.Read[b] || d.BlockHex(b) != strings.ToUpper(hex.EncodeToString(card.Block(b))) {
+			t.Errorf("bloco %d = %s, esperado %X", b, d.BlockHex(b), card.Block(b))
+		}
+	}
+	// Trailer do dump traz as keys descobertas no lugar dos zeros lidos
+	if got, want := d.BlockHex(7), derived+"FF078069"+derived; got != want {
+		t.Errorf("trailer = %s, esperado %s", got, want)
+	}
+	if d.Blocks[0][0] != 0xA1 {
+		t.Errorf("bloco 0 não lido: %X", d.Blocks[0])
+	}
+}
@@ -0,0 +1,382 @@
+package rfid
+
+// Emulador de MIFARE Classic 1K em memória para testes sem hardware.
+//
+//   card, _ := NewEmulatedCard("A1B2C3D4")
+//   rdr := NewReader(card)
+//   rdr.WriteTagCFS("A1B2C3D4", blocks, false)
+//
+// Entende os pseudo-APDUs do ACR122U (FF CA, FF 82, FF 86, FF B0, FF D6)
+// e os comandos PN532 diretos (FF 00 00 00) usados na identificação,
+// respeita as keys e os access bits gravados no trailer de cada setor e
+// mantém o estado de autenticação como um cartão real.
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/robertocorreajr/cfs_spool/internal/access"
+)
+
+const (
+	emuSectors         = 16
+	emuBlocksPerSector = 4
+	emuBlocks          = emuSectors * emuBlocksPerSector
+)
+
+// Status words devolvidos pelo emulador (mesmos do ACR122U).
+var (
+	swOK            = []byte{0x90, 0x00}
+	swFailed        = []byte{0x63, 0x00}
+	swWrongLength   = []byte{0x67, 0x00}
+	swNotSupported  = []byte{0x6A, 0x81}
+	swBlockNotFound = []byte{0x6A, 0x82}
+	swClassNotSupp  = []byte{0x6E, 0x00}
+	defaultKeyBytes = []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+)
+
+// EmulatedCard é um MIFARE Classic 1K em memória que implementa Transport.
+type EmulatedCard struct {
+	mu         sync.Mutex
+	uid        []byte
+	blocks     [emuBlocks][16]byte
+	keySlots   [2][]byte
+	authSector int // -1 quando não autenticado
+	authKey    byte
+
+	atr        []byte
+	atqa       uint16
+	sak        byte
+	gen1a      bool
+	halted     bool // HALT recebido; só InListPassiveTarget re-seleciona
+	bitFraming byte // CIU_BitFraming (63 3D)
+}
+
+// emuATR ATR PC/SC de um MIFARE Classic 1K no ACR122U.
+var emuATR = []byte{
+	0x3B, 0x8F, 0x80, 0x01, 0x80, 0x4F, 0x0C, 0xA0, 0x00, 0x00,
+	0x03, 0x06, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x6A,
+}
+
+// NewEmulatedCard cria um cartão virgem (todas as keys FFFFFFFFFFFF,
+// access bits de transporte FF078069) com o UID de 4 bytes informado.
+func NewEmulatedCard(uidHex string) (*EmulatedCard, error) {
+	uid, err := hex.DecodeString(uidHex)
+	if err != nil || len(uid) != 4 {
+		return nil, errors.New("UID deve ter 4 bytes (8 hex)")
+	}
+	c := &EmulatedCard{uid: uid, authSector: -1, atr: emuATR, atqa: 0x0004, sak: 0x08}
+
+	// Bloco 0: UID + BCC + SAK (08) + ATQA (0004) + dados do fabricante
+	copy(c.blocks[0][:4], uid)
+	c.blocks[0][4] = uid[0] ^ uid[1] ^ uid[2] ^ uid[3]
+	c.blocks[0][5] = 0x08
+	c.blocks[0][6] = 0x04
+	c.blocks[0][7] = 0x00
+
+	trailer := access.Trailer(defaultKeyBytes, access.Transport, defaultKeyBytes)
+	for s := 0; s < emuSectors; s++ {
+		copy(c.blocks[trailerOf(s)][:], trailer)
+	}
+	return c, nil
+}
+
+// Block devolve uma cópia do conteúdo bruto do bloco, ignorando keys
+// e access bits (útil para asserções nos testes).
+func (c *EmulatedCard) Block(block int) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]byte, 16)
+	copy(out, c.blocks[block][:])
+	return out
+}
+
+// SetBlock grava o bloco diretamente na memória, sem autenticação
+// (útil para preparar cenários nos testes).
+func (c *EmulatedCard) SetBlock(block int, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	copy(c.blocks[block][:], data)
+}
+
+// SetIdentity troca o ATR e o ATQA/SAK informados pelo cartão (para
+// simular outros tipos de cartão nos testes).
+func (c *EmulatedCard) SetIdentity(atr []byte, atqa uint16, sak byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.atr = clone(atr)
+	c.atqa = atqa
+	c.sak = sak
+}
+
+// SetGen1a faz o cartão responder ao backdoor de clones Gen1a.
+func (c *EmulatedCard) SetGen1a(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen1a = on
+}
+
+// ATR devolve o ATR do cartão emulado.
+func (c *EmulatedCard) ATR() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return clone(c.atr), nil
+}
+
+// Close não tem efeito no emulador.
+func (c *EmulatedCard) Close() error {
+	return nil
+}
+
+// Transmit processa um pseudo-APDU do ACR122U e devolve resposta + SW1SW2.
+func (c *EmulatedCard) Transmit(cmd []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(cmd) < 5 {
+		return clone(swWrongLength), nil
+	}
+	if cmd[0] != 0xFF {
+		return clone(swClassNotSupp), nil
+	}
+	if cmd[1] == 0x00 && cmd[2] == 0x00 && cmd[3] == 0x00 {
+		return c.direct(cmd), nil
+	}
+	if c.halted && cmd[1] != 0x82 {
+		return clone(swFailed), nil
+	}
+	switch cmd[1] {
+	case 0xCA:
+		return c.getData(cmd), nil
+	case 0x82:
+		return c.loadKey(cmd), nil
+	case 0x86:
+		return c.authenticate(cmd), nil
+	case 0xB0:
+		return c.readBinary(cmd), nil
+	case 0xD6:
+		return c.updateBinary(cmd), nil
+	}
+	return clone(swNotSupported), nil
+}
+
+// direct: FF 00 00 00 <Lc> D4 <comando PN532> → D5 <comando+1> <dados> 90 00.
+func (c *EmulatedCard) direct(cmd []byte) []byte {
+	if len(cmd) < 7 || int(cmd[4]) != len(cmd)-5 || cmd[5] != 0xD4 {
+		return clone(swWrongLength)
+	}
+	code, args := cmd[6], cmd[7:]
+	var out []byte
+	switch code {
+	case 0x4A: // InListPassiveTarget: re-seleciona (também acorda após HALT)
+		c.halted = false
+		c.authSector = -1
+		out = []byte{0x01, 0x01, byte(c.atqa >> 8), byte(c.atqa), c.sak, byte(len(c.uid))}
+		out = append(out, c.uid...)
+	case 0x08: // WriteRegister: pares <endereço 2 bytes> <valor>
+		for i := 0; i+2 < len(args); i += 3 {
+			if args[i] == 0x63 && args[i+1] == 0x3D {
+				c.bitFraming = args[i+2]
+			}
+		}
+	case 0x32: // RFConfiguration: desligar o campo desseleciona o cartão
+		if len(args) == 2 && args[0] == 0x01 && args[1] == 0x00 {
+			c.halted = true
+			c.authSector = -1
+		}
+	case 0x42: // InCommunicateThru: HALT e backdoor Gen1a
+		out = c.communicateThru(args)
+	default:
+		return clone(swNotSupported)
+	}
+	return append(append([]byte{0xD5, code + 1}, out...), swOK...)
+}
+
+// communicateThru responde a quadros crus; 01 = timeout (sem resposta).
+func (c *EmulatedCard) communicateThru(frame []byte) []byte {
+	switch {
+	case len(frame) >= 2 && frame[0] == 0x50 && frame[1] == 0x00:
+		c.halted = true
+		c.authSector = -1
+	case len(frame) == 1 && frame[0] == 0x40 && c.bitFraming == 0x07 && c.gen1a && c.halted:
+		return []byte{0x00, 0x0A}
+	}
+	return []byte{0x01}
+}
+
+// getData: FF CA 00 00 00 → UID. ATS (P1=01) não existe em MIFARE Classic.
+func (c *EmulatedCard) getData(cmd []byte) []byte {
+	if cmd[2] != 0x00 {
+		return clone(swNotSupported)
+	}
+	return append(clone(c.uid), swOK...)
+}
+
+// loadKey: FF 82 00 <slot> 06 <key 6 bytes>.
+func (c *EmulatedCard) loadKey(cmd []byte) []byte {
+	if len(cmd) != 11 || cmd[4] != 6 {
+		return clone(swWrongLength)
+	}
+	if cmd[2] != 0x00 || cmd[3] > 1 {
+		return clone(swFailed)
+	}
+	c.keySlots[cmd[3]] = clone(cmd[5:11])
+	return clone(swOK)
+}
+
+// authenticate: FF 86 00 00 05 01 00 <bloco> <60|61> <slot>.
+func (c *EmulatedCard) authenticate(cmd []byte) []byte {
+	c.authSector = -1
+	if len(cmd) != 10 || cmd[4] != 5 {
+		return clone(swWrongLength)
+	}
+	if cmd[5] != 0x01 || cmd[6] != 0x00 {
+		return clone(swFailed)
+	}
+	block, keyType, slot := int(cmd[7]), cmd[8], cmd[9]
+	if block >= emuBlocks {
+		return clone(swBlockNotFound)
+	}
+	if slot > 1 || c.keySlots[slot] == nil {
+		return clone(swFailed)
+	}
+
+	t := c.blocks[trailerOf(sectorOf(block))]
+	var key []byte
+	switch keyType {
+	case KeyTypeA:
+		key = t[0:6]
+	case KeyTypeB:
+		key = t[10:16]
+	default:
+		return clone(swFailed)
+	}
+	if string(key) != string(c.keySlots[slot]) {
+		return clone(swFailed)
+	}
+	c.authSector = sectorOf(block)
+	c.authKey = keyType
+	return clone(swOK)
+}
+
+// readBinary: FF B0 00 <bloco> 10.
+func (c *EmulatedCard) readBinary(cmd []byte) []byte {
+	if len(cmd) != 5 || cmd[4] != 16 {
+		return clone(swWrongLength)
+	}
+	block := int(cmd[3])
+	if block >= emuBlocks {
+		return clone(swBlockNotFound)
+	}
+	if !c.authenticatedFor(block) {
+		return clone(swFailed)
+	}
+
+	perm := c.keyPerm()
+	if block != trailerOf(sectorOf(block)) {
+		bits, ok := c.accessBits(sectorOf(block))
+		if !ok || bits.Blocks[block%emuBlocksPerSector].Data().Read&perm == 0 {
+			return clone(swFailed)
+		}
+		return append(clone(c.blocks[block][:]), swOK...)
+	}
+
+	// Trailer: KeyA nunca é legível; access bits e KeyB conforme C1C2C3
+	bits, ok := c.accessBits(sectorOf(block))
+	if !ok {
+		return clone(swFailed)
+	}
+	acc := bits.Blocks[3].Trailer()
+	if acc.ReadAccess&perm == 0 {
+		return clone(swFailed)
+	}
+	out := make([]byte, 16)
+	copy(out[6:10], c.blocks[block][6:10])
+	if acc.ReadKeyB&perm != 0 {
+		copy(out[10:16], c.blocks[block][10:16])
+	}
+	return append(out, swOK...)
+}
+
+// updateBinary: FF D6 00 <bloco> 10 <dados 16 bytes>.
+func (c *EmulatedCard) updateBinary(cmd []byte) []byte {
+	if len(cmd) != 21 || cmd[4] != 16 {
+		return clone(swWrongLength)
+	}
+	block := int(cmd[3])
+	data := cmd[5:21]
+	if block >= emuBlocks {
+		return clone(swBlockNotFound)
+	}
+	if block == 0 || !c.authenticatedFor(block) {
+		return clone(swFailed)
+	}
+
+	bits, ok := c.accessBits(sectorOf(block))
+	if !ok {
+		return clone(swFailed)
+	}
+	perm := c.keyPerm()
+	if block != trailerOf(sectorOf(block)) {
+		if bits.Blocks[block%emuBlocksPerSector].Data().Write&perm == 0 {
+			return clone(swFailed)
+		}
+		copy(c.blocks[block][:], data)
+		return clone(swOK)
+	}
+
+	// Trailer: cada parte só é gravada se a key autenticada tiver permissão
+	acc := bits.Blocks[3].Trailer()
+	if (acc.WriteKeyA|acc.WriteAccess|acc.WriteKeyB)&perm == 0 {
+		return clone(swFailed)
+	}
+	t := &c.blocks[block]
+	if acc.WriteKeyA&perm != 0 {
+		copy(t[0:6], data[0:6])
+	}
+	if acc.WriteAccess&perm != 0 {
+		copy(t[6:10], data[6:10])
+	}
+	if acc.WriteKeyB&perm != 0 {
+		copy(t[10:16], data[10:16])
+	}
+	return clone(swOK)
+}
+
+func (c *EmulatedCard) authenticatedFor(block int) bool {
+	return c.authSector >= 0 && c.authSector == sectorOf(block)
+}
+
+// keyPerm devolve a máscara da key autenticada. Quando a KeyB é legível
+// pelas condições do trailer ela não concede acesso (comportamento NXP).
+func (c *EmulatedCard) keyPerm() access.Perm {
+	if c.authKey == KeyTypeA {
+		return access.PermA
+	}
+	bits, ok := c.accessBits(c.authSector)
+	if !ok || bits.KeyBReadable() {
+		return access.PermNone
+	}
+	return access.PermB
+}
+
+// accessBits decodifica as condições do setor a partir do trailer.
+// ok=false se as cópias invertidas não conferem (setor bloqueado).
+func (c *EmulatedCard) accessBits(sector int) (access.Bits, bool) {
+	t := c.blocks[trailerOf(sector)]
+	bits, err := access.Decode(t[6:10])
+	return bits, err == nil
+}
+
+func sectorOf(block int) int {
+	return block / emuBlocksPerSector
+}
+
+func trailerOf(sector int) int {
+	return sector*emuBlocksPerSector + emuBlocksPerSector - 1
+}
+
+func clone(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,150 @@
+package rfid
+
+// Emulador de NTAG21x em memória, no mesmo espírito de EmulatedCard.
+//
+//   tag, _ := NewEmulatedNTAG("04A1B2C3D4E5F6", NTAG215)
+//   rdr := NewReader(tag)
+//   rdr.WriteNDEF(msg)
+//
+// Entende FF CA, FF B0 (4 páginas), FF D6 (1 página) e InListPassiveTarget.
+// Vem formatado para NDEF com uma mensagem vazia, como as tags vendidas
+// para OpenSpool.
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+)
+
+// EmulatedNTAG é uma NTAG21x em memória que implementa Transport.
+type EmulatedNTAG struct {
+	mu    sync.Mutex
+	model NTAG
+	uid   []byte
+	pages [][4]byte
+	atr   []byte
+}
+
+// emuNTAGATR ATR PC/SC de uma Ultralight/NTAG (NN = 00 03) no ACR122U.
+var emuNTAGATR = []byte{
+	0x3B, 0x8F, 0x80, 0x01, 0x80, 0x4F, 0x0C, 0xA0, 0x00, 0x00,
+	0x03, 0x06, 0x03, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x68,
+}
+
+// NewEmulatedNTAG cria uma tag com o UID de 7 bytes informado, CC do
+// modelo e TLV NDEF vazio na página 4.
+func NewEmulatedNTAG(uidHex string, model NTAG) (*EmulatedNTAG, error) {
+	uid, err := hex.DecodeString(uidHex)
+	if err != nil || len(uid) != 7 {
+		return nil, errors.New("UID deve ter 7 bytes (14 hex)")
+	}
+	t := &EmulatedNTAG{model: model, uid: uid, pages: make([][4]byte, model.Pages), atr: emuNTAGATR}
+
+	// Páginas 0–2: UID0-2 BCC0 | UID3-6 | BCC1 interno lock0 lock1
+	t.pages[0] = [4]byte{uid[0], uid[1], uid[2], 0x88 ^ uid[0] ^ uid[1] ^ uid[2]}
+	copy(t.pages[1][:], uid[3:7])
+	t.pages[2][0] = uid[3] ^ uid[4] ^ uid[5] ^ uid[6]
+	t.pages[2][1] = 0x48
+	t.pages[ntagCCPage] = [4]byte{ntagCCMagic, 0x10, model.Size, 0x00}
+	t.pages[ntagDataPage] = [4]byte{0x03, 0x00, 0xFE, 0x00}
+	return t, nil
+}
+
+// Page devolve uma cópia da página (útil para asserções nos testes).
+func (t *EmulatedNTAG) Page(page int) []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return clone(t.pages[page][:])
+}
+
+// SetPage grava a página diretamente, inclusive as protegidas (útil para
+// preparar cenários nos testes).
+func (t *EmulatedNTAG) SetPage(page int, data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	copy(t.pages[page][:], data)
+}
+
+// ATR devolve o ATR da tag emulada.
+func (t *EmulatedNTAG) ATR() ([]byte, error) {
+	return clone(t.atr), nil
+}
+
+// Close não tem efeito no emulador.
+func (t *EmulatedNTAG) Close() error {
+	return nil
+}
+
+// Transmit processa um pseudo-APDU do ACR122U e devolve resposta + SW1SW2.
+func (t *EmulatedNTAG) Transmit(cmd []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(cmd) < 5 {
+		return clone(swWrongLength), nil
+	}
+	if cmd[0] != 0xFF {
+		return clone(swClassNotSupp), nil
+	}
+	if cmd[1] == 0x00 && cmd[2] == 0x00 && cmd[3] == 0x00 {
+		return t.direct(cmd), nil
+	}
+	switch cmd[1] {
+	case 0xCA:
+		if cmd[2] != 0x00 {
+			return clone(swNotSupported), nil
+		}
+		return append(clone(t.uid), swOK...), nil
+	case 0xB0:
+		return t.read(cmd), nil
+	case 0xD6:
+		return t.write(cmd), nil
+	}
+	return clone(swNotSupported), nil
+}
+
+// direct: só InListPassiveTarget (ATQA 0044, SAK 00).
+func (t *EmulatedNTAG) direct(cmd []byte) []byte {
+	if len(cmd) < 7 || int(cmd[4]) != len(cmd)-5 || cmd[5] != 0xD4 {
+		return clone(swWrongLength)
+	}
+	if cmd[6] != 0x4A {
+		return clone(swNotSupported)
+	}
+	out := []byte{0xD5, 0x4B, 0x01, 0x01, 0x00, 0x44, 0x00, byte(len(t.uid))}
+	out = append(out, t.uid...)
+	return append(out, swOK...)
+}
+
+// read: FF B0 00 <página> 10; como a NTAG, volta à página 0 no fim.
+func (t *EmulatedNTAG) read(cmd []byte) []byte {
+	if len(cmd) != 5 || cmd[4] != 16 {
+		return clone(swWrongLength)
+	}
+	page := int(cmd[3])
+	if page >= len(t.pages) {
+		return clone(swBlockNotFound)
+	}
+	var out []byte
+	for i := 0; i < 4; i++ {
+		out = append(out, t.pages[(page+i)%len(t.pages)][:]...)
+	}
+	return append(out, swOK...)
+}
+
+// write: FF D6 00 <página> 04 <dados>. Páginas 0–3 e as de configuração
+// no fim da tag são recusadas.
+func (t *EmulatedNTAG) write(cmd []byte) []byte {
+	if len(cmd) != 9 || cmd[4] != 4 {
+		return clone(swWrongLength)
+	}
+	page := int(cmd[3])
+	if page >= len(t.pages) {
+		return clone(swBlockNotFound)
+	}
+	if page < ntagDataPage || page >= ntagDataPage+int(t.model.Size)*2 {
+		return clone(swFailed)
+	}
+	copy(t.pages[page][:], cmd[5:9])
+	return clone(swOK)
+}
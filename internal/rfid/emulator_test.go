@@ -0,0 +1,144 @@
+package rfid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func apdu(t *testing.T, c *EmulatedCard, hexCmd string) []byte {
+	t.Helper()
+	cmd, err := hex.DecodeString(hexCmd)
+	if err != nil {
+		t.Fatalf("APDU inválido %q: %v", hexCmd, err)
+	}
+	resp, err := c.Transmit(cmd)
+	if err != nil {
+		t.Fatalf("Transmit(%s) erro: %v", hexCmd, err)
+	}
+	return resp
+}
+
+func sw(resp []byte) string {
+	if len(resp) < 2 {
+		return ""
+	}
+	return hex.EncodeToString(resp[len(resp)-2:])
+}
+
+func TestEmulatedCardGetData(t *testing.T) {
+	c, err := NewEmulatedCard("A1B2C3D4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := apdu(t, c, "FFCA000000")
+	if got := hex.EncodeToString(resp); got != "a1b2c3d49000" {
+		t.Errorf("GET DATA = %s, esperado a1b2c3d49000", got)
+	}
+	if got := sw(apdu(t, c, "FFCA010000")); got != "6a81" {
+		t.Errorf("GET DATA ATS SW = %s, esperado 6a81", got)
+	}
+}
+
+func TestEmulatedCardAuthAndRead(t *testing.T) {
+	c, _ := NewEmulatedCard("A1B2C3D4")
+
+	// Leitura sem autenticação deve falhar
+	if got := sw(apdu(t, c, "FFB0000410")); got != "6300" {
+		t.Errorf("read sem auth SW = %s, esperado 6300", got)
+	}
+
+	apdu(t, c, "FF82000006FFFFFFFFFFFF")
+	if got := sw(apdu(t, c, "FF860000050100046000")); got != "9000" {
+		t.Fatalf("auth KeyA SW = %s, esperado 9000", got)
+	}
+	resp := apdu(t, c, "FFB0000410")
+	if len(resp) != 18 || sw(resp) != "9000" {
+		t.Errorf("read bloco 4 = %x", resp)
+	}
+
+	// Autenticação vale só para o setor 1
+	if got := sw(apdu(t, c, "FFB0000810")); got != "6300" {
+		t.Errorf("read bloco 8 (outro setor) SW = %s, esperado 6300", got)
+	}
+
+	// Key errada derruba o estado de autenticação
+	apdu(t, c, "FF82000006A0A1A2A3A4A5")
+	if got := sw(apdu(t, c, "FF860000050100046000")); got != "6300" {
+		t.Errorf("auth com key errada SW = %s, esperado 6300", got)
+	}
+	if got := sw(apdu(t, c, "FFB0000410")); got != "6300" {
+		t.Errorf("read após auth falha SW = %s, esperado 6300", got)
+	}
+}
+
+func TestEmulatedCardTrailerRead(t *testing.T) {
+	c, _ := NewEmulatedCard("A1B2C3D4")
+	apdu(t, c, "FF82000006FFFFFFFFFFFF")
+	apdu(t, c, "FF860000050100076000")
+	resp := apdu(t, c, "FFB0000710")
+	// KeyA sempre lida como zeros; KeyB legível no modo de transporte
+	want := "000000000000ff078069ffffffffffff9000"
+	if got := hex.EncodeToString(resp); got != want {
+		t.Errorf("read trailer = %s, esperado %s", got, want)
+	}
+}
+
+func TestEmulatedCardKeyBReadableDeniesAccess(t *testing.T) {
+	c, _ := NewEmulatedCard("A1B2C3D4")
+	apdu(t, c, "FF82000006FFFFFFFFFFFF")
+	// Auth com KeyB passa, mas no modo de transporte (KeyB legível) não concede acesso
+	if got := sw(apdu(t, c, "FF860000050100046100")); got != "9000" {
+		t.Fatalf("auth KeyB SW = %s, esperado 9000", got)
+	}
+	if got := sw(apdu(t, c, "FFD60004100102030405060708090A0B0C0D0E0F10")); got != "6300" {
+		t.Errorf("write com KeyB SW = %s, esperado 6300", got)
+	}
+}
+
+func TestEmulatedCardBlockZeroReadOnly(t *testing.T) {
+	c, _ := NewEmulatedCard("A1B2C3D4")
+	apdu(t, c, "FF82000006FFFFFFFFFFFF")
+	apdu(t, c, "FF860000050100006000")
+	if got := sw(apdu(t, c, "FFD60000100102030405060708090A0B0C0D0E0F10")); got != "6300" {
+		t.Errorf("write bloco 0 SW = %s, esperado 6300", got)
+	}
+}
+
+func TestEmulatedCardAccessBitsEnforced(t *testing.T) {
+	c, _ := NewEmulatedCard("A1B2C3D4")
+	// Setor 1 com FF 07 80 → 78 77 88: blocos de dados 100 (escrita só KeyB),
+	// trailer 011; KeyB = B0B1B2B3B4B5
+	trailer, _ := hex.DecodeString("FFFFFFFFFFFF78778869B0B1B2B3B4B5")
+	c.SetBlock(7, trailer)
+
+	apdu(t, c, "FF82000006FFFFFFFFFFFF")
+	apdu(t, c, "FF860000050100046000")
+	if got := sw(apdu(t, c, "FFB0000410")); got != "9000" {
+		t.Errorf("read com KeyA SW = %s, esperado 9000", got)
+	}
+	if got := sw(apdu(t, c, "FFD60004100102030405060708090A0B0C0D0E0F10")); got != "6300" {
+		t.Errorf("write com KeyA SW = %s, esperado 6300", got)
+	}
+
+	apdu(t, c, "FF82000006B0B1B2B3B4B5")
+	apdu(t, c, "FF860000050100046100")
+	if got := sw(apdu(t, c, "FFD60004100102030405060708090A0B0C0D0E0F10")); got != "9000" {
+		t.Errorf("write com KeyB SW = %s, esperado 9000", got)
+	}
+	want, _ := hex.DecodeString("0102030405060708090A0B0C0D0E0F10")
+	if !bytes.Equal(c.Block(4), want) {
+		t.Errorf("bloco 4 = %X, esperado %X", c.Block(4), want)
+	}
+}
+
+func TestEmulatedCardInvalidAccessBitsLockSector(t *testing.T) {
+	c, _ := NewEmulatedCard("A1B2C3D4")
+	trailer, _ := hex.DecodeString("FFFFFFFFFFFF00000069FFFFFFFFFFFF")
+	c.SetBlock(7, trailer)
+	apdu(t, c, "FF82000006FFFFFFFFFFFF")
+	apdu(t, c, "FF860000050100046000")
+	if got := sw(apdu(t, c, "FFB0000410")); got != "6300" {
+		t.Errorf("read com access bits inválidos SW = %s, esperado 6300", got)
+	}
+}
@@ -0,0 +1,103 @@
+package rfid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ebfe/scard"
+)
+
+// Erros sentinela — use errors.Is para distinguir a causa de uma falha.
+var (
+	ErrNoReader        = errors.New("nenhum leitor PC/SC")
+	ErrCardRemoved     = errors.New("tag ausente ou removida do leitor")
+	ErrAuthFailed      = errors.New("autenticação falhou")
+	ErrBlockNotFound   = errors.New("bloco não encontrado")
+	ErrAccessDenied    = errors.New("operação não permitida pelos access bits")
+	ErrWrongLength     = errors.New("tamanho de APDU incorreto")
+	ErrNotSupported    = errors.New("comando não suportado pelo leitor/tag")
+	ErrOperationFailed = errors.New("operação falhou")
+	ErrInvalidResponse = errors.New("resposta APDU inválida")
+	ErrVerifyFailed    = errors.New("releitura não confere com o gravado")
+	ErrUnsupportedCard = errors.New("cartão não suportado")
+	ErrWrongTag        = errors.New("tag diferente da esperada")
+	ErrNotNDEF         = errors.New("tag sem formatação NDEF")
+	ErrTagFull         = errors.New("dados não cabem na tag")
+)
+
+// StatusError falha de APDU com o status word (SW1SW2) devolvido e o bloco envolvido.
+type StatusError struct {
+	Op       string // "uid", "load key", "auth", "read", "write", "pn532"
+	Block    int    // -1 quando o comando não se refere a um bloco
+	SW1, SW2 byte
+	Err      error // sentinela correspondente ao SW
+}
+
+func (e *StatusError) Error() string {
+	msg := e.Op
+	if e.Block >= 0 {
+		msg = fmt.Sprintf("%s bloco %d", e.Op, e.Block)
+	}
+	if e.SW1 == 0 && e.SW2 == 0 {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return fmt.Sprintf("%s: %v (SW %02X%02X)", msg, e.Err, e.SW1, e.SW2)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// SW retorna o status word como uint16 (ex.: 0x6300).
+func (e *StatusError) SW() uint16 {
+	return uint16(e.SW1)<<8 | uint16(e.SW2)
+}
+
+// DecodeSW mapeia um status word para o erro sentinela correspondente.
+// 63 00 é genérico no ACR122U: numa autenticação significa key recusada.
+func DecodeSW(op string, sw1, sw2 byte) error {
+	switch {
+	case sw1 == 0x90 && sw2 == 0x00:
+		return nil
+	case sw1 == 0x63 && op == "auth":
+		return ErrAuthFailed
+	case sw1 == 0x69 && sw2 == 0x82:
+		return ErrAuthFailed // security status not satisfied
+	case sw1 == 0x69 && sw2 == 0x86:
+		return ErrAccessDenied // command not allowed
+	case sw1 == 0x6A && sw2 == 0x82, sw1 == 0x6B:
+		return ErrBlockNotFound // endereço inexistente / P1-P2 fora da faixa
+	case sw1 == 0x6A && sw2 == 0x81, sw1 == 0x6D, sw1 == 0x6E:
+		return ErrNotSupported
+	case sw1 == 0x67, sw1 == 0x6C:
+		return ErrWrongLength
+	}
+	return ErrOperationFailed
+}
+
+// checkSW valida o SW no fim da resposta; nil se 90 00.
+func checkSW(resp []byte, op string, block int) error {
+	if len(resp) < 2 {
+		return &StatusError{Op: op, Block: block, Err: ErrInvalidResponse}
+	}
+	sw1, sw2 := resp[len(resp)-2], resp[len(resp)-1]
+	if err := DecodeSW(op, sw1, sw2); err != nil {
+		return &StatusError{Op: op, Block: block, SW1: sw1, SW2: sw2, Err: err}
+	}
+	return nil
+}
+
+// wrapTransportErr associa erros PC/SC aos sentinelas do pacote, mantendo o original.
+func wrapTransportErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, scard.ErrRemovedCard), errors.Is(err, scard.ErrNoSmartcard),
+		errors.Is(err, scard.ErrResetCard):
+		return fmt.Errorf("%w: %w", ErrCardRemoved, err)
+	case errors.Is(err, scard.ErrNoReadersAvailable), errors.Is(err, scard.ErrUnknownReader),
+		errors.Is(err, scard.ErrReaderUnavailable), errors.Is(err, scard.ErrNoService):
+		return fmt.Errorf("%w: %w", ErrNoReader, err)
+	}
+	return err
+}
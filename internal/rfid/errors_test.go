@@ -0,0 +1,78 @@
+package rfid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ebfe/scard"
+)
+
+func TestDecodeSW(t *testing.T) {
+	testes := []struct {
+		op       string
+		sw1, sw2 byte
+		esperado error
+	}{
+		{"read", 0x90, 0x00, nil},
+		{"auth", 0x63, 0x00, ErrAuthFailed},
+		{"read", 0x63, 0x00, ErrOperationFailed},
+		{"read", 0x69, 0x82, ErrAuthFailed},
+		{"write", 0x69, 0x86, ErrAccessDenied},
+		{"read", 0x6A, 0x82, ErrBlockNotFound},
+		{"uid", 0x6A, 0x81, ErrNotSupported},
+		{"write", 0x67, 0x00, ErrWrongLength},
+		{"read", 0x6F, 0x00, ErrOperationFailed},
+	}
+
+	for _, tt := range testes {
+		err := DecodeSW(tt.op, tt.sw1, tt.sw2)
+		if err != tt.esperado {
+			t.Errorf("DecodeSW(%s, %02X%02X) = %v, esperado %v", tt.op, tt.sw1, tt.sw2, err, tt.esperado)
+		}
+	}
+}
+
+func TestCheckSW(t *testing.T) {
+	err := checkSW([]byte{0x63, 0x00}, "auth", 4)
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("checkSW deveria retornar *StatusError, obtido %T", err)
+	}
+	if se.SW() != 0x6300 || se.Block != 4 || !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("StatusError inesperado: %+v", se)
+	}
+	if msg := err.Error(); msg != "auth bloco 4: autenticação falhou (SW 6300)" {
+		t.Errorf("mensagem = %q", msg)
+	}
+
+	// Resposta curta não pode causar panic
+	if err := checkSW([]byte{0x90}, "read", 4); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("resposta de 1 byte = %v, esperado ErrInvalidResponse", err)
+	}
+	if err := checkSW(nil, "read", 4); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("resposta vazia = %v, esperado ErrInvalidResponse", err)
+	}
+}
+
+// failingTransport simula falhas de transporte PC/SC.
+type failingTransport struct{ err error }
+
+func (f failingTransport) Transmit([]byte) ([]byte, error) { return nil, f.err }
+func (f failingTransport) Close() error                    { return nil }
+
+func TestReaderErrorsAreTyped(t *testing.T) {
+	rdr, _ := newTestReader(t)
+	_, err := rdr.TryReadBlock(4, KeyTypeA, "A0A1A2A3A4A5")
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("TryReadBlock com key errada = %v, esperado ErrAuthFailed", err)
+	}
+
+	removed := NewReader(failingTransport{err: scard.ErrRemovedCard})
+	if _, err := removed.UID(); !errors.Is(err, ErrCardRemoved) || !errors.Is(err, scard.ErrRemovedCard) {
+		t.Errorf("UID com tag removida = %v, esperado ErrCardRemoved", err)
+	}
+	err = removed.WriteBlockDirectly(4, "FFFFFFFFFFFF", "00112233445566778899AABBCCDDEEFF", testUID)
+	if !errors.Is(err, ErrCardRemoved) {
+		t.Errorf("WriteBlockDirectly com tag removida = %v, esperado ErrCardRemoved", err)
+	}
+}
@@ -0,0 +1,92 @@
+package rfid
+
+// LED e buzzer do ACR122U (pseudo-APDUs FF 00 40 e FF 00 52).
+//
+//   rdr.Signal(SignalRead)             // pisca verde + bipe curto
+//   rdr.Signal(SignalError.Silent())   // só o LED
+//   rdr.SetDetectBeep(false)           // sem o bipe ao detectar a tag
+//
+// Leitores sem LED/buzzer controláveis (PN532 via UART, emulador, outros
+// PC/SC) respondem com ErrNotSupported.
+
+import "fmt"
+
+// Bits de P2 do FF 00 40 (controle dos LEDs).
+const (
+	ledRedFinal     = 0x01
+	ledGreenFinal   = 0x02
+	ledRedMask      = 0x04
+	ledGreenMask    = 0x08
+	ledRedBlink     = 0x10 // estado inicial do vermelho ao piscar
+	ledGreenBlink   = 0x20 // estado inicial do verde ao piscar
+	ledRedBlinkOn   = 0x40 // vermelho pisca
+	ledGreenBlinkOn = 0x80 // verde pisca
+)
+
+// Buzzer durante o piscar do FF 00 40.
+const (
+	BuzzerOff = 0x00
+	BuzzerT1  = 0x01 // liga durante o T1 (LED aceso)
+	BuzzerT2  = 0x02 // liga durante o T2 (LED apagado)
+)
+
+// Signal padrão de LED/buzzer. O estado final dos LEDs não muda.
+type Signal struct {
+	LED    byte // P2: LEDs que piscam e estado inicial
+	On     byte // T1 em unidades de 100 ms
+	Off    byte // T2 em unidades de 100 ms
+	Repeat byte
+	Buzzer byte
+}
+
+// Padrões usados pela aplicação.
+var (
+	// SignalRead tag lida: verde pisca uma vez com bipe curto.
+	SignalRead = Signal{LED: ledGreenBlinkOn | ledGreenBlink, On: 1, Off: 1, Repeat: 1, Buzzer: BuzzerT1}
+	// SignalWrite gravação verificada: verde pisca duas vezes, dois bipes.
+	SignalWrite = Signal{LED: ledGreenBlinkOn | ledGreenBlink, On: 1, Off: 1, Repeat: 2, Buzzer: BuzzerT1}
+	// SignalError falha: vermelho aceso com bipe longo.
+	SignalError = Signal{LED: ledRedBlinkOn | ledRedBlink, On: 8, Off: 1, Repeat: 1, Buzzer: BuzzerT1}
+)
+
+// Silent devolve o mesmo padrão sem o buzzer.
+func (s Signal) Silent() Signal {
+	s.Buzzer = BuzzerOff
+	return s
+}
+
+// APDU monta o FF 00 40 <P2> 04 <T1> <T2> <N> <buzzer>.
+func (s Signal) APDU() []byte {
+	return []byte{0xFF, 0x00, 0x40, s.LED, 0x04, s.On, s.Off, s.Repeat, s.Buzzer}
+}
+
+// Signal pisca os LEDs e toca o buzzer do ACR122U. Bloqueia enquanto o
+// padrão toca.
+func (r *Reader) Signal(s Signal) error {
+	resp, err := r.transmit(s.APDU())
+	if err != nil {
+		return fmt.Errorf("led: %w", err)
+	}
+	// Sucesso é 90 <estado atual dos LEDs>
+	if len(resp) == 2 && resp[0] == 0x90 {
+		return nil
+	}
+	return checkSW(resp, "led", -1)
+}
+
+// SetDetectBeep liga ou desliga o bipe do ACR122U ao detectar uma tag
+// (FF 00 52). A configuração vale até o leitor ser desligado.
+func (r *Reader) SetDetectBeep(on bool) error {
+	p2 := byte(0x00)
+	if on {
+		p2 = 0xFF
+	}
+	resp, err := r.transmit([]byte{0xFF, 0x00, 0x52, p2, 0x00})
+	if err != nil {
+		return fmt.Errorf("buzzer: %w", err)
+	}
+	if len(resp) == 2 && resp[0] == 0x90 {
+		return nil
+	}
+	return checkSW(resp, "buzzer", -1)
+}
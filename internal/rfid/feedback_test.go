@@ -0,0 +1,77 @@
+package rfid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// ledReader responde aos comandos de LED/buzzer como o ACR122U e guarda o
+// último APDU.
+type ledReader struct {
+	last []byte
+}
+
+func (l *ledReader) Transmit(cmd []byte) ([]byte, error) {
+	l.last = clone(cmd)
+	if cmd[2] == 0x40 {
+		return []byte{0x90, 0x02}, nil // 90 <LEDs>: verde aceso
+	}
+	return clone(swOK), nil
+}
+
+func (l *ledReader) Close() error { return nil }
+
+func TestSignalAPDU(t *testing.T) {
+	testes := []struct {
+		nome     string
+		entrada  Signal
+		esperado []byte
+	}{
+		{"leitura", SignalRead, []byte{0xFF, 0x00, 0x40, 0xA0, 0x04, 0x01, 0x01, 0x01, 0x01}},
+		{"gravação", SignalWrite, []byte{0xFF, 0x00, 0x40, 0xA0, 0x04, 0x01, 0x01, 0x02, 0x01}},
+		{"erro", SignalError, []byte{0xFF, 0x00, 0x40, 0x50, 0x04, 0x08, 0x01, 0x01, 0x01}},
+		{"erro sem bipe", SignalError.Silent(), []byte{0xFF, 0x00, 0x40, 0x50, 0x04, 0x08, 0x01, 0x01, 0x00}},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			l := &ledReader{}
+			if err := NewReader(l).Signal(tt.entrada); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(l.last, tt.esperado) {
+				t.Errorf("APDU % X, esperado % X", l.last, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestSetDetectBeep(t *testing.T) {
+	testes := []struct {
+		entrada  bool
+		esperado byte
+	}{
+		{true, 0xFF},
+		{false, 0x00},
+	}
+	for _, tt := range testes {
+		l := &ledReader{}
+		if err := NewReader(l).SetDetectBeep(tt.entrada); err != nil {
+			t.Fatal(err)
+		}
+		if want := []byte{0xFF, 0x00, 0x52, tt.esperado, 0x00}; !bytes.Equal(l.last, want) {
+			t.Errorf("SetDetectBeep(%v): APDU % X, esperado % X", tt.entrada, l.last, want)
+		}
+	}
+}
+
+func TestSignalNotSupported(t *testing.T) {
+	// O emulador, como o PN532 via UART, não tem LED/buzzer
+	rdr, _ := newTestReader(t)
+	if err := rdr.Signal(SignalRead); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Signal: esperado ErrNotSupported, obtido %v", err)
+	}
+	if err := rdr.SetDetectBeep(false); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("SetDetectBeep: esperado ErrNotSupported, obtido %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package rfid
+
+import (
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
+)
+
+// Logging do pacote. Nada é registrado até SetLogger; com SetTrace(true)
+// cada APDU é registrado no nível logging.LevelTrace com as keys em claro.
+// Sem trace, keys aparecem mascaradas (MaskKey) nos logs.
+
+var (
+	logger atomic.Pointer[slog.Logger]
+	trace  atomic.Bool
+)
+
+func init() {
+	logger.Store(slog.New(slog.DiscardHandler))
+}
+
+// SetLogger define o logger do pacote; nil desliga o log.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = slog.New(slog.DiscardHandler)
+	}
+	logger.Store(l)
+}
+
+// SetTrace liga o trace de APDUs (e desliga o mascaramento de keys).
+func SetTrace(on bool) {
+	trace.Store(on)
+}
+
+func log() *slog.Logger {
+	return logger.Load()
+}
+
+// MaskKey mascara uma key hex para o log, mantendo o primeiro byte.
+// Com trace ligado devolve a key inteira.
+func MaskKey(key string) string {
+	if trace.Load() || len(key) <= 2 {
+		return key
+	}
+	return key[:2] + strings.Repeat("*", len(key)-2)
+}
+
+// apduOps nome da operação pelo INS dos pseudo-APDUs do ACR122U.
+var apduOps = map[byte]string{
+	0xCA: "uid",
+	0x82: "load key",
+	0x86: "auth",
+	0xB0: "read",
+	0xD6: "write",
+	0x00: "pn532",
+}
+
+// traceAPDU registra comando, resposta, SW decodificado e latência.
+func traceAPDU(cmd, resp []byte, err error, elapsed time.Duration) {
+	l := log()
+	if !l.Enabled(context.Background(), logging.LevelTrace) {
+		return
+	}
+	op := "?"
+	if len(cmd) > 1 {
+		if name, ok := apduOps[cmd[1]]; ok {
+			op = name
+		}
+	}
+	attrs := []slog.Attr{
+		slog.String("op", op),
+		slog.String("cmd", strings.ToUpper(hex.EncodeToString(cmd))),
+		slog.Duration("latency", elapsed),
+	}
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.String("err", err.Error()))
+	case len(resp) >= 2:
+		sw1, sw2 := resp[len(resp)-2], resp[len(resp)-1]
+		status := "ok"
+		if swErr := DecodeSW(op, sw1, sw2); swErr != nil {
+			status = swErr.Error()
+		}
+		attrs = append(attrs,
+			slog.String("resp", strings.ToUpper(hex.EncodeToString(resp))),
+			slog.String("sw", strings.ToUpper(hex.EncodeToString(resp[len(resp)-2:]))),
+			slog.String("status", status))
+	default:
+		attrs = append(attrs, slog.String("resp", strings.ToUpper(hex.EncodeToString(resp))))
+	}
+	l.LogAttrs(context.Background(), logging.LevelTrace, "apdu", attrs...)
+}
@@ -0,0 +1,95 @@
+package rfid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
+)
+
+func TestMaskKey(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		trace    bool
+		esperado string
+	}{
+		{"A1B2C3D4E5F6", false, "A1**********"},
+		{"A1B2C3D4E5F6", true, "A1B2C3D4E5F6"},
+		{"", false, ""},
+	}
+	defer SetTrace(false)
+	for _, tt := range testes {
+		SetTrace(tt.trace)
+		if got := MaskKey(tt.entrada); got != tt.esperado {
+			t.Errorf("MaskKey(%q, trace=%v) = %q, esperado %q", tt.entrada, tt.trace, got, tt.esperado)
+		}
+	}
+}
+
+// captureLog direciona o log do pacote para um buffer durante o teste.
+func captureLog(t *testing.T, trace bool) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetLogger(logging.New(&buf, logging.LevelTrace))
+	SetTrace(trace)
+	t.Cleanup(func() {
+		SetLogger(nil)
+		SetTrace(false)
+	})
+	return &buf
+}
+
+func TestWriteTagCFSLogsMaskedKeys(t *testing.T) {
+	testes := []struct {
+		trace    bool
+		apdus    bool
+		keyClara bool
+	}{
+		{false, false, false},
+		{true, true, true},
+	}
+	for _, tt := range testes {
+		buf := captureLog(t, tt.trace)
+		card, err := NewEmulatedCard(testUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := NewReader(card).WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+			t.Fatalf("WriteTagCFS erro: %v", err)
+		}
+
+		out := buf.String()
+		key := NewReader(card).DeriveKeyFromUID(testUID)
+		if got := strings.Contains(out, key); got != tt.keyClara {
+			t.Errorf("trace=%v: key derivada em claro no log = %v, esperado %v\n%s", tt.trace, got, tt.keyClara, out)
+		}
+		if got := strings.Contains(out, "msg=apdu"); got != tt.apdus {
+			t.Errorf("trace=%v: APDUs no log = %v, esperado %v", tt.trace, got, tt.apdus)
+		}
+		if !strings.Contains(out, "trailer atualizado") {
+			t.Errorf("trace=%v: log sem a migração do trailer:\n%s", tt.trace, out)
+		}
+	}
+}
+
+func TestTraceAPDUDecodesSW(t *testing.T) {
+	buf := captureLog(t, true)
+	card, err := NewEmulatedCard(testUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewReader(card)
+	r.UID()
+	r.auth(4, KeyTypeA, "000000000000") // key errada: 63 00
+
+	out := buf.String()
+	for _, want := range []string{"op=uid", "sw=9000", "status=ok", "op=auth", "sw=6300", "latency="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace sem %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, ErrAuthFailed.Error()) {
+		t.Errorf("SW 6300 do auth não decodificado:\n%s", out)
+	}
+}
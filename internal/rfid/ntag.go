@@ -0,0 +1,175 @@
+package rfid
+
+// Tags NTAG21x (NFC Forum Tipo 2): páginas de 4 bytes, sem autenticação.
+// A área de dados começa na página 4 e guarda TLVs; o Capability
+// Container (página 3) diz se a tag está formatada para NDEF e o tamanho
+// da área.
+//
+//   msg, err := rdr.ReadNDEF()         // mensagem NDEF sem o TLV
+//   err = rdr.WriteNDEF(msg)           // grava 03 <len> <msg> FE na página 4
+//   raw, err := rdr.ReadUserData(36)   // formatos binários sem NDEF (TigerTag)
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+)
+
+// NTAG modelo NTAG21x.
+type NTAG struct {
+	Name  string
+	Pages int  // total de páginas, incluindo configuração
+	Size  byte // byte 2 do CC: área de dados / 8
+}
+
+var (
+	NTAG213 = NTAG{Name: "NTAG213", Pages: 45, Size: 0x12}
+	NTAG215 = NTAG{Name: "NTAG215", Pages: 135, Size: 0x3E}
+	NTAG216 = NTAG{Name: "NTAG216", Pages: 231, Size: 0x6D}
+)
+
+const (
+	ntagCCPage   = 3
+	ntagDataPage = 4
+	ntagCCMagic  = 0xE1
+)
+
+// ReadPages lê 16 bytes (4 páginas) a partir da página informada.
+func (r *Reader) ReadPages(page byte) ([]byte, error) {
+	resp, err := r.transmit([]byte{0xFF, 0xB0, 0x00, page, 16})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSW(resp, "read", int(page)); err != nil {
+		return nil, err
+	}
+	if len(resp) != 18 {
+		return nil, &StatusError{Op: "read", Block: int(page), SW1: 0x90, Err: ErrInvalidResponse}
+	}
+	return resp[:16], nil
+}
+
+// WritePage grava uma página de 4 bytes.
+func (r *Reader) WritePage(page byte, data []byte) error {
+	if len(data) != 4 {
+		return &StatusError{Op: "write", Block: int(page), Err: ErrWrongLength}
+	}
+	resp, err := r.transmit(append([]byte{0xFF, 0xD6, 0x00, page, 4}, data...))
+	if err != nil {
+		return err
+	}
+	return checkSW(resp, "write", int(page))
+}
+
+// NDEFCapacity lê o CC e devolve o tamanho da área de dados em bytes.
+// ErrNotNDEF se a tag não estiver formatada; ErrAccessDenied se o CC a
+// marca como somente leitura e write for true.
+func (r *Reader) NDEFCapacity(write bool) (int, error) {
+	cc, err := r.ReadPages(ntagCCPage)
+	if err != nil {
+		return 0, err
+	}
+	if cc[0] != ntagCCMagic || cc[2] == 0 {
+		return 0, fmt.Errorf("%w: CC %X", ErrNotNDEF, cc[:4])
+	}
+	if write && cc[3]&0x0F != 0 {
+		return 0, fmt.Errorf("%w: tag NDEF somente leitura (CC %X)", ErrAccessDenied, cc[:4])
+	}
+	return int(cc[2]) * 8, nil
+}
+
+// ReadNDEF lê a primeira mensagem NDEF da tag, lendo só as páginas que o
+// TLV ocupa.
+func (r *Reader) ReadNDEF() ([]byte, error) {
+	size, err := r.NDEFCapacity(false)
+	if err != nil {
+		return nil, err
+	}
+	var area []byte
+	for len(area) < size {
+		data, err := r.ReadPages(byte(ntagDataPage + len(area)/4))
+		if err != nil {
+			return nil, err
+		}
+		area = append(area, data...)
+		if n := ndef.TLVSize(area); n > 0 && n <= len(area) {
+			break
+		}
+	}
+	if len(area) > size {
+		area = area[:size]
+	}
+	return ndef.UnwrapTLV(area)
+}
+
+// WriteNDEF grava a mensagem no TLV NDEF a partir da página 4. O
+// tamanho do TLV é gravado por último: se a tag sair no meio, ela fica
+// com uma mensagem vazia em vez de uma truncada.
+func (r *Reader) WriteNDEF(msg []byte) error {
+	size, err := r.NDEFCapacity(true)
+	if err != nil {
+		return err
+	}
+	area := ndef.WrapTLV(msg)
+	if len(area) > size {
+		return fmt.Errorf("%w: %d bytes, capacidade %d", ErrTagFull, len(area), size)
+	}
+	for len(area)%4 != 0 {
+		area = append(area, 0x00)
+	}
+
+	first := clone(area[:4])
+	empty := clone(first)
+	if len(msg) < 0xFF {
+		empty[1] = 0x00
+	} else {
+		empty[2], empty[3] = 0x00, 0x00
+	}
+	if err := r.WritePage(ntagDataPage, empty); err != nil {
+		return err
+	}
+	for i := 4; i < len(area); i += 4 {
+		if err := r.WritePage(byte(ntagDataPage+i/4), area[i:i+4]); err != nil {
+			return err
+		}
+	}
+	return r.WritePage(ntagDataPage, first)
+}
+
+// ReadUserData lê os primeiros n bytes da área de dados (página 4 em
+// diante), para formatos gravados direto nas páginas, sem TLV.
+func (r *Reader) ReadUserData(n int) ([]byte, error) {
+	var area []byte
+	for len(area) < n {
+		data, err := r.ReadPages(byte(ntagDataPage + len(area)/4))
+		if err != nil {
+			return nil, err
+		}
+		area = append(area, data...)
+	}
+	return area[:n], nil
+}
+
+// WriteUserData grava data a partir da página 4, completando a última
+// página com 00. O tamanho é conferido com o CC quando a tag o tem.
+func (r *Reader) WriteUserData(data []byte) error {
+	size, err := r.NDEFCapacity(true)
+	switch {
+	case errors.Is(err, ErrNotNDEF):
+	case err != nil:
+		return err
+	case len(data) > size:
+		return fmt.Errorf("%w: %d bytes, capacidade %d", ErrTagFull, len(data), size)
+	}
+	area := clone(data)
+	for len(area)%4 != 0 {
+		area = append(area, 0x00)
+	}
+	for i := 0; i < len(area); i += 4 {
+		if err := r.WritePage(byte(ntagDataPage+i/4), area[i:i+4]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package rfid
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+)
+
+const testNTAGUID = "04A1B2C3D4E5F6"
+
+func newTestNTAG(t *testing.T, model NTAG) (*EmulatedNTAG, *Reader) {
+	t.Helper()
+	tag, err := NewEmulatedNTAG(testNTAGUID, model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tag, NewReader(tag)
+}
+
+func TestNTAGIdentify(t *testing.T) {
+	_, rdr := newTestNTAG(t, NTAG215)
+	info, err := rdr.Identify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Type != CardUltralight || info.UID != testNTAGUID || info.SAK != "00" || info.ATQA != "0044" {
+		t.Errorf("Identify = %+v", info)
+	}
+	if err := info.Supported(); !errors.Is(err, ErrUnsupportedCard) {
+		t.Errorf("NTAG não recebe tag CFS: esperado ErrUnsupportedCard, obtido %v", err)
+	}
+}
+
+func TestNDEFRoundTrip(t *testing.T) {
+	testes := []struct {
+		nome    string
+		modelo  NTAG
+		payload int
+	}{
+		{"curta NTAG213", NTAG213, 40},
+		{"TLV longo NTAG215", NTAG215, 300},
+		{"NTAG216 quase cheia", NTAG216, 840},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			tag, rdr := newTestNTAG(t, tt.modelo)
+			msg := ndef.Encode(ndef.MediaRecord("application/json", bytes.Repeat([]byte("x"), tt.payload)))
+			if err := rdr.WriteNDEF(msg); err != nil {
+				t.Fatalf("WriteNDEF erro: %v", err)
+			}
+			got, err := rdr.ReadNDEF()
+			if err != nil {
+				t.Fatalf("ReadNDEF erro: %v", err)
+			}
+			if !bytes.Equal(got, msg) {
+				t.Errorf("mensagem relida difere: %d bytes, esperado %d", len(got), len(msg))
+			}
+			if cc := tag.Page(ntagCCPage); cc[0] != ntagCCMagic {
+				t.Errorf("CC alterado: % X", cc)
+			}
+		})
+	}
+}
+
+func TestNDEFErrors(t *testing.T) {
+	tag, rdr := newTestNTAG(t, NTAG213)
+	if _, err := rdr.ReadNDEF(); !errors.Is(err, ndef.ErrNoMessage) {
+		t.Errorf("tag vazia: esperado ErrNoMessage, obtido %v", err)
+	}
+
+	big := ndef.Encode(ndef.MediaRecord("application/json", bytes.Repeat([]byte("x"), 200)))
+	if err := rdr.WriteNDEF(big); !errors.Is(err, ErrTagFull) {
+		t.Errorf("mensagem grande: esperado ErrTagFull, obtido %v", err)
+	}
+	if got := tag.Page(ntagDataPage); got[0] != 0x03 || got[1] != 0x00 {
+		t.Errorf("tag cheia não deveria ser alterada: % X", got)
+	}
+
+	tag.SetPage(ntagCCPage, []byte{0xE1, 0x10, 0x12, 0x0F})
+	if err := rdr.WriteNDEF([]byte{0xD0, 0x00, 0x00}); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("CC somente leitura: esperado ErrAccessDenied, obtido %v", err)
+	}
+
+	tag.SetPage(ntagCCPage, []byte{0x00, 0x00, 0x00, 0x00})
+	if _, err := rdr.ReadNDEF(); !errors.Is(err, ErrNotNDEF) || !strings.Contains(err.Error(), "CC") {
+		t.Errorf("sem CC: esperado ErrNotNDEF, obtido %v", err)
+	}
+}
+
+func TestUserData(t *testing.T) {
+	tag, rdr := newTestNTAG(t, NTAG213)
+	data := bytes.Repeat([]byte{0xA5}, 37)
+	if err := rdr.WriteUserData(data); err != nil {
+		t.Fatalf("WriteUserData erro: %v", err)
+	}
+	got, err := rdr.ReadUserData(len(data))
+	if err != nil {
+		t.Fatalf("ReadUserData erro: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("dados relidos = % X", got)
+	}
+	if p := tag.Page(ntagDataPage + 9); !bytes.Equal(p, []byte{0xA5, 0x00, 0x00, 0x00}) {
+		t.Errorf("última página = % X, esperado A5 completado com 00", p)
+	}
+	if err := rdr.WriteUserData(make([]byte, 145)); !errors.Is(err, ErrTagFull) {
+		t.Errorf("dados maiores que a NTAG213: esperado ErrTagFull, obtido %v", err)
+	}
+}
@@ -0,0 +1,372 @@
+package rfid
+
+// Backend PN532 nativo via UART, sem PC/SC.
+//
+//   rdr, _ := OpenPN532("/dev/ttyUSB0")   // ou OpenReader("pn532:/dev/ttyUSB0")
+//   defer rdr.Close()
+//
+// pn532Transport traduz os pseudo-APDUs do ACR122U usados pelo Reader
+// (FF CA, FF 82, FF 86, FF B0, FF D6 e FF 00 00 00 direto) em comandos
+// PN532 (InListPassiveTarget, InDataExchange), então o resto do pacote
+// funciona igual nos dois backends.
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.bug.st/serial"
+	"go.bug.st/serial/enumerator"
+)
+
+// PN532Prefix prefixo dos nomes de leitor PN532 (ex.: "pn532:/dev/ttyUSB0").
+const PN532Prefix = "pn532:"
+
+const (
+	pn532BaudRate    = 115200
+	pn532PollTimeout = 50 * time.Millisecond // timeout de cada Read na porta
+	pn532AckTimeout  = 500 * time.Millisecond
+	pn532RespTimeout = 2 * time.Second
+)
+
+var (
+	errPN532Timeout = errors.New("PN532 não respondeu")
+	errPN532Frame   = errors.New("quadro PN532 inválido")
+	errPN532Cancel  = errors.New("comando PN532 cancelado")
+)
+
+// PN532Port extrai a porta serial de um nome de leitor PN532.
+func PN532Port(name string) (string, bool) {
+	return strings.CutPrefix(name, PN532Prefix)
+}
+
+// ListPN532Ports lista as portas seriais USB como candidatos a PN532.
+// Portas não-USB (ttyS*) ficam de fora: não dá para sondá-las sem risco.
+func ListPN532Ports() ([]string, error) {
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, p := range ports {
+		if p.IsUSB {
+			out = append(out, PN532Prefix+p.Name)
+		}
+	}
+	return out, nil
+}
+
+// OpenPN532 abre um PN532 em modo UART (HSU) na porta serial informada.
+func OpenPN532(path string) (*Reader, error) {
+	port, err := serial.Open(path, &serial.Mode{BaudRate: pn532BaudRate})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrNoReader, path, err)
+	}
+	if err := port.SetReadTimeout(pn532PollTimeout); err != nil {
+		port.Close()
+		return nil, err
+	}
+	t := &pn532Transport{port: port}
+	if err := t.init(); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("%w: %s: %v", ErrNoReader, path, err)
+	}
+	return NewReader(t), nil
+}
+
+// pn532Transport implementa Transport falando o protocolo de quadros do
+// PN532 (00 00 FF LEN LCS D4 ... DCS 00).
+type pn532Transport struct {
+	port    io.ReadWriteCloser // Read deve retornar (0, nil) no timeout
+	pending []byte
+	keys    [2][]byte // slots do FF 82
+	uid     []byte    // alvo selecionado (Tg 1)
+
+	canceled atomic.Bool // Cancel pendente: a espera em reader aborta
+}
+
+// init acorda o PN532 (HSU dorme até receber 55 55 00...), confere o
+// firmware, liga o modo normal do SAM e limita as tentativas de
+// InListPassiveTarget para ele retornar logo quando não há tag.
+func (t *pn532Transport) init() error {
+	wakeup := append([]byte{0x55, 0x55}, make([]byte, 14)...)
+	if _, err := t.port.Write(wakeup); err != nil {
+		return err
+	}
+	fw, err := t.command(0x02)
+	if err != nil {
+		return err
+	}
+	if len(fw) < 1 || fw[0] != 0x32 {
+		return fmt.Errorf("%w: firmware % X", errPN532Frame, fw)
+	}
+	if _, err := t.command(0x14, 0x01, 0x14, 0x01); err != nil { // SAMConfiguration
+		return err
+	}
+	// RFConfiguration item 05: MxRtyATR, MxRtyPSL, MxRtyPassiveActivation
+	_, err = t.command(0x32, 0x05, 0xFF, 0x01, 0x02)
+	return err
+}
+
+func (t *pn532Transport) Transmit(cmd []byte) ([]byte, error) {
+	if len(cmd) < 5 {
+		return clone(swWrongLength), nil
+	}
+	if cmd[0] != 0xFF {
+		return clone(swClassNotSupp), nil
+	}
+	if cmd[1] == 0x00 && cmd[2] == 0x00 && cmd[3] == 0x00 {
+		return t.direct(cmd)
+	}
+	switch cmd[1] {
+	case 0xCA:
+		if cmd[2] != 0x00 {
+			return clone(swNotSupported), nil
+		}
+		if err := t.selectCard(); err != nil {
+			return nil, err
+		}
+		return append(clone(t.uid), swOK...), nil
+	case 0x82:
+		if len(cmd) != 11 || cmd[3] > 1 {
+			return clone(swWrongLength), nil
+		}
+		t.keys[cmd[3]] = clone(cmd[5:11])
+		return clone(swOK), nil
+	case 0x86:
+		if len(cmd) != 10 {
+			return clone(swWrongLength), nil
+		}
+		block, keyType, slot := cmd[7], cmd[8], cmd[9]
+		if slot > 1 || t.keys[slot] == nil || t.uid == nil {
+			return clone(swFailed), nil
+		}
+		// MIFARE auth: 60|61 <bloco> <key 6> <últimos 4 bytes do UID>
+		args := append([]byte{keyType, block}, t.keys[slot]...)
+		args = append(args, t.uid[len(t.uid)-4:]...)
+		_, err := t.exchange(args...)
+		return t.statusSW(err)
+	case 0xB0:
+		data, err := t.exchange(0x30, cmd[3])
+		if err == nil && len(data) != 16 {
+			err = errPN532Frame
+		}
+		if sw, err := t.statusSW(err); err != nil || sw[0] != 0x90 {
+			return sw, err
+		}
+		return append(data, swOK...), nil
+	case 0xD6:
+		switch len(cmd) {
+		case 21: // MIFARE Classic: 16 bytes
+			_, err := t.exchange(append([]byte{0xA0, cmd[3]}, cmd[5:21]...)...)
+			return t.statusSW(err)
+		case 9: // NTAG/Ultralight: uma página de 4 bytes
+			_, err := t.exchange(append([]byte{0xA2, cmd[3]}, cmd[5:9]...)...)
+			return t.statusSW(err)
+		}
+		return clone(swWrongLength), nil
+	}
+	return clone(swNotSupported), nil
+}
+
+// direct repassa FF 00 00 00 <Lc> D4 <comando> ao PN532 e devolve a
+// resposta D5 <comando+1> ... + 90 00, como o ACR122U.
+func (t *pn532Transport) direct(cmd []byte) ([]byte, error) {
+	if len(cmd) < 7 || int(cmd[4]) != len(cmd)-5 || cmd[5] != 0xD4 {
+		return clone(swWrongLength), nil
+	}
+	resp, err := t.command(cmd[6], cmd[7:]...)
+	if err != nil {
+		return nil, err
+	}
+	if cmd[6] == 0x4A {
+		t.uid = targetUID(resp)
+	}
+	return append(append([]byte{0xD5, cmd[6] + 1}, resp...), swOK...), nil
+}
+
+// selectCard seleciona a tag no campo (InListPassiveTarget, 106 kbps tipo A).
+// Equivale ao FF CA do ACR122U, que também re-seleciona o cartão.
+func (t *pn532Transport) selectCard() error {
+	resp, err := t.command(0x4A, 0x01, 0x00)
+	if err != nil {
+		return err
+	}
+	t.uid = targetUID(resp)
+	if t.uid == nil {
+		return ErrCardRemoved
+	}
+	return nil
+}
+
+// targetUID extrai o NFCID de NbTg Tg ATQA(2) SAK NFCIDLength NFCID...
+func targetUID(resp []byte) []byte {
+	if len(resp) < 6 || resp[0] == 0 || len(resp) < 6+int(resp[5]) {
+		return nil
+	}
+	return clone(resp[6 : 6+int(resp[5])])
+}
+
+// exchange envia um comando MIFARE ao alvo 1 (InDataExchange) e devolve
+// os dados após o byte de status.
+func (t *pn532Transport) exchange(args ...byte) ([]byte, error) {
+	resp, err := t.command(0x40, append([]byte{0x01}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 1 {
+		return nil, errPN532Frame
+	}
+	if status := resp[0] & 0x3F; status != 0 {
+		return nil, pn532Status(status)
+	}
+	return resp[1:], nil
+}
+
+// pn532Status código de erro do byte de status do PN532.
+type pn532Status byte
+
+func (s pn532Status) Error() string {
+	return fmt.Sprintf("PN532 status %02X", byte(s))
+}
+
+// statusSW converte o resultado de InDataExchange em SW do ACR122U:
+// erro de status do PN532 vira 63 00; erro de comunicação sobe como erro.
+func (t *pn532Transport) statusSW(err error) ([]byte, error) {
+	var st pn532Status
+	switch {
+	case err == nil:
+		return clone(swOK), nil
+	case errors.As(err, &st):
+		return clone(swFailed), nil
+	}
+	return nil, err
+}
+
+// command envia um comando PN532 e devolve os dados da resposta sem D5 <cmd+1>.
+func (t *pn532Transport) command(code byte, args ...byte) ([]byte, error) {
+	t.canceled.Store(false)
+	t.pending = nil // sobra de um comando cancelado
+	data := append([]byte{0xD4, code}, args...)
+	if _, err := t.port.Write(encodeFrame(data)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoReader, err)
+	}
+
+	_, ack, err := readFrame(t.reader(time.Now().Add(pn532AckTimeout)))
+	if err != nil {
+		return nil, err
+	}
+	if !ack {
+		return nil, fmt.Errorf("%w: ACK esperado", errPN532Frame)
+	}
+
+	resp, ack, err := readFrame(t.reader(time.Now().Add(pn532RespTimeout)))
+	if err != nil {
+		return nil, err
+	}
+	if ack || len(resp) < 2 {
+		return nil, errPN532Frame
+	}
+	if resp[0] == 0x7F {
+		return nil, fmt.Errorf("%w: erro de aplicação", errPN532Frame)
+	}
+	if resp[0] != 0xD5 || resp[1] != code+1 {
+		return nil, fmt.Errorf("%w: resposta % X ao comando %02X", errPN532Frame, resp[:2], code)
+	}
+	return resp[2:], nil
+}
+
+// reader devolve uma função que lê o próximo byte da porta até o prazo.
+func (t *pn532Transport) reader(deadline time.Time) func() (byte, error) {
+	buf := make([]byte, 64)
+	return func() (byte, error) {
+		for len(t.pending) == 0 {
+			if time.Now().After(deadline) {
+				return 0, errPN532Timeout
+			}
+			if t.canceled.Load() {
+				return 0, errPN532Cancel
+			}
+			n, err := t.port.Read(buf)
+			if err != nil {
+				return 0, fmt.Errorf("%w: %v", ErrNoReader, err)
+			}
+			t.pending = append(t.pending, buf[:n]...)
+		}
+		b := t.pending[0]
+		t.pending = t.pending[1:]
+		return b, nil
+	}
+}
+
+// Cancel faz o comando em andamento desistir de esperar a resposta.
+func (t *pn532Transport) Cancel() error {
+	t.canceled.Store(true)
+	return nil
+}
+
+func (t *pn532Transport) Close() error {
+	return t.port.Close()
+}
+
+// encodeFrame monta um quadro normal: 00 00 FF LEN LCS <dados> DCS 00.
+func encodeFrame(data []byte) []byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	n := byte(len(data))
+	out := append([]byte{0x00, 0x00, 0xFF, n, -n}, data...)
+	return append(out, -sum, 0x00)
+}
+
+// readFrame lê o próximo quadro; ack=true para o quadro ACK (00 00 FF 00 FF 00).
+// Preâmbulo, postâmbulo e bytes de wakeup antes do start code são ignorados.
+func readFrame(next func() (byte, error)) (data []byte, ack bool, err error) {
+	prev := byte(0xFF)
+	for {
+		b, err := next()
+		if err != nil {
+			return nil, false, err
+		}
+		if prev == 0x00 && b == 0xFF {
+			break
+		}
+		prev = b
+	}
+
+	n, err := next()
+	if err != nil {
+		return nil, false, err
+	}
+	lcs, err := next()
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case n == 0x00 && lcs == 0xFF:
+		return nil, true, nil
+	case n+lcs != 0 || n == 0:
+		return nil, false, fmt.Errorf("%w: LEN %02X LCS %02X", errPN532Frame, n, lcs)
+	}
+
+	data = make([]byte, n)
+	var sum byte
+	for i := range data {
+		if data[i], err = next(); err != nil {
+			return nil, false, err
+		}
+		sum += data[i]
+	}
+	dcs, err := next()
+	if err != nil {
+		return nil, false, err
+	}
+	if sum+dcs != 0 {
+		return nil, false, fmt.Errorf("%w: checksum", errPN532Frame)
+	}
+	return data, false, nil
+}
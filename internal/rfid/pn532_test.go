@@ -0,0 +1,214 @@
+//go:build !windows
+
+package rfid
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/creack/pty"
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+)
+
+// fakePN532 emula um PN532 em HSU do outro lado de um pseudo-terminal,
+// traduzindo InDataExchange para o cartão emulado (card nil = campo vazio).
+type fakePN532 struct {
+	master *os.File
+	card   Transport
+}
+
+// startFakePN532 devolve o caminho do pty escravo onde o PN532 responde.
+func startFakePN532(t *testing.T, card *EmulatedCard) string {
+	t.Helper()
+	if card == nil {
+		return startFakePN532Tag(t, nil)
+	}
+	return startFakePN532Tag(t, card)
+}
+
+// startFakePN532Tag como startFakePN532, com qualquer cartão emulado.
+func startFakePN532Tag(t *testing.T, card Transport) string {
+	t.Helper()
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Skipf("pty indisponível: %v", err)
+	}
+	f := &fakePN532{master: master, card: card}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.serve()
+	}()
+	t.Cleanup(func() {
+		master.Close()
+		slave.Close()
+		<-done
+	})
+	return slave.Name()
+}
+
+func (f *fakePN532) serve() {
+	r := bufio.NewReader(f.master)
+	for {
+		frame, ack, err := readFrame(r.ReadByte)
+		if err != nil {
+			return
+		}
+		if ack || len(frame) < 2 || frame[0] != 0xD4 {
+			continue
+		}
+		f.master.Write([]byte{0x00, 0x00, 0xFF, 0x00, 0xFF, 0x00})
+		resp := append([]byte{0xD5, frame[1] + 1}, f.handle(frame[1], frame[2:])...)
+		f.master.Write(encodeFrame(resp))
+	}
+}
+
+func (f *fakePN532) handle(code byte, args []byte) []byte {
+	switch code {
+	case 0x02: // GetFirmwareVersion: PN532 v1.6
+		return []byte{0x32, 0x01, 0x06, 0x07}
+	case 0x14: // SAMConfiguration
+		return nil
+	case 0x40: // InDataExchange
+		if f.card == nil || len(args) < 3 {
+			return []byte{0x01}
+		}
+		return f.exchange(args[1:])
+	}
+	if f.card == nil {
+		if code == 0x4A {
+			return []byte{0x00}
+		}
+		return nil
+	}
+	// Demais comandos (4A, 08, 32, 42) o EmulatedCard já entende
+	apdu := append([]byte{0xFF, 0x00, 0x00, 0x00, byte(len(args) + 2), 0xD4, code}, args...)
+	resp, _ := f.card.Transmit(apdu)
+	if len(resp) < 4 {
+		return nil
+	}
+	return resp[2 : len(resp)-2]
+}
+
+// exchange traduz os comandos MIFARE para pseudo-APDUs do emulador.
+func (f *fakePN532) exchange(mc []byte) []byte {
+	var resp []byte
+	switch mc[0] {
+	case 0x60, 0x61:
+		f.card.Transmit(append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, mc[2:8]...))
+		resp, _ = f.card.Transmit([]byte{0xFF, 0x86, 0x00, 0x00, 0x05, 0x01, 0x00, mc[1], mc[0], 0x00})
+	case 0x30:
+		resp, _ = f.card.Transmit([]byte{0xFF, 0xB0, 0x00, mc[1], 16})
+	case 0xA0:
+		resp, _ = f.card.Transmit(append([]byte{0xFF, 0xD6, 0x00, mc[1], 16}, mc[2:]...))
+	case 0xA2:
+		resp, _ = f.card.Transmit(append([]byte{0xFF, 0xD6, 0x00, mc[1], 4}, mc[2:]...))
+	default:
+		return []byte{0x27} // comando inválido no contexto
+	}
+	if !bytes.HasSuffix(resp, swOK) {
+		return []byte{0x14} // erro MIFARE (auth/acesso)
+	}
+	return append([]byte{0x00}, resp[:len(resp)-2]...)
+}
+
+func TestEncodeFrame(t *testing.T) {
+	// GetFirmwareVersion, exemplo do manual do PN532 (UM0701-02)
+	want := []byte{0x00, 0x00, 0xFF, 0x02, 0xFE, 0xD4, 0x02, 0x2A, 0x00}
+	if got := encodeFrame([]byte{0xD4, 0x02}); !bytes.Equal(got, want) {
+		t.Errorf("encodeFrame = % X, esperado % X", got, want)
+	}
+
+	// Wakeup e preâmbulo antes do start code são ignorados
+	stream := append([]byte{0x55, 0x55, 0x00, 0x00, 0x00}, want...)
+	r := bufio.NewReader(bytes.NewReader(stream))
+	data, ack, err := readFrame(r.ReadByte)
+	if err != nil || ack || !bytes.Equal(data, []byte{0xD4, 0x02}) {
+		t.Errorf("readFrame = % X, %v, %v", data, ack, err)
+	}
+
+	corrupt := append([]byte{}, want...)
+	corrupt[7] ^= 0xFF
+	r = bufio.NewReader(bytes.NewReader(corrupt))
+	if _, _, err := readFrame(r.ReadByte); !errors.Is(err, errPN532Frame) {
+		t.Errorf("checksum errado: esperado errPN532Frame, obtido %v", err)
+	}
+}
+
+func TestPN532ReaderOverPTY(t *testing.T) {
+	card, err := NewEmulatedCard(testUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdr, err := OpenReader(PN532Prefix + startFakePN532(t, card))
+	if err != nil {
+		t.Fatalf("OpenReader erro: %v", err)
+	}
+	defer rdr.Close()
+
+	uid, err := rdr.UID()
+	if err != nil || !strings.EqualFold(uid, testUID) {
+		t.Fatalf("UID() = %s, %v", uid, err)
+	}
+
+	info, err := rdr.Identify()
+	if err != nil || info.Type != CardClassic1K || info.SAK != "08" {
+		t.Errorf("Identify = %+v, %v", info, err)
+	}
+
+	if err := rdr.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatalf("WriteTagCFS erro: %v", err)
+	}
+	derived, _ := creality.DeriveS1KeyFromUID(testUID)
+	if _, err := rdr.TryReadBlock(4, KeyTypeA, DefaultKey); err == nil {
+		t.Error("key padrão ainda autentica após a migração do trailer")
+	}
+	if _, err := rdr.VerifyTagCFS(testUID, testFields()); err != nil {
+		t.Errorf("VerifyTagCFS erro: %v", err)
+	}
+	blocks, err := rdr.ReadRange(4, 3, KeyTypeA, derived)
+	if err != nil {
+		t.Fatalf("ReadRange erro: %v", err)
+	}
+	if want := strings.ToUpper(encryptedTestBlocks(t)[0]); blocks[0] != want {
+		t.Errorf("bloco 4 = %s, esperado %s", blocks[0], want)
+	}
+}
+
+func TestPN532NoCard(t *testing.T) {
+	rdr, err := OpenPN532(startFakePN532(t, nil))
+	if err != nil {
+		t.Fatalf("OpenPN532 erro: %v", err)
+	}
+	defer rdr.Close()
+
+	if _, err := rdr.UID(); !errors.Is(err, ErrCardRemoved) {
+		t.Errorf("sem tag: esperado ErrCardRemoved, obtido %v", err)
+	}
+}
+
+func TestPN532NTAG(t *testing.T) {
+	tag, err := NewEmulatedNTAG(testNTAGUID, NTAG215)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdr, err := OpenPN532(startFakePN532Tag(t, tag))
+	if err != nil {
+		t.Fatalf("OpenPN532 erro: %v", err)
+	}
+	defer rdr.Close()
+
+	msg := ndef.Encode(ndef.MediaRecord("application/json", []byte(`{"protocol":"openspool"}`)))
+	if err := rdr.WriteNDEF(msg); err != nil {
+		t.Fatalf("WriteNDEF erro: %v", err)
+	}
+	got, err := rdr.ReadNDEF()
+	if err != nil || !bytes.Equal(got, msg) {
+		t.Errorf("ReadNDEF = % X, %v", got, err)
+	}
+}
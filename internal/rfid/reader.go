@@ -8,12 +8,15 @@ package rfid
 //   rdr.WriteBlock(4, keyTypeB, "FFFFFFFFFFFF", data32Hex)
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ebfe/scard"
+	"github.com/robertocorreajr/cfs_spool/internal/access"
 	"github.com/robertocorreajr/cfs_spool/internal/creality"
 )
 
@@ -22,35 +25,128 @@ const (
 	KeyTypeB = byte(0x61)
 )
 
-// Reader mantém conexão PC/SC aberta.
+// Reader mantém conexão aberta com o cartão através de um Transport.
 type Reader struct {
-	ctx  *scard.Context
-	card *scard.Card
+	t         Transport
+	forceLock bool
+	ctx       context.Context // nil = sem prazo (ver WithContext)
+	cards     *cardCache      // cartão já identificado na Session (nil = sem cache)
 }
 
-// Open conecta no 1º leitor encontrado (ACR122…).
+// NewReader cria um Reader sobre um Transport já conectado
+// (ex.: EmulatedCard nos testes).
+func NewReader(t Transport) *Reader {
+	return &Reader{t: t}
+}
+
+// SetForceLock permite gravar trailers que travam as condições de acesso
+// do setor para sempre. Desligado por padrão.
+func (r *Reader) SetForceLock(force bool) {
+	r.forceLock = force
+}
+
+// Open conecta no leitor padrão (ACR122 se houver, senão o 1º da lista).
 func Open() (*Reader, error) {
+	return OpenReader("")
+}
+
+// OpenReader conecta no leitor cujo nome corresponde a pattern (ver MatchReader).
+// Nomes "pn532:<porta>" abrem o PN532 via UART em vez do PC/SC e
+// "replay:<arquivo>" reproduz uma gravação de APDUs (ver Recorder).
+func OpenReader(pattern string) (*Reader, error) {
+	if port, ok := PN532Port(pattern); ok {
+		return OpenPN532(port)
+	}
+	if path, ok := ReplayFile(pattern); ok {
+		rp, err := LoadReplay(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNoReader, err)
+		}
+		return NewReader(rp), nil
+	}
 	ctx, err := scard.EstablishContext()
 	if err != nil {
-		return nil, err
+		return nil, wrapTransportErr(err)
 	}
 	readers, err := ctx.ListReaders()
 	if err != nil || len(readers) == 0 {
-		return nil, errors.New("nenhum leitor PC/SC")
+		ctx.Release()
+		return nil, ErrNoReader
 	}
-	card, err := ctx.Connect(readers[0], scard.ShareShared, scard.ProtocolAny)
+	name, err := MatchReader(readers, pattern)
 	if err != nil {
+		ctx.Release()
 		return nil, err
 	}
-	return &Reader{ctx: ctx, card: card}, nil
+	card, err := ctx.Connect(name, scard.ShareShared, scard.ProtocolAny)
+	if err != nil {
+		ctx.Release()
+		return nil, wrapTransportErr(err)
+	}
+	return NewReader(&pcscTransport{ctx: ctx, card: card}), nil
 }
 
-func (r *Reader) Close() {
-	if r.card != nil {
-		r.card.Disconnect(scard.LeaveCard)
+// ListReaders devolve os nomes dos leitores PC/SC conectados seguidos das
+// portas seriais USB candidatas a PN532 ("pn532:<porta>"). Sem pcscd as
+// portas seriais continuam sendo listadas.
+func ListReaders() ([]string, error) {
+	readers, err := listPCSCReaders()
+	ports, _ := ListPN532Ports()
+	if err != nil && len(ports) == 0 {
+		return nil, err
 	}
-	if r.ctx != nil {
-		r.ctx.Release()
+	return append(readers, ports...), nil
+}
+
+func listPCSCReaders() ([]string, error) {
+	ctx, err := scard.EstablishContext()
+	if err != nil {
+		return []string{}, wrapTransportErr(err)
+	}
+	defer ctx.Release()
+	readers, err := ctx.ListReaders()
+	if err != nil {
+		// pcsc-lite devolve SCARD_E_NO_READERS_AVAILABLE com lista vazia
+		if errors.Is(err, scard.ErrNoReadersAvailable) {
+			return []string{}, nil
+		}
+		return []string{}, wrapTransportErr(err)
+	}
+	return readers, nil
+}
+
+// MatchReader escolhe um leitor da lista: nome exato, senão o primeiro que
+// contém pattern (sem diferenciar maiúsculas). Com pattern vazio prefere
+// leitores ACR122 e cai no 1º da lista — evita pegar o slot de smart card
+// do notebook ou o leitor do Windows Hello.
+func MatchReader(readers []string, pattern string) (string, error) {
+	if len(readers) == 0 {
+		return "", ErrNoReader
+	}
+	if pattern == "" {
+		for _, name := range readers {
+			if strings.Contains(strings.ToUpper(name), "ACR122") {
+				return name, nil
+			}
+		}
+		return readers[0], nil
+	}
+	for _, name := range readers {
+		if name == pattern {
+			return name, nil
+		}
+	}
+	for _, name := range readers {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(pattern)) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: leitor %q não encontrado", ErrNoReader, pattern)
+}
+
+func (r *Reader) Close() {
+	if r.t != nil {
+		r.t.Close()
 	}
 }
 
@@ -61,30 +157,83 @@ func (r *Reader) UID() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-		return "", errors.New("falha UID")
+	if err := checkSW(resp, "uid", -1); err != nil {
+		return "", err
 	}
 	return hex.EncodeToString(resp[:len(resp)-2]), nil
 }
 
-// Authenticate bloco com key (12 hex).
+// Authenticate bloco com key (12 hex): Load Key no slot 0 + General Authenticate.
 func (r *Reader) auth(block byte, keyType byte, keyHex string) error {
 	key, _ := hex.DecodeString(keyHex)
 	if len(key) != 6 {
 		return errors.New("key deve ter 12 hex")
 	}
-	cmd := append([]byte{0xFF, 0x86, 0, 0, 5,
-		1,       // version
-		block,   // bloco
+	if err := r.loadKey(key); err != nil {
+		return err
+	}
+	return r.authSlot(block, keyType)
+}
+
+// loadKey carrega a key (6 bytes) no slot volátil 0 do leitor.
+func (r *Reader) loadKey(key []byte) error {
+	resp, err := r.transmit(append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, key...))
+	if err != nil {
+		return err
+	}
+	return checkSW(resp, "load key", -1)
+}
+
+// authSlot autentica o bloco com a key já carregada no slot 0.
+func (r *Reader) authSlot(block byte, keyType byte) error {
+	cmd := []byte{0xFF, 0x86, 0x00, 0x00, 0x05,
+		0x01,    // version
+		0x00,    // bloco MSB
+		block,   // bloco LSB
 		keyType, // 0x60 A / 0x61 B
-		0},      // key slot
-		key...) // 6 bytes
+		0x00}    // key slot
 	resp, err := r.transmit(cmd)
 	if err != nil {
 		return err
 	}
-	if len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-		return errors.New("authenticate falhou")
+	return checkSW(resp, "auth", int(block))
+}
+
+// readBlock lê 16 bytes do bloco (exige autenticação prévia no setor).
+func (r *Reader) readBlock(block byte) (string, error) {
+	resp, err := r.transmit([]byte{0xFF, 0xB0, 0x00, block, 16})
+	if err != nil {
+		return "", err
+	}
+	if err := checkSW(resp, "read", int(block)); err != nil {
+		return "", err
+	}
+	if len(resp) != 18 {
+		return "", &StatusError{Op: "read", Block: int(block), SW1: 0x90, Err: ErrInvalidResponse}
+	}
+	return strings.ToUpper(hex.EncodeToString(resp[:16])), nil
+}
+
+// writeBlock grava 16 bytes no bloco (exige autenticação prévia no setor).
+func (r *Reader) writeBlock(block byte, data []byte) error {
+	if err := r.checkTrailer(block, data); err != nil {
+		return err
+	}
+	resp, err := r.transmit(append([]byte{0xFF, 0xD6, 0x00, block, 16}, data...))
+	if err != nil {
+		return err
+	}
+	return checkSW(resp, "write", int(block))
+}
+
+// checkTrailer valida os access bits antes de gravar um trailer; blocos
+// de dados passam direto.
+func (r *Reader) checkTrailer(block byte, data []byte) error {
+	if block%4 != 3 {
+		return nil
+	}
+	if _, err := access.CheckTrailer(data, r.forceLock); err != nil {
+		return fmt.Errorf("trailer do bloco %d recusado: %w", block, err)
 	}
 	return nil
 }
@@ -98,15 +247,7 @@ func (r *Reader) WriteBlock(block byte, keyType byte, keyHex, dataHex string) er
 	if len(data) != 16 {
 		return errors.New("bloco precisa de 32 hex")
 	}
-	cmd := append([]byte{0xFF, 0xD6, 0x00, block, 16}, data...)
-	resp, err := r.transmit(cmd)
-	if err != nil {
-		return err
-	}
-	if resp[len(resp)-2] != 0x90 {
-		return errors.New("write falhou")
-	}
-	return nil
+	return r.writeBlock(block, data)
 }
 
 // WriteBlockAlternative tenta escrever um bloco com métodos alternativos
@@ -118,41 +259,24 @@ func (r *Reader) WriteBlockAlternative(block byte, keyType byte, keyHex, dataHex
 	
 	// Método 1: Autenticação + escrita normal
 	if err := r.auth(block, keyType, keyHex); err == nil {
-		cmd := append([]byte{0xFF, 0xD6, 0x00, block, 16}, data...)
-		resp, err := r.transmit(cmd)
-		if err == nil && len(resp) >= 2 && resp[len(resp)-2] == 0x90 {
+		if err := r.writeBlock(block, data); err == nil {
 			return nil
 		}
 	}
 
-	// Método 2: Load key + authenticate + write
+	// Método 2: re-seleciona a tag e repete Load key + authenticate + write
 	keyBytes, err := hex.DecodeString(keyHex)
 	if err != nil || len(keyBytes) != 6 {
 		return errors.New("key deve ter 12 hex")
 	}
-	
-	// Load key no slot 0
-	cmd := append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, keyBytes...)
-	resp, err := r.transmit(cmd)
-	if err != nil || len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-		return fmt.Errorf("falha ao carregar key: %v", err)
-	}
-	
-	// Authenticate usando key slot 0
-	authCmd := []byte{0xFF, 0x86, 0x00, 0x00, 0x05, 0x01, 0x00, block, keyType, 0x00}
-	resp, err = r.transmit(authCmd)
-	if err != nil || len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-		return fmt.Errorf("falha na autenticação: %v", err)
+	r.UID()
+	if err := r.loadKey(keyBytes); err != nil {
+		return err
 	}
-	
-	// Write block
-	cmd = append([]byte{0xFF, 0xD6, 0x00, block, 16}, data...)
-	resp, err = r.transmit(cmd)
-	if err != nil || len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-		return fmt.Errorf("falha na escrita: %v", err)
+	if err := r.authSlot(block, keyType); err != nil {
+		return err
 	}
-	
-	return nil
+	return r.writeBlock(block, data)
 }
 
 // WriteRange escreve múltiplos blocos consecutivos
@@ -165,81 +289,25 @@ func (r *Reader) WriteRange(start byte, blocks []string, keyType byte, keyHex st
 		if err != nil {
 			err = r.WriteBlockAlternative(block, keyType, keyHex, blockData)
 			if err != nil {
-				return fmt.Errorf("falha ao escrever bloco %d: %v", block, err)
+				return fmt.Errorf("falha ao escrever bloco %d: %w", block, err)
 			}
 		}
 	}
 	return nil
 }
 
-// WriteTagCFS escreve dados CFS nos blocos 4, 5, 6 usando o padrão JavaScript
+// WriteTagCFS escreve dados CFS nos blocos 4, 5, 6 usando o padrão JavaScript.
+// Se a tag sair do leitor no meio, o erro é um *WriteInterruptedError com a
+// transação para ResumeWriteCFS/RollbackWriteCFS.
 func (r *Reader) WriteTagCFS(uid string, blocksToWrite []string, encrypted bool) error {
-	// Primeiro tentar determinar se é tag nova ou usada
-	// Testar autenticação com FFFFFFFFFFFF no setor 1
-	var key string
-	var isNewTag bool
-	
-	// Derivar a chave do UID primeiro
-	derivedKey := r.DeriveKeyFromUID(uid)
-	fmt.Printf("🔑 Chave derivada do UID %s: %s\n", uid, derivedKey)
-
-	// Primeiro tentar autenticar com a chave derivada
-	err := r.testAuthentication(4, derivedKey)
-	if err == nil {
-		key = derivedKey
-		isNewTag = false
-		fmt.Printf("🔄 Tag detectada como USADA (usando key derivada: %s)\n", key)
-	} else {
-		// Se falhar, tentar com a chave padrão
-		err = r.testAuthentication(4, "FFFFFFFFFFFF")
-		if err == nil {
-			key = "FFFFFFFFFFFF"
-			isNewTag = true
-			fmt.Println("🆕 Tag detectada como NOVA (usando FFFFFFFFFFFF)")
-		} else {
-			// Se ambas falharem, usar a chave derivada mesmo assim
-			// porque a tag pode estar usando uma chave antiga
-			key = derivedKey
-			isNewTag = false
-			fmt.Printf("⚠️ Autenticação falhou, usando key derivada: %s\n", key)
-		}
+	if len(blocksToWrite) > 3 {
+		blocksToWrite = blocksToWrite[:3]
 	}
-	
-	// Escrever blocos 4, 5, 6
-	blocks := []byte{4, 5, 6}
-	for i, blockNum := range blocks {
-		if i >= len(blocksToWrite) {
-			break
-		}
-		
-		err := r.WriteBlockDirectly(blockNum, key, blocksToWrite[i], uid)
-		if err != nil {
-			return fmt.Errorf("erro ao escrever bloco %d: %v", blockNum, err)
-		}
-		
-		fmt.Printf("✅ Bloco %d escrito com sucesso\n", blockNum)
-	}
-	
-	// Para tags novas, atualizar o trailer (bloco 7) com key derivada
-	// IMPORTANTE: A impressora Creality só reconhece tags com key derivada no trailer
-	if isNewTag {
-		derivedKey := r.DeriveKeyFromUID(uid)
-		fmt.Printf("🔑 Atualizando trailer para compatibilidade Creality (key: %s)\n", derivedKey)
-		
-		// Access bits FF0780: permite leitura e escrita com KeyA ou KeyB
-		// GPB 69: padrão Creality
-		trailer := derivedKey + "FF078069" + derivedKey // KeyA + Access + GPB + KeyB
-
-		fmt.Printf("🔑 Trailer que será gravado: %s\n", trailer)
-		
-		err := r.WriteBlockDirectly(7, key, trailer, uid) // Usar key atual (FFFFFFFFFFFF) para escrever
-		if err != nil {
-			return fmt.Errorf("erro ao escrever trailer: %v", err)
-		}
-		fmt.Println("✅ Trailer atualizado - tag compatível com impressora Creality")
+	tx, err := r.BeginWriteCFS(uid, blocksToWrite)
+	if err != nil {
+		return err
 	}
-	
-	return nil
+	return r.ResumeWriteCFS(tx)
 }
 
 // WriteBlockDirectly escreve um bloco usando Load Key + Authenticate + Write
@@ -269,6 +337,15 @@ func (r *Reader) WriteBlockDirectly(block byte, keyHex, dataHex string, uid ...s
 		keys = append(keys, "FFFFFFFFFFFF")
 	}
 
+	data, err := hex.DecodeString(dataHex)
+	if err != nil || len(data) != 16 {
+		return errors.New("dados devem ter 32 hex chars")
+	}
+	// Trailer inválido falharia com todas as keys: recusar antes de tentar
+	if err := r.checkTrailer(block, data); err != nil {
+		return err
+	}
+
 	var lastErr error
 	for _, key := range keys {
 		// 1. Load Key no slot 0
@@ -277,50 +354,33 @@ func (r *Reader) WriteBlockDirectly(block byte, keyHex, dataHex string, uid ...s
 			lastErr = errors.New("key deve ter 12 hex chars válidos")
 			continue
 		}
-		
-		cmd := append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, keyBytes...)
-		resp, err := r.transmit(cmd)
-		if err != nil || len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-			lastErr = fmt.Errorf("falha ao carregar key para bloco %d", block)
+		if err := r.loadKey(keyBytes); err != nil {
+			if errors.Is(err, ErrCardRemoved) {
+				return err
+			}
+			lastErr = err
 			continue
 		}
 		
 		// 2. Tentar authenticate + write com cada KeyType
 		// KeyB primeiro: access bits podem exigir KeyB para escrita
-		var written bool
 		for _, keyType := range []byte{KeyTypeB, KeyTypeA} {
-			authCmd := []byte{0xFF, 0x86, 0x00, 0x00, 0x05, 0x01, 0x00, block, keyType, 0x00}
-			resp, err = r.transmit(authCmd)
-			if err != nil || len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-				// Auth falhou — re-selecionar cartão e recarregar key antes de tentar próximo tipo
-				r.UID()
-				reloadCmd := append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, keyBytes...)
-				r.transmit(reloadCmd)
-				continue
+			err := r.authSlot(block, keyType)
+			if err == nil {
+				// Auth OK — tentar escrever
+				if err = r.writeBlock(block, data); err == nil {
+					return nil
+				}
 			}
-
-			// Auth OK — tentar escrever
-			data, err := hex.DecodeString(dataHex)
-			if err != nil || len(data) != 16 {
-				return errors.New("dados devem ter 32 hex chars")
+			if errors.Is(err, ErrCardRemoved) {
+				return err
 			}
+			lastErr = err
 
-			writeCmd := append([]byte{0xFF, 0xD6, 0x00, block, 16}, data...)
-			resp, err = r.transmit(writeCmd)
-			if err == nil && len(resp) >= 2 && resp[len(resp)-2] == 0x90 {
-				written = true
-				break
-			}
-			// Escrita falhou — re-selecionar e recarregar key antes de tentar próximo tipo
+			// Auth ou escrita falhou — re-selecionar cartão e recarregar key antes de tentar próximo tipo
 			r.UID()
-			reloadCmd := append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, keyBytes...)
-			r.transmit(reloadCmd)
-		}
-
-		if written {
-			return nil
+			r.loadKey(keyBytes)
 		}
-		lastErr = fmt.Errorf("falha na escrita do bloco %d", block)
 	}
 	
 	return lastErr // Retorna o último erro se todas as tentativas falharam
@@ -345,116 +405,90 @@ func (r *Reader) ReadRange(start byte, count int, keyType byte, keyHex string) (
 		if err := r.auth(blk, keyType, keyHex); err != nil {
 			return nil, err
 		}
-		resp, err := r.transmit([]byte{0xFF, 0xB0, 0x00, blk, 16})
-		if err != nil || resp[len(resp)-2] != 0x90 {
-			return nil, fmt.Errorf("read %d falhou", blk)
+		data, err := r.readBlock(blk)
+		if err != nil {
+			return nil, err
 		}
-		out = append(out, strings.ToUpper(hex.EncodeToString(resp[:16])))
+		out = append(out, data)
 	}
 	return out, nil
 }
 
 func (r *Reader) transmit(cmd []byte) ([]byte, error) {
-	return r.card.Transmit(cmd)
+	start := time.Now()
+	var resp []byte
+	var err error
+	if r.ctx != nil {
+		resp, err = r.transmitContext(cmd)
+	} else {
+		resp, err = r.t.Transmit(cmd)
+	}
+	if trace.Load() {
+		traceAPDU(cmd, resp, err, time.Since(start))
+	}
+	return resp, wrapTransportErr(err)
 }
 
 // ReadBlockDirect lê um bloco sem autenticação (útil para bloco 0)
 func (r *Reader) ReadBlockDirect(block byte) (string, error) {
-	resp, err := r.transmit([]byte{0xFF, 0xB0, 0x00, block, 16})
-	if err != nil {
-		return "", err
-	}
-	if len(resp) < 18 || resp[len(resp)-2] != 0x90 {
-		return "", fmt.Errorf("read direto bloco %d falhou", block)
-	}
-	return strings.ToUpper(hex.EncodeToString(resp[:16])), nil
+	return r.readBlock(block)
 }
 
 // TestBasicRead tenta diferentes métodos de leitura para diagnóstico
 func (r *Reader) TestBasicRead() error {
 	// Método 1: Get Data (obtém UID/ATQA/SAK)
-	resp, err := r.transmit([]byte{0xFF, 0xCA, 0x00, 0x00, 0x00})
+	uid, err := r.UID()
 	if err != nil {
-		return fmt.Errorf("método 1 falhou: %v", err)
-	}
-	if len(resp) >= 2 && resp[len(resp)-2] == 0x90 {
-		fmt.Printf("✓ Método 1 OK: %s\n", hex.EncodeToString(resp[:len(resp)-2]))
+		return fmt.Errorf("método 1 falhou: %w", err)
 	}
+	log().Info("método 1 OK", "uid", uid)
 
 	// Método 2: Load Key padrão
-	defaultKey := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
-	cmd := append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, defaultKey...)
-	resp, err = r.transmit(cmd)
-	if err == nil && len(resp) >= 2 && resp[len(resp)-2] == 0x90 {
-		fmt.Printf("✓ Load Key OK\n")
-		
-		// Método 3: Authenticate bloco 4 com key A
-		authCmd := []byte{0xFF, 0x86, 0x00, 0x00, 0x05, 0x01, 0x00, 0x04, 0x60, 0x00}
-		resp, err = r.transmit(authCmd)
-		if err == nil && len(resp) >= 2 && resp[len(resp)-2] == 0x90 {
-			fmt.Printf("✓ Auth bloco 4 com key A padrão OK\n")
-			return nil
-		}
+	if err := r.loadKey([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}); err != nil {
+		return fmt.Errorf("load key falhou: %w", err)
 	}
+	log().Info("load key OK")
 
-	return fmt.Errorf("todos os métodos falharam")
+	// Método 3: Authenticate bloco 4 com key A
+	if err := r.authSlot(4, KeyTypeA); err != nil {
+		return fmt.Errorf("auth bloco 4 com key A padrão falhou: %w", err)
+	}
+	log().Info("auth bloco 4 com key A padrão OK")
+	return nil
 }
 
 // TryReadBlock tenta ler um bloco específico com diferentes abordagens
 func (r *Reader) TryReadBlock(block byte, keyType byte, keyHex string) (string, error) {
 	// Método 1: Autenticação + leitura normal
-	if err := r.auth(block, keyType, keyHex); err == nil {
-		resp, err := r.transmit([]byte{0xFF, 0xB0, 0x00, block, 16})
-		if err == nil && len(resp) >= 18 && resp[len(resp)-2] == 0x90 {
-			return strings.ToUpper(hex.EncodeToString(resp[:16])), nil
+	err := r.auth(block, keyType, keyHex)
+	if err == nil {
+		var data string
+		if data, err = r.readBlock(block); err == nil {
+			return data, nil
 		}
 	}
-
-	// Método 2: Load key específica + authenticate + read
-	keyBytes, _ := hex.DecodeString(keyHex)
-	if len(keyBytes) == 6 {
-		// Load key no slot 0
-		cmd := append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, keyBytes...)
-		resp, err := r.transmit(cmd)
-		if err == nil && len(resp) >= 2 && resp[len(resp)-2] == 0x90 {
-			// Authenticate usando key slot 0
-			authCmd := []byte{0xFF, 0x86, 0x00, 0x00, 0x05, 0x01, 0x00, block, keyType, 0x00}
-			resp, err = r.transmit(authCmd)
-			if err == nil && len(resp) >= 2 && resp[len(resp)-2] == 0x90 {
-				// Read block
-				resp, err = r.transmit([]byte{0xFF, 0xB0, 0x00, block, 16})
-				if err == nil && len(resp) >= 18 && resp[len(resp)-2] == 0x90 {
-					return strings.ToUpper(hex.EncodeToString(resp[:16])), nil
-				}
-			}
-		}
+	if errors.Is(err, ErrCardRemoved) {
+		return "", err
 	}
 
-	return "", fmt.Errorf("não foi possível ler bloco %d", block)
+	// Método 2: re-seleciona a tag e repete Load key + authenticate + read
+	r.UID()
+	if err := r.auth(block, keyType, keyHex); err != nil {
+		return "", err
+	}
+	return r.readBlock(block)
 }
 
 // testAuthentication testa se uma key funciona para um bloco específico
 func (r *Reader) testAuthentication(block byte, keyHex string) error {
-	// 1. Load Key no slot 0
 	keyBytes, err := hex.DecodeString(keyHex)
 	if err != nil || len(keyBytes) != 6 {
 		return errors.New("key inválida")
 	}
-	
-	cmd := append([]byte{0xFF, 0x82, 0x00, 0x00, 0x06}, keyBytes...)
-	resp, err := r.transmit(cmd)
-	if err != nil || len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-		return errors.New("falha ao carregar key")
-	}
-	
-	// 2. Authenticate bloco
-	authCmd := []byte{0xFF, 0x86, 0x00, 0x00, 0x05, 0x01, 0x00, block, KeyTypeA, 0x00}
-	resp, err = r.transmit(authCmd)
-	if err != nil || len(resp) < 2 || resp[len(resp)-2] != 0x90 {
-		return errors.New("falha na autenticação")
+	if err := r.loadKey(keyBytes); err != nil {
+		return err
 	}
-	
-	return nil
+	return r.authSlot(block, KeyTypeA)
 }
 
 // DeriveKeyFromUID deriva uma key do UID usando o algoritmo Creality
@@ -476,7 +510,7 @@ func (r *Reader) ReadRangeAlternative(start byte, count int, keyType byte, keyHe
 		block := start + byte(i)
 		data, err := r.TryReadBlock(block, keyType, keyHex)
 		if err != nil {
-			return nil, fmt.Errorf("falha no bloco %d: %v", block, err)
+			return nil, fmt.Errorf("falha no bloco %d: %w", block, err)
 		}
 		blocks = append(blocks, data)
 	}
@@ -0,0 +1,194 @@
+package rfid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/access"
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+const testUID = "A1B2C3D4"
+
+func newTestReader(t *testing.T) (*Reader, *EmulatedCard) {
+	t.Helper()
+	card, err := NewEmulatedCard(testUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewReader(card), card
+}
+
+func encryptedTestBlocks(t *testing.T) []string {
+	t.Helper()
+	fields := creality.NewFields()
+	fields.Date = "26412"
+	fields.Supplier = "0276"
+	fields.Material = "04001"
+	fields.Length = "014A"
+	fields.Serial = "000001"
+	if err := fields.SetColor("77BB41"); err != nil {
+		t.Fatal(err)
+	}
+	payload, err := fields.ASCIIConcat48()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b4, b5, b6, err := creality.EncryptPayloadToBlocks(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []string{b4, b5, b6}
+}
+
+func TestReaderUID(t *testing.T) {
+	rdr, _ := newTestReader(t)
+	uid, err := rdr.UID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(uid, testUID) {
+		t.Errorf("UID() = %s, esperado %s", uid, testUID)
+	}
+}
+
+func TestReaderWriteBlockAndReadRange(t *testing.T) {
+	rdr, _ := newTestReader(t)
+	data := "00112233445566778899AABBCCDDEEFF"
+	if err := rdr.WriteBlock(5, KeyTypeA, "FFFFFFFFFFFF", data); err != nil {
+		t.Fatalf("WriteBlock erro: %v", err)
+	}
+	blocks, err := rdr.ReadRange(4, 3, KeyTypeA, "FFFFFFFFFFFF")
+	if err != nil {
+		t.Fatalf("ReadRange erro: %v", err)
+	}
+	if blocks[1] != data {
+		t.Errorf("bloco 5 = %s, esperado %s", blocks[1], data)
+	}
+}
+
+func TestReaderTryReadBlockWrongKey(t *testing.T) {
+	rdr, _ := newTestReader(t)
+	if _, err := rdr.TryReadBlock(4, KeyTypeA, "A0A1A2A3A4A5"); err == nil {
+		t.Error("TryReadBlock com key errada deveria retornar erro")
+	}
+}
+
+func TestReaderWriteTagCFSRoundTrip(t *testing.T) {
+	rdr, card := newTestReader(t)
+	blocks := encryptedTestBlocks(t)
+
+	if err := rdr.WriteTagCFS(testUID, blocks, false); err != nil {
+		t.Fatalf("WriteTagCFS erro: %v", err)
+	}
+
+	// Trailer migrado para a key derivada do UID
+	derived, err := creality.DeriveS1KeyFromUID(testUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trailer := strings.ToUpper(hex.EncodeToString(card.Block(7)))
+	if want := derived + "FF078069" + derived; trailer != want {
+		t.Errorf("trailer = %s, esperado %s", trailer, want)
+	}
+
+	// FFFFFFFFFFFF não abre mais o setor 1
+	if _, err := rdr.TryReadBlock(4, KeyTypeA, "FFFFFFFFFFFF"); err == nil {
+		t.Error("key padrão ainda autentica após migração do trailer")
+	}
+
+	read, err := rdr.ReadRangeAlternative(4, 3, KeyTypeA, derived)
+	if err != nil {
+		t.Fatalf("ReadRangeAlternative erro: %v", err)
+	}
+	decrypted, err := creality.DecryptBlocks(strings.Join(read, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := creality.ParseFieldsCompat(decrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields.Material != "04001" || fields.Color != "077BB41" || fields.Serial != "000001" {
+		t.Errorf("campos lidos inesperados: %s", fields)
+	}
+
+	// Reescrita de tag usada mantém a key derivada
+	if err := rdr.WriteTagCFS(testUID, blocks, false); err != nil {
+		t.Fatalf("WriteTagCFS (tag usada) erro: %v", err)
+	}
+	if got := strings.ToUpper(hex.EncodeToString(card.Block(7))); got != trailer {
+		t.Errorf("trailer alterado na reescrita: %s", got)
+	}
+}
+
+func TestMatchReader(t *testing.T) {
+	readers := []string{
+		"Broadcom Corp Contacted SmartCard 0",
+		"ACS ACR122U PICC Interface 00 00",
+		"ACS ACR122U PICC Interface 01 00",
+	}
+
+	testes := []struct {
+		pattern  string
+		esperado string
+		erro     bool
+	}{
+		{"", "ACS ACR122U PICC Interface 00 00", false},
+		{"ACS ACR122U PICC Interface 01 00", "ACS ACR122U PICC Interface 01 00", false},
+		{"broadcom", "Broadcom Corp Contacted SmartCard 0", false},
+		{"interface 01", "ACS ACR122U PICC Interface 01 00", false},
+		{"PN532", "", true},
+	}
+
+	for _, tt := range testes {
+		resultado, err := MatchReader(readers, tt.pattern)
+		if tt.erro && err == nil {
+			t.Errorf("MatchReader(%q) deveria retornar erro", tt.pattern)
+		}
+		if !tt.erro && err != nil {
+			t.Errorf("MatchReader(%q) retornou erro inesperado: %v", tt.pattern, err)
+		}
+		if resultado != tt.esperado {
+			t.Errorf("MatchReader(%q) = %q, esperado %q", tt.pattern, resultado, tt.esperado)
+		}
+	}
+
+	if r, _ := MatchReader([]string{"Windows Hello"}, ""); r != "Windows Hello" {
+		t.Errorf("sem ACR122 deveria cair no 1º leitor, obtido %q", r)
+	}
+	if _, err := MatchReader(nil, ""); err == nil {
+		t.Error("lista vazia deveria retornar erro")
+	}
+}
+
+func TestWriteTrailerLockGuard(t *testing.T) {
+	rdr, card := newTestReader(t)
+	original := card.Block(11)
+
+	// Access bits 100 no trailer: ninguém regrava as condições depois
+	locked := "FFFFFFFFFFFF" + "F78F0000" + "FFFFFFFFFFFF"
+	err := rdr.WriteBlockDirectly(11, "FFFFFFFFFFFF", locked)
+	if !errors.Is(err, access.ErrPermanentLock) {
+		t.Fatalf("esperado ErrPermanentLock, obtido %v", err)
+	}
+	if !bytes.Equal(card.Block(11), original) {
+		t.Errorf("trailer alterado apesar da recusa: %X", card.Block(11))
+	}
+
+	// Cópias invertidas erradas: recusado mesmo com force
+	rdr.SetForceLock(true)
+	if err := rdr.WriteBlockDirectly(11, "FFFFFFFFFFFF", "FFFFFFFFFFFFFFFFFF69FFFFFFFFFFFF"); !errors.Is(err, access.ErrMalformed) {
+		t.Errorf("esperado ErrMalformed, obtido %v", err)
+	}
+
+	if err := rdr.WriteBlockDirectly(11, "FFFFFFFFFFFF", locked); err != nil {
+		t.Fatalf("com force o trailer deveria ser gravado: %v", err)
+	}
+	if got := strings.ToUpper(hex.EncodeToString(card.Block(11)[6:10])); got != "F78F0000" {
+		t.Errorf("access bits gravados = %s, esperado F78F0000", got)
+	}
+}
@@ -0,0 +1,311 @@
+package rfid
+
+// Gravação e reprodução de sessões de APDUs.
+//
+//   rdr, _ := OpenReader("")
+//   rdr.Record(f)                      // grava cada APDU em f (JSON por linha)
+//
+//   rp, _ := LoadReplay("sessao.apdu.jsonl")
+//   rdr := NewReader(rp)               // ou OpenReader("replay:sessao.apdu.jsonl")
+//   ...
+//   rp.Mismatches()                    // comandos diferentes do gravado
+//
+// As keys (LOAD KEY e trailers) saem mascaradas como no log (MaskKey), a
+// menos que o trace esteja ligado; os dados dos blocos ficam em claro.
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ReplayPrefix prefixo dos nomes de leitor que reproduzem uma gravação
+// (ex.: "replay:/tmp/write.apdu.jsonl").
+const ReplayPrefix = "replay:"
+
+// ErrReplayMismatch o código enviou um comando diferente do gravado.
+var ErrReplayMismatch = errors.New("comando diferente do gravado")
+
+// ReplayFile extrai o caminho da gravação de um nome de leitor "replay:".
+func ReplayFile(name string) (string, bool) {
+	return strings.CutPrefix(name, ReplayPrefix)
+}
+
+// Exchange um APDU gravado (uma linha do arquivo).
+type Exchange struct {
+	Op   string `json:"op,omitempty"`   // "" = Transmit, "atr" = ATR()
+	Cmd  string `json:"cmd,omitempty"`  // hex
+	Resp string `json:"resp,omitempty"` // hex
+	Err  string `json:"err,omitempty"`
+	Kind string `json:"kind,omitempty"` // sentinela do erro: removed, no_reader, unsupported
+}
+
+const opATR = "atr"
+
+// errKinds sentinelas preservados na gravação para errors.Is na reprodução.
+var errKinds = []struct {
+	kind string
+	err  error
+}{
+	{"removed", ErrCardRemoved},
+	{"no_reader", ErrNoReader},
+	{"unsupported", ErrNotSupported},
+}
+
+func errKind(err error) string {
+	err = wrapTransportErr(err)
+	for _, k := range errKinds {
+		if errors.Is(err, k.err) {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// Recorder Transport que repassa os APDUs e grava cada troca em w.
+type Recorder struct {
+	t Transport
+	w io.Writer
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error // primeiro erro de escrita
+}
+
+// NewRecorder grava as trocas de t em w, uma Exchange JSON por linha.
+func NewRecorder(t Transport, w io.Writer) *Recorder {
+	return &Recorder{t: t, w: w, enc: json.NewEncoder(w)}
+}
+
+// Record passa a gravar os APDUs do leitor em w; Close fecha w se for io.Closer.
+func (r *Reader) Record(w io.Writer) {
+	r.t = NewRecorder(r.t, w)
+}
+
+func (r *Recorder) Transmit(cmd []byte) ([]byte, error) {
+	resp, err := r.t.Transmit(cmd)
+	r.write(Exchange{Cmd: maskCmd(cmd), Resp: maskResp(cmd, resp)}, err)
+	return resp, err
+}
+
+// maskCmd comando em hex com as keys mascaradas como no log (MaskKey):
+// a do LOAD KEY (FF 82) e as A/B da gravação de um trailer (FF D6).
+func maskCmd(cmd []byte) string {
+	h := encodeHex(cmd)
+	switch {
+	case len(cmd) == 11 && cmd[1] == 0x82:
+		return h[:10] + MaskKey(h[10:])
+	case len(cmd) == 21 && cmd[1] == 0xD6 && cmd[3]%4 == 3:
+		return h[:10] + maskTrailer(h[10:])
+	}
+	return h
+}
+
+// maskResp resposta em hex com as keys do trailer lido (FF B0) mascaradas.
+func maskResp(cmd, resp []byte) string {
+	h := encodeHex(resp)
+	if len(cmd) == 5 && cmd[1] == 0xB0 && cmd[3]%4 == 3 && len(resp) == 18 {
+		return maskTrailer(h[:32]) + h[32:]
+	}
+	return h
+}
+
+// maskTrailer trailer em hex (32 dígitos) com as keys A e B mascaradas.
+func maskTrailer(h string) string {
+	return MaskKey(h[:12]) + h[12:20] + MaskKey(h[20:32])
+}
+
+// ATR repassa o ATR do transporte gravado; sem suporte grava ErrNotSupported.
+func (r *Recorder) ATR() ([]byte, error) {
+	var atr []byte
+	err := error(ErrNotSupported)
+	if t, ok := r.t.(atrTransport); ok {
+		atr, err = t.ATR()
+	}
+	r.write(Exchange{Op: opATR, Resp: encodeHex(atr)}, err)
+	return atr, err
+}
+
+func (r *Recorder) write(e Exchange, err error) {
+	if err != nil {
+		e.Err, e.Kind = err.Error(), errKind(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.enc.Encode(e)
+	}
+}
+
+// Cancel repassa o cancelamento ao transporte gravado.
+func (r *Recorder) Cancel() error {
+	if c, ok := r.t.(canceler); ok {
+		return c.Cancel()
+	}
+	return nil
+}
+
+// Err primeiro erro ao escrever a gravação.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) Close() error {
+	err := r.t.Close()
+	if c, ok := r.w.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Replay Transport que devolve as respostas de uma gravação na ordem,
+// conferindo se cada comando é o mesmo que foi gravado.
+type Replay struct {
+	exchanges  []Exchange
+	pos        int
+	mismatches []*ReplayMismatch
+}
+
+// ReplayMismatch divergência entre o comando enviado e o gravado.
+type ReplayMismatch struct {
+	Index int    // posição na gravação
+	Want  string // gravado ("" = fim da gravação); "atr" para ATR()
+	Got   string // enviado
+}
+
+func (m *ReplayMismatch) Error() string {
+	if m.Want == "" {
+		return fmt.Sprintf("%v: #%d %s após o fim da gravação", ErrReplayMismatch, m.Index, m.Got)
+	}
+	return fmt.Sprintf("%v: #%d esperado %s, enviado %s", ErrReplayMismatch, m.Index, m.Want, m.Got)
+}
+
+func (m *ReplayMismatch) Unwrap() error {
+	return ErrReplayMismatch
+}
+
+// NewReplay lê uma gravação feita por Recorder.
+func NewReplay(rd io.Reader) (*Replay, error) {
+	var exchanges []Exchange
+	sc := bufio.NewScanner(rd)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var e Exchange
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("gravação linha %d: %w", line, err)
+		}
+		exchanges = append(exchanges, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return &Replay{exchanges: exchanges}, nil
+}
+
+// LoadReplay abre uma gravação do disco.
+func LoadReplay(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewReplay(f)
+}
+
+func (p *Replay) Transmit(cmd []byte) ([]byte, error) {
+	e, err := p.next("", encodeHex(cmd))
+	if err != nil {
+		return nil, err
+	}
+	return p.result(e)
+}
+
+func (p *Replay) ATR() ([]byte, error) {
+	e, err := p.next(opATR, opATR)
+	if err != nil {
+		return nil, err
+	}
+	return p.result(e)
+}
+
+// next consome a próxima troca se ela corresponder ao que foi enviado.
+// Em divergência a posição não avança e o erro fica em Mismatches.
+func (p *Replay) next(op, got string) (Exchange, error) {
+	m := &ReplayMismatch{Index: p.pos, Got: got}
+	if p.pos < len(p.exchanges) {
+		e := p.exchanges[p.pos]
+		want := e.Cmd
+		if e.Op != "" {
+			want = e.Op
+		}
+		if e.Op == op && matchCmd(want, got) {
+			p.pos++
+			return e, nil
+		}
+		m.Want = want
+	}
+	p.mismatches = append(p.mismatches, m)
+	return Exchange{}, m
+}
+
+// matchCmd compara o comando gravado com o enviado; "*" na gravação
+// (key mascarada) aceita qualquer dígito.
+func matchCmd(want, got string) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := 0; i < len(want); i++ {
+		if want[i] != '*' && !strings.EqualFold(want[i:i+1], got[i:i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// result reconstrói a resposta gravada, com o sentinela do erro se houver.
+func (p *Replay) result(e Exchange) ([]byte, error) {
+	// Key mascarada na gravação volta como zeros
+	resp, err := hex.DecodeString(strings.ReplaceAll(e.Resp, "*", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("gravação #%d: %w", p.pos-1, err)
+	}
+	if e.Err == "" {
+		return resp, nil
+	}
+	for _, k := range errKinds {
+		if k.kind == e.Kind {
+			return resp, fmt.Errorf("%w: %s", k.err, e.Err)
+		}
+	}
+	return resp, errors.New(e.Err)
+}
+
+// Mismatches comandos enviados que divergiram da gravação.
+func (p *Replay) Mismatches() []*ReplayMismatch {
+	return p.mismatches
+}
+
+// Remaining número de trocas gravadas ainda não consumidas.
+func (p *Replay) Remaining() int {
+	return len(p.exchanges) - p.pos
+}
+
+func (p *Replay) Close() error {
+	return nil
+}
+
+func encodeHex(b []byte) string {
+	return strings.ToUpper(hex.EncodeToString(b))
+}
@@ -0,0 +1,114 @@
+package rfid
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// recordWrite grava uma sessão WriteTagCFS + VerifyTagCFS no emulador.
+func recordWrite(t *testing.T) []byte {
+	t.Helper()
+	rdr, _ := newTestReader(t)
+	var buf bytes.Buffer
+	rdr.Record(&buf)
+	if err := rdr.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatalf("WriteTagCFS erro: %v", err)
+	}
+	if _, err := rdr.VerifyTagCFS(testUID, testFields()); err != nil {
+		t.Fatalf("VerifyTagCFS erro: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReplayWriteSession(t *testing.T) {
+	rec := recordWrite(t)
+
+	rp, err := NewReplay(bytes.NewReader(rec))
+	if err != nil {
+		t.Fatalf("NewReplay erro: %v", err)
+	}
+	rdr := NewReader(rp)
+	if err := rdr.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatalf("WriteTagCFS na reprodução: %v", err)
+	}
+	if _, err := rdr.VerifyTagCFS(testUID, testFields()); err != nil {
+		t.Fatalf("VerifyTagCFS na reprodução: %v", err)
+	}
+	if m := rp.Mismatches(); len(m) != 0 {
+		t.Errorf("divergências inesperadas: %v", m)
+	}
+	if n := rp.Remaining(); n != 0 {
+		t.Errorf("%d trocas gravadas não consumidas", n)
+	}
+}
+
+func TestRecorderMasksKeys(t *testing.T) {
+	rec := string(recordWrite(t))
+	key := NewReader(nil).DeriveKeyFromUID(testUID)
+	testes := []struct {
+		nome     string
+		entrada  string
+		esperado bool
+	}{
+		{"key derivada", key, false},
+		{"LOAD KEY mascarado", "FF82000006FF**********", true},
+		{"trailer mascarado", "FFD6000710" + key[:2] + "**********FF078069" + key[:2] + "**********", true},
+	}
+	for _, tt := range testes {
+		if got := strings.Contains(strings.ToUpper(rec), strings.ToUpper(tt.entrada)); got != tt.esperado {
+			t.Errorf("%s: presente = %v, esperado %v", tt.nome, got, tt.esperado)
+		}
+	}
+}
+
+func TestReplayFlagsDifferentCommand(t *testing.T) {
+	rec := recordWrite(t)
+
+	rp, err := NewReplay(bytes.NewReader(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Mesmo fluxo com outro conteúdo no bloco 4
+	blocks := encryptedTestBlocks(t)
+	blocks[0] = strings.Repeat("00", 16)
+	err = NewReader(rp).WriteTagCFS(testUID, blocks, false)
+	if !errors.Is(err, ErrReplayMismatch) {
+		t.Fatalf("esperado ErrReplayMismatch, obtido %v", err)
+	}
+
+	// WriteBlockDirectly ainda tenta alternativas; a primeira divergência é o bloco 4
+	m := rp.Mismatches()
+	if len(m) == 0 {
+		t.Fatal("nenhuma divergência registrada")
+	}
+	if !strings.HasPrefix(m[0].Want, "FFD6000410") || !strings.HasPrefix(m[0].Got, "FFD6000410"+strings.Repeat("00", 16)) {
+		t.Errorf("divergência = %+v", m[0])
+	}
+}
+
+func TestReplayPreservesSentinels(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado error
+	}{
+		{`{"cmd":"FFCA000000","err":"card removed","kind":"removed"}`, ErrCardRemoved},
+		{`{"cmd":"FFCA000000","err":"sem pcscd","kind":"no_reader"}`, ErrNoReader},
+	}
+	for _, tt := range testes {
+		rp, err := NewReplay(strings.NewReader(tt.entrada))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewReader(rp).UID(); !errors.Is(err, tt.esperado) {
+			t.Errorf("%s: esperado %v, obtido %v", tt.entrada, tt.esperado, err)
+		}
+	}
+
+	// Comando após o fim da gravação também é divergência
+	rp, _ := NewReplay(strings.NewReader(""))
+	if _, err := NewReader(rp).UID(); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("gravação vazia: esperado ErrReplayMismatch, obtido %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package rfid
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/access"
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// ResetTagCFS devolve uma tag provisionada por WriteTagCFS ao estado de
+// fábrica: zera os blocos 4–6 e regrava o trailer 7 com
+// FFFFFFFFFFFF/FF078069/FFFFFFFFFFFF. Tags que ainda abrem com a key
+// padrão só têm os dados zerados. No fim confere que a tag lê como virgem.
+func (r *Reader) ResetTagCFS(uid string) error {
+	defaultKey, _ := hex.DecodeString(DefaultKey)
+
+	key := r.DeriveKeyFromUID(uid)
+	if err := r.auth(4, KeyTypeA, key); err != nil {
+		if errors.Is(err, ErrCardRemoved) {
+			return err
+		}
+		r.UID()
+		if err := r.auth(4, KeyTypeA, DefaultKey); err != nil {
+			return fmt.Errorf("setor 1 não abre com a key derivada nem com a padrão: %w", err)
+		}
+		key = DefaultKey
+	}
+
+	zero := make([]byte, 16)
+	for block := byte(4); block <= 6; block++ {
+		if err := r.writeBlock(block, zero); err != nil {
+			return fmt.Errorf("erro ao zerar bloco %d: %w", block, err)
+		}
+	}
+
+	// Trailer por último: com ele a key derivada deixa de valer
+	if key != DefaultKey {
+		trailer := access.Trailer(defaultKey, access.Transport, defaultKey)
+		if err := r.writeBlock(7, trailer); err != nil {
+			return fmt.Errorf("erro ao regravar trailer: %w", err)
+		}
+	}
+
+	return r.verifyBlank()
+}
+
+// verifyBlank relê o setor 1 com a key padrão e confere que a tag é virgem.
+func (r *Reader) verifyBlank() error {
+	r.UID()
+	if err := r.auth(4, KeyTypeA, DefaultKey); err != nil {
+		return fmt.Errorf("%w: key padrão não autentica o setor 1: %v", ErrVerifyFailed, err)
+	}
+	var blocks []string
+	for block := byte(4); block <= 6; block++ {
+		data, err := r.readBlock(block)
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, data)
+	}
+
+	decrypted, err := creality.DecryptBlocks(strings.Join(blocks, ""))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerifyFailed, err)
+	}
+	fields, err := creality.ParseFieldsCompat(decrypted)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerifyFailed, err)
+	}
+	if !fields.IsBlankTag() {
+		return fmt.Errorf("%w: tag ainda contém dados CFS (%s)", ErrVerifyFailed, fields)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package rfid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestResetTagCFS(t *testing.T) {
+	rdr, card := newTestReader(t)
+	factory := card.Block(7)
+	if err := rdr.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatalf("WriteTagCFS erro: %v", err)
+	}
+
+	if err := rdr.ResetTagCFS(testUID); err != nil {
+		t.Fatalf("ResetTagCFS erro: %v", err)
+	}
+	for block := 4; block <= 6; block++ {
+		if got := card.Block(block); !bytes.Equal(got, make([]byte, 16)) {
+			t.Errorf("bloco %d = %X, esperado zerado", block, got)
+		}
+	}
+	if got := card.Block(7); !bytes.Equal(got, factory) {
+		t.Errorf("trailer = %X, esperado %X", got, factory)
+	}
+
+	// Tag já virgem: reset só zera os dados
+	if err := rdr.ResetTagCFS(testUID); err != nil {
+		t.Errorf("ResetTagCFS em tag virgem erro: %v", err)
+	}
+}
+
+func TestResetTagCFSUnknownKey(t *testing.T) {
+	rdr, card := newTestReader(t)
+	trailer := card.Block(7)
+	copy(trailer[0:6], []byte{0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5})
+	card.SetBlock(7, trailer)
+
+	if err := rdr.ResetTagCFS(testUID); !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("esperado ErrAuthFailed, obtido %v", err)
+	}
+}
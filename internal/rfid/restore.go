@@ -0,0 +1,154 @@
+package rfid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/dump"
+)
+
+// BlockIssue bloco que não foi gravado ou não confere após a restauração.
+type BlockIssue struct {
+	Block    int    `json:"block"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// RestoreReport resultado de Restore.
+type RestoreReport struct {
+	UID        string       `json:"uid"`
+	Written    []int        `json:"written"`
+	Skipped    []BlockIssue `json:"skipped"`
+	Mismatches []BlockIssue `json:"mismatches"`
+	Warnings   []string     `json:"warnings"`
+}
+
+// OK indica que tudo o que foi gravado confere na releitura.
+func (rep *RestoreReport) OK() bool {
+	return len(rep.Mismatches) == 0
+}
+
+// credential par key/tipo que autenticou um setor.
+type credential struct {
+	keyType byte
+	key     string
+}
+
+// Restore grava o dump na tag: primeiro todos os blocos de dados, depois os
+// trailers — assim uma falha no meio não deixa setores com keys novas e
+// dados antigos. Autentica com as keys que a tag tem hoje (keys do
+// dicionário + keys do dump) e, ao final, relê tudo comparando com o dump.
+// O bloco 0 (fabricante) e blocos desconhecidos no dump são ignorados.
+func (r *Reader) Restore(d *dump.Dump, keys []string) (*RestoreReport, error) {
+	uid, err := r.UID()
+	if err != nil {
+		return nil, err
+	}
+	rep := &RestoreReport{UID: strings.ToUpper(uid)}
+	if d.UID != "" && !strings.EqualFold(d.UID, uid) {
+		rep.Warnings = append(rep.Warnings, fmt.Sprintf(
+			"dump é da tag %s, gravando na tag %s — keys derivadas do UID não vão conferir", d.UID, rep.UID))
+	}
+
+	dict := restoreKeys(keys, d)
+	creds := make(map[int]credential)
+
+	// 1. Blocos de dados
+	for b := 1; b < dump.Blocks; b++ {
+		if dump.IsTrailer(b) {
+			continue
+		}
+		if !d.Read[b] {
+			rep.Skipped = append(rep.Skipped, BlockIssue{Block: b, Reason: "bloco desconhecido no dump"})
+			continue
+		}
+		if err := r.restoreBlock(rep, creds, dict, b, d.Blocks[b][:]); err != nil {
+			return rep, err
+		}
+	}
+
+	// 2. Trailers por último
+	for s := 0; s < dump.Sectors; s++ {
+		b := dump.TrailerBlock(s)
+		if !d.Read[b] || d.Keys[s].KeyA == "" || d.Keys[s].KeyB == "" {
+			rep.Skipped = append(rep.Skipped, BlockIssue{Block: b, Reason: "trailer sem KeyA/KeyB conhecidas no dump"})
+			continue
+		}
+		if err := r.checkTrailer(byte(b), d.Blocks[b][:]); err != nil {
+			rep.Skipped = append(rep.Skipped, BlockIssue{Block: b, Reason: err.Error()})
+			continue
+		}
+		if err := r.restoreBlock(rep, creds, dict, b, d.Blocks[b][:]); err != nil {
+			return rep, err
+		}
+	}
+
+	// 3. Verificação: relê a tag com as keys novas
+	after, err := r.Dump(dict)
+	if err != nil {
+		return rep, fmt.Errorf("erro na verificação: %w", err)
+	}
+	for _, b := range rep.Written {
+		expected := d.BlockHex(b)
+		if !after.Read[b] {
+			rep.Mismatches = append(rep.Mismatches, BlockIssue{Block: b, Reason: "bloco ilegível após restauração", Expected: expected})
+			continue
+		}
+		if actual := after.BlockHex(b); actual != expected {
+			rep.Mismatches = append(rep.Mismatches, BlockIssue{Block: b, Reason: "conteúdo diferente do dump", Expected: expected, Actual: actual})
+		}
+	}
+	return rep, nil
+}
+
+// restoreBlock grava um bloco autenticando com a credencial conhecida do
+// setor ou, na falta dela, com cada key do dicionário (KeyB e depois KeyA).
+func (r *Reader) restoreBlock(rep *RestoreReport, creds map[int]credential, dict []string, block int, data []byte) error {
+	sector := dump.SectorOf(block)
+	candidates := make([]credential, 0, 2*len(dict)+1)
+	if c, ok := creds[sector]; ok {
+		candidates = append(candidates, c)
+	}
+	for _, key := range dict {
+		candidates = append(candidates, credential{KeyTypeB, key}, credential{KeyTypeA, key})
+	}
+
+	var lastErr error
+	for _, c := range candidates {
+		err := r.auth(byte(block), c.keyType, c.key)
+		if err == nil {
+			if err = r.writeBlock(byte(block), data); err == nil {
+				creds[sector] = c
+				rep.Written = append(rep.Written, block)
+				return nil
+			}
+		}
+		if errors.Is(err, ErrCardRemoved) {
+			return fmt.Errorf("bloco %d: %w", block, err)
+		}
+		lastErr = err
+		r.UID()
+	}
+	rep.Skipped = append(rep.Skipped, BlockIssue{Block: block, Reason: fmt.Sprintf("nenhuma key gravou o bloco: %v", lastErr)})
+	return nil
+}
+
+// restoreKeys dicionário da restauração: keys informadas + keys do dump.
+func restoreKeys(keys []string, d *dump.Dump) []string {
+	out := append([]string(nil), keys...)
+	seen := map[string]bool{}
+	for _, k := range out {
+		seen[k] = true
+	}
+	for s := 0; s < dump.Sectors; s++ {
+		for _, k := range []string{d.Keys[s].KeyA, d.Keys[s].KeyB} {
+			if k != "" && !seen[k] {
+				seen[k] = true
+				out = append(out, k)
+			}
+		}
+	}
+	return out
+}
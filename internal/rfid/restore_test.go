@@ -0,0 +1,77 @@
+package rfid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReaderRestoreRollback(t *testing.T) {
+	rdr, card := newTestReader(t)
+	keys, _ := DumpKeys(testUID)
+
+	// Dump da tag virgem, escrita CFS e rollback para o dump
+	blank, err := rdr.Dump(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rdr.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := rdr.Restore(blank, keys)
+	if err != nil {
+		t.Fatalf("Restore erro: %v", err)
+	}
+	if !rep.OK() {
+		t.Fatalf("Restore com divergências: %+v", rep.Mismatches)
+	}
+	if len(rep.Warnings) != 0 {
+		t.Errorf("avisos inesperados: %v", rep.Warnings)
+	}
+
+	// Bloco 0 nunca é gravado; 47 blocos de dados + 16 trailers
+	if len(rep.Written) != 63 {
+		t.Errorf("blocos gravados = %d, esperado 63", len(rep.Written))
+	}
+	if got := card.Block(7); !bytes.Equal(got[:6], defaultKeyBytes) {
+		t.Errorf("KeyA do trailer 7 = %X, esperado FFFFFFFFFFFF", got[:6])
+	}
+	if _, err := rdr.TryReadBlock(4, KeyTypeA, DefaultKey); err != nil {
+		t.Errorf("key padrão deveria voltar a abrir o setor 1: %v", err)
+	}
+	for b := 4; b <= 6; b++ {
+		for _, v := range card.Block(b) {
+			if v != 0 {
+				t.Fatalf("bloco %d não foi zerado: %X", b, card.Block(b))
+			}
+		}
+	}
+}
+
+func TestReaderRestoreOtherUID(t *testing.T) {
+	src, _ := newTestReader(t)
+	if err := src.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := DumpKeys(testUID)
+	d, err := src.Dump(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card, _ := NewEmulatedCard("01020304")
+	dst := NewReader(card)
+	rep, err := dst.Restore(d, keys)
+	if err != nil {
+		t.Fatalf("Restore erro: %v", err)
+	}
+	if len(rep.Warnings) != 1 {
+		t.Errorf("esperado aviso de UID diferente, obtido %v", rep.Warnings)
+	}
+	if !rep.OK() {
+		t.Errorf("Restore com divergências: %+v", rep.Mismatches)
+	}
+	if got := card.Block(5); !bytes.Equal(got, d.Blocks[5][:]) {
+		t.Errorf("bloco 5 = %X, esperado %X", got, d.Blocks[5])
+	}
+}
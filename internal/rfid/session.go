@@ -0,0 +1,442 @@
+package rfid
+
+// Sessão única com o leitor.
+//
+//   dev, _ := OpenDevice("ACR122")
+//   s := NewSession(dev)
+//   defer s.Close()
+//
+//   for present := range s.Events() { ... }   // watcher: tag entrou/saiu
+//
+//   rdr, err := s.Open(ctx)                   // operação: espera a vez
+//   defer rdr.Close()                          // devolve o leitor à sessão
+//
+// A goroutine da Session é a única dona do leitor: acompanha a presença da
+// tag enquanto ninguém usa o leitor e atende os pedidos de Open em ordem de
+// chegada, um por vez. No PC/SC cada empréstimo roda dentro de
+// SCardBeginTransaction/SCardEndTransaction, então outros programas também
+// não intercalam APDUs no meio de uma gravação.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ebfe/scard"
+)
+
+const (
+	// sessionIdleWait espera máxima por mudança de presença entre uma
+	// conferência e outra da fila de pedidos.
+	sessionIdleWait = 250 * time.Millisecond
+	// pollInterval intervalo entre sondagens dos leitores sem notificação
+	// de presença (PN532 via UART).
+	pollInterval = 500 * time.Millisecond
+)
+
+// errSessionClosed a Session foi encerrada com Close.
+var errSessionClosed = fmt.Errorf("%w: sessão encerrada", ErrNoReader)
+
+// Device leitor aberto e mantido por uma Session.
+type Device interface {
+	// Wait espera a presença da tag mudar em relação a present, até
+	// timeout ou Interrupt, e devolve a presença atual. Erro significa
+	// leitor perdido.
+	Wait(present bool, timeout time.Duration) (bool, error)
+	// Interrupt faz um Wait em andamento (ou o próximo) retornar já.
+	Interrupt() error
+	// Connect conecta na tag presente.
+	Connect() (Transport, error)
+	Close() error
+}
+
+// transactor Transport com reserva exclusiva do cartão (PC/SC).
+type transactor interface {
+	Begin() error
+	End() error
+}
+
+// OpenDevice abre o leitor cujo nome corresponde a pattern, com as mesmas
+// regras de OpenReader ("pn532:<porta>", "replay:<arquivo>" ou PC/SC).
+func OpenDevice(pattern string) (Device, error) {
+	if port, ok := PN532Port(pattern); ok {
+		r, err := OpenPN532(port)
+		if err != nil {
+			return nil, err
+		}
+		probe := func() (bool, error) {
+			_, err := r.UID()
+			if errors.Is(err, ErrCardRemoved) {
+				return false, nil
+			}
+			return err == nil, err
+		}
+		connect := func() (Transport, error) { return keepOpen{r.t}, nil }
+		return newPollDevice(probe, connect, r.t.Close), nil
+	}
+	if path, ok := ReplayFile(pattern); ok {
+		if _, err := LoadReplay(path); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNoReader, err)
+		}
+		// A gravação começa com a tag no leitor; cada empréstimo a
+		// reproduz do início
+		probe := func() (bool, error) { return true, nil }
+		connect := func() (Transport, error) { return LoadReplay(path) }
+		return newPollDevice(probe, connect, nil), nil
+	}
+
+	ctx, err := scard.EstablishContext()
+	if err != nil {
+		return nil, wrapTransportErr(err)
+	}
+	readers, err := ctx.ListReaders()
+	if err != nil || len(readers) == 0 {
+		ctx.Release()
+		return nil, ErrNoReader
+	}
+	name, err := MatchReader(readers, pattern)
+	if err != nil {
+		ctx.Release()
+		return nil, err
+	}
+	return &pcscDevice{ctx: ctx, name: name, state: scard.StateUnaware}, nil
+}
+
+// pcscDevice leitor PC/SC: presença por SCardGetStatusChange.
+type pcscDevice struct {
+	ctx   *scard.Context
+	name  string
+	state scard.StateFlag // último estado visto
+}
+
+func (d *pcscDevice) Wait(present bool, timeout time.Duration) (bool, error) {
+	states := []scard.ReaderState{{Reader: d.name, CurrentState: d.state}}
+	err := d.ctx.GetStatusChange(states, timeout)
+	switch {
+	case errors.Is(err, scard.ErrTimeout), errors.Is(err, scard.ErrCancelled):
+		return present, nil
+	case errors.Is(err, scard.ErrUnknownReader), errors.Is(err, scard.ErrReaderUnavailable):
+		return false, fmt.Errorf("%w: %s desconectado", ErrNoReader, d.name)
+	case err != nil:
+		return false, wrapTransportErr(err)
+	}
+	ev := states[0].EventState
+	if ev&(scard.StateUnknown|scard.StateUnavailable) != 0 {
+		return false, fmt.Errorf("%w: %s desconectado", ErrNoReader, d.name)
+	}
+	d.state = ev &^ scard.StateChanged
+	return ev&scard.StatePresent != 0, nil
+}
+
+func (d *pcscDevice) Interrupt() error {
+	return d.ctx.Cancel()
+}
+
+func (d *pcscDevice) Connect() (Transport, error) {
+	card, err := d.ctx.Connect(d.name, scard.ShareShared, scard.ProtocolAny)
+	if err != nil {
+		return nil, wrapTransportErr(err)
+	}
+	return &pcscTransport{ctx: d.ctx, card: card, shared: true}, nil
+}
+
+func (d *pcscDevice) Close() error {
+	return d.ctx.Release()
+}
+
+// pollDevice leitor sem notificação de presença: probe a cada pollInterval.
+type pollDevice struct {
+	probe    func() (bool, error)
+	connect  func() (Transport, error)
+	close    func() error
+	interval time.Duration
+	wake     chan struct{}
+}
+
+func newPollDevice(probe func() (bool, error), connect func() (Transport, error), close func() error) *pollDevice {
+	return &pollDevice{probe: probe, connect: connect, close: close, interval: pollInterval, wake: make(chan struct{}, 1)}
+}
+
+func (d *pollDevice) Wait(present bool, timeout time.Duration) (bool, error) {
+	deadline := time.After(timeout)
+	for {
+		now, err := d.probe()
+		if err != nil || now != present {
+			return now, err
+		}
+		select {
+		case <-d.wake:
+			return present, nil
+		case <-deadline:
+			return present, nil
+		case <-time.After(d.interval):
+		}
+	}
+}
+
+// Interrupt fica registrado até o próximo Wait: não há corrida com a
+// entrada no Wait como no SCardCancel.
+func (d *pollDevice) Interrupt() error {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (d *pollDevice) Connect() (Transport, error) {
+	return d.connect()
+}
+
+func (d *pollDevice) Close() error {
+	if d.close == nil {
+		return nil
+	}
+	return d.close()
+}
+
+// keepOpen Transport do Device emprestado: Close não fecha a porta.
+type keepOpen struct {
+	Transport
+}
+
+func (keepOpen) Close() error { return nil }
+
+func (k keepOpen) ATR() ([]byte, error) {
+	if t, ok := k.Transport.(atrTransport); ok {
+		return t.ATR()
+	}
+	return nil, ErrNotSupported
+}
+
+func (k keepOpen) Cancel() error {
+	if c, ok := k.Transport.(canceler); ok {
+		return c.Cancel()
+	}
+	return nil
+}
+
+// Session dona única de um Device (ver comentário no topo do arquivo).
+type Session struct {
+	dev    Device
+	reqs   chan *leaseRequest
+	events chan bool
+	quit   chan struct{}
+	done   chan struct{}
+
+	closeOnce sync.Once
+	err       error // motivo do fim; vale depois de done fechar
+
+	cards cardCache // tipo do cartão presente (ver Reader.Identify)
+}
+
+type leaseRequest struct {
+	ctx   context.Context
+	grant chan *Reader // sem buffer: a entrega falha se o pedido desistiu
+	fail  chan error
+}
+
+// NewSession assume o Device e começa a acompanhar a presença da tag.
+func NewSession(dev Device) *Session {
+	s := &Session{
+		dev:    dev,
+		reqs:   make(chan *leaseRequest, 16),
+		events: make(chan bool, 16),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Events presença da tag a cada mudança (true = tag no leitor). O canal é
+// fechado quando a sessão termina; Err diz o motivo.
+func (s *Session) Events() <-chan bool {
+	return s.events
+}
+
+// Done é fechado quando a sessão termina.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err motivo do fim da sessão (nil enquanto ela roda).
+func (s *Session) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Open espera a vez na fila e devolve o Reader conectado à tag presente,
+// com as operações limitadas por ctx. Reader.Close devolve o leitor.
+func (s *Session) Open(ctx context.Context) (*Reader, error) {
+	req := &leaseRequest{ctx: ctx, grant: make(chan *Reader), fail: make(chan error, 1)}
+	select {
+	case s.reqs <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, s.err
+	}
+	if err := s.dev.Interrupt(); err != nil {
+		log().Debug("interrupção da espera de presença falhou", "err", err)
+	}
+
+	select {
+	case r := <-req.grant:
+		return r, nil
+	case err := <-req.fail:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, s.err
+	}
+}
+
+// Close encerra a sessão e fecha o Device. Espera o empréstimo em
+// andamento ser devolvido.
+func (s *Session) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.quit)
+		s.dev.Interrupt()
+	})
+	<-s.done
+	if errors.Is(s.err, errSessionClosed) {
+		return nil
+	}
+	return s.err
+}
+
+func (s *Session) run() {
+	defer close(s.done)
+	defer close(s.events)
+	defer s.dev.Close()
+
+	present := false
+	for {
+		select {
+		case <-s.quit:
+			s.err = errSessionClosed
+			return
+		case req := <-s.reqs:
+			s.serve(req)
+			continue
+		default:
+		}
+
+		now, err := s.dev.Wait(present, sessionIdleWait)
+		if err != nil {
+			log().Info("leitor perdido", "err", err)
+			s.err = err
+			return
+		}
+		if now != present {
+			present = now
+			s.cards.reset()
+			select {
+			case s.events <- now:
+			default:
+				log().Warn("evento de presença descartado: fila cheia", "present", now)
+			}
+		}
+	}
+}
+
+// serve empresta o leitor a um pedido e espera a devolução.
+func (s *Session) serve(req *leaseRequest) {
+	if req.ctx.Err() != nil {
+		return
+	}
+	t, err := s.dev.Connect()
+	if err != nil {
+		req.fail <- err
+		return
+	}
+	tr, hasTx := t.(transactor)
+	if hasTx {
+		if err := tr.Begin(); err != nil {
+			t.Close()
+			req.fail <- wrapTransportErr(err)
+			return
+		}
+	}
+
+	l := &lease{Transport: t, released: make(chan struct{})}
+	r := NewReader(l).WithContext(req.ctx)
+	r.cards = &s.cards
+	select {
+	case req.grant <- r:
+		<-l.released
+	case <-req.ctx.Done():
+	}
+
+	if l.abandoned.Load() {
+		// APDU órfão em voo: nada de End na mesma conexão; o cartão é
+		// reiniciado e o próximo empréstimo conecta de novo
+		if rs, ok := t.(resetter); ok {
+			if err := rs.Reset(); err != nil {
+				log().Debug("reinício da conexão falhou", "err", err)
+			}
+		}
+	} else if hasTx {
+		if err := tr.End(); err != nil {
+			log().Debug("fim da transação falhou", "err", err)
+		}
+	}
+	t.Close()
+}
+
+// resetter Transport que desconecta reiniciando o cartão (PC/SC).
+type resetter interface {
+	Reset() error
+}
+
+// lease Transport emprestado pela Session; Close devolve o leitor.
+type lease struct {
+	Transport
+	once      sync.Once
+	released  chan struct{}
+	abandoned atomic.Bool // APDU órfão em voo (ver Abandon)
+}
+
+func (l *lease) Transmit(cmd []byte) ([]byte, error) {
+	select {
+	case <-l.released:
+		return nil, fmt.Errorf("%w: leitor já devolvido à sessão", ErrNoReader)
+	default:
+	}
+	return l.Transport.Transmit(cmd)
+}
+
+func (l *lease) ATR() ([]byte, error) {
+	if t, ok := l.Transport.(atrTransport); ok {
+		return t.ATR()
+	}
+	return nil, ErrNotSupported
+}
+
+func (l *lease) Cancel() error {
+	if c, ok := l.Transport.(canceler); ok {
+		return c.Cancel()
+	}
+	return nil
+}
+
+func (l *lease) Close() error {
+	l.once.Do(func() { close(l.released) })
+	return nil
+}
+
+// Abandon devolve o leitor com um APDU órfão em voo; a Session reinicia a
+// conexão em vez de reaproveitá-la.
+func (l *lease) Abandon() error {
+	l.abandoned.Store(true)
+	return l.Close()
+}
@@ -0,0 +1,281 @@
+package rfid
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// txCard EmulatedCard que conta as transações abertas e fechadas.
+type txCard struct {
+	*EmulatedCard
+	begins, ends *atomic.Int32
+}
+
+func (c txCard) Begin() error { c.begins.Add(1); return nil }
+func (c txCard) End() error   { c.ends.Add(1); return nil }
+
+// testDevice Device sobre o emulador com a presença controlada pelo teste.
+type testDevice struct {
+	*pollDevice
+	card         *EmulatedCard
+	present      atomic.Bool
+	lost         atomic.Bool
+	begins, ends atomic.Int32
+}
+
+func newTestDevice(t *testing.T) *testDevice {
+	t.Helper()
+	card, err := NewEmulatedCard(testUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &testDevice{card: card}
+	probe := func() (bool, error) {
+		if d.lost.Load() {
+			return false, ErrNoReader
+		}
+		return d.present.Load(), nil
+	}
+	connect := func() (Transport, error) {
+		if !d.present.Load() {
+			return nil, ErrCardRemoved
+		}
+		return txCard{card, &d.begins, &d.ends}, nil
+	}
+	d.pollDevice = newPollDevice(probe, connect, nil)
+	d.interval = 5 * time.Millisecond
+	return d
+}
+
+func nextEvent(t *testing.T, s *Session) (bool, bool) {
+	t.Helper()
+	select {
+	case present, ok := <-s.Events():
+		return present, ok
+	case <-time.After(time.Second):
+		t.Fatal("nenhum evento de presença")
+		return false, false
+	}
+}
+
+func TestSessionEvents(t *testing.T) {
+	dev := newTestDevice(t)
+	s := NewSession(dev)
+	defer s.Close()
+
+	testes := []struct {
+		nome     string
+		entrada  bool
+		esperado bool
+	}{
+		{"tag colocada", true, true},
+		{"tag retirada", false, false},
+		{"tag de volta", true, true},
+	}
+	for _, tt := range testes {
+		t.Run(tt.nome, func(t *testing.T) {
+			dev.present.Store(tt.entrada)
+			present, ok := nextEvent(t, s)
+			if !ok || present != tt.esperado {
+				t.Errorf("evento %v (aberto %v), esperado %v", present, ok, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestSessionSerializesLeases(t *testing.T) {
+	dev := newTestDevice(t)
+	dev.present.Store(true)
+	s := NewSession(dev)
+	defer s.Close()
+
+	first, err := s.Open(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(chan *Reader)
+	go func() {
+		r, err := s.Open(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		got <- r
+	}()
+	select {
+	case <-got:
+		t.Fatal("segundo Open recebeu o leitor antes da devolução do primeiro")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if uid, err := first.UID(); err != nil || !strings.EqualFold(uid, testUID) {
+		t.Errorf("UID %q, %v", uid, err)
+	}
+	first.Close()
+	if _, err := first.UID(); !errors.Is(err, ErrNoReader) {
+		t.Errorf("Reader devolvido ainda transmite: %v", err)
+	}
+
+	var second *Reader
+	select {
+	case second = <-got:
+	case <-time.After(time.Second):
+		t.Fatal("segundo Open não recebeu o leitor")
+	}
+	if second == nil {
+		t.Fatal("segundo Open sem leitor")
+	}
+	second.Close()
+
+	// Cada empréstimo roda dentro de uma transação
+	waitFor(t, func() bool { return dev.ends.Load() == 2 })
+	if b := dev.begins.Load(); b != 2 {
+		t.Errorf("%d transações abertas, esperado 2", b)
+	}
+}
+
+func TestSessionOpenCanceledInQueue(t *testing.T) {
+	dev := newTestDevice(t)
+	dev.present.Store(true)
+	s := NewSession(dev)
+	defer s.Close()
+
+	holder, err := s.Open(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Open(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("esperado DeadlineExceeded, obtido %v", err)
+	}
+	holder.Close()
+
+	// O pedido que desistiu não prende o leitor
+	r, err := s.Open(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+}
+
+func TestSessionOpenWithoutTag(t *testing.T) {
+	dev := newTestDevice(t)
+	s := NewSession(dev)
+	defer s.Close()
+
+	if _, err := s.Open(context.Background()); !errors.Is(err, ErrCardRemoved) {
+		t.Errorf("esperado ErrCardRemoved, obtido %v", err)
+	}
+}
+
+func TestSessionReaderLost(t *testing.T) {
+	dev := newTestDevice(t)
+	s := NewSession(dev)
+
+	dev.lost.Store(true)
+	if _, ok := nextEvent(t, s); ok {
+		t.Fatal("Events deveria fechar com o leitor perdido")
+	}
+	if !errors.Is(s.Err(), ErrNoReader) {
+		t.Errorf("Err() = %v", s.Err())
+	}
+	if _, err := s.Open(context.Background()); !errors.Is(err, ErrNoReader) {
+		t.Errorf("Open após perda: %v", err)
+	}
+	if err := s.Close(); !errors.Is(err, ErrNoReader) {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	dev := newTestDevice(t)
+	s := NewSession(dev)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(context.Background()); !errors.Is(err, ErrNoReader) {
+		t.Errorf("Open após Close: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condição não atingida")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// selectCounter conta os InListPassiveTarget (FF 00 00 00 .. D4 4A).
+type selectCounter struct {
+	*EmulatedCard
+	n *atomic.Int32
+}
+
+func (c selectCounter) Transmit(cmd []byte) ([]byte, error) {
+	if len(cmd) > 6 && cmd[1] == 0x00 && cmd[5] == 0xD4 && cmd[6] == 0x4A {
+		c.n.Add(1)
+	}
+	return c.EmulatedCard.Transmit(cmd)
+}
+
+func TestSessionCachesCard(t *testing.T) {
+	dev := newTestDevice(t)
+	dev.present.Store(true)
+	var selects atomic.Int32
+	dev.connect = func() (Transport, error) {
+		if !dev.present.Load() {
+			return nil, ErrCardRemoved
+		}
+		return selectCounter{dev.card, &selects}, nil
+	}
+	s := NewSession(dev)
+	defer s.Close()
+	nextEvent(t, s)
+
+	identify := func() *CardInfo {
+		t.Helper()
+		r, err := s.Open(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		info, err := r.Identify()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+
+	testes := []struct {
+		nome     string
+		remover  bool
+		esperado int32
+	}{
+		{"primeira leitura", false, 1},
+		{"mesma tag", false, 1},
+		{"tag recolocada", true, 2},
+	}
+	for _, tt := range testes {
+		if tt.remover {
+			dev.present.Store(false)
+			nextEvent(t, s)
+			dev.present.Store(true)
+			nextEvent(t, s)
+		}
+		if info := identify(); info.Type != CardClassic1K || info.SAK != "08" {
+			t.Errorf("%s: descritor %+v", tt.nome, info)
+		}
+		if got := selects.Load(); got != tt.esperado {
+			t.Errorf("%s: %d seleções, esperado %d", tt.nome, got, tt.esperado)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package rfid
+
+import "github.com/ebfe/scard"
+
+// Transport é o canal de APDUs entre o Reader e o cartão.
+// Em produção é o PC/SC (pcscTransport); nos testes, o EmulatedCard.
+type Transport interface {
+	Transmit(cmd []byte) ([]byte, error)
+	Close() error
+}
+
+// pcscTransport envia APDUs por um cartão conectado via PC/SC.
+type pcscTransport struct {
+	ctx    *scard.Context
+	card   *scard.Card
+	shared bool // contexto da Session: Close não o libera
+}
+
+func (t *pcscTransport) Transmit(cmd []byte) ([]byte, error) {
+	return t.card.Transmit(cmd)
+}
+
+// ATR devolve o ATR montado pelo leitor para o cartão conectado.
+func (t *pcscTransport) ATR() ([]byte, error) {
+	status, err := t.card.Status()
+	if err != nil {
+		return nil, wrapTransportErr(err)
+	}
+	return status.Atr, nil
+}
+
+// Cancel interrompe a chamada PC/SC bloqueada no contexto (SCardCancel).
+func (t *pcscTransport) Cancel() error {
+	return t.ctx.Cancel()
+}
+
+// Reset desconecta reiniciando o cartão (SCardDisconnect com
+// SCARD_RESET_CARD), o que também encerra a transação aberta.
+func (t *pcscTransport) Reset() error {
+	if t.card == nil {
+		return nil
+	}
+	err := t.card.Disconnect(scard.ResetCard)
+	t.card = nil
+	return wrapTransportErr(err)
+}
+
+func (t *pcscTransport) Close() error {
+	if t.card != nil {
+		t.card.Disconnect(scard.LeaveCard)
+	}
+	if t.ctx != nil && !t.shared {
+		return t.ctx.Release()
+	}
+	return nil
+}
+
+// Begin reserva o cartão para esta conexão (SCardBeginTransaction).
+func (t *pcscTransport) Begin() error {
+	return t.card.BeginTransaction()
+}
+
+// End libera o cartão reservado em Begin (SCardEndTransaction).
+func (t *pcscTransport) End() error {
+	return t.card.EndTransaction(scard.LeaveCard)
+}
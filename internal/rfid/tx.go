@@ -0,0 +1,193 @@
+package rfid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/access"
+)
+
+// WriteTx gravação CFS acompanhada passo a passo (blocos 4–6 e, em tag
+// nova, o trailer 7). Se a tag sai do leitor no meio, WriteTagCFS devolve
+// um *WriteInterruptedError com a transação; com a mesma tag de volta,
+// ResumeWriteCFS conclui os passos pendentes e RollbackWriteCFS devolve os
+// blocos originais.
+type WriteTx struct {
+	UID      string   `json:"uid"`
+	Blocks   []string `json:"blocks"`             // dados dos blocos 4–6
+	Original []string `json:"original,omitempty"` // blocos 4–6 antes da gravação
+	NewTag   bool     `json:"newTag"`             // trailer com a key padrão: migrar no fim
+	Done     int      `json:"done"`               // passos concluídos
+}
+
+// Steps número total de passos: um por bloco e o trailer em tag nova.
+func (tx *WriteTx) Steps() int {
+	if tx.NewTag {
+		return len(tx.Blocks) + 1
+	}
+	return len(tx.Blocks)
+}
+
+// Complete indica se todos os passos foram gravados.
+func (tx *WriteTx) Complete() bool {
+	return tx.Done >= tx.Steps()
+}
+
+// WriteInterruptedError a tag saiu do leitor durante a gravação.
+type WriteInterruptedError struct {
+	Tx  *WriteTx
+	Err error
+}
+
+func (e *WriteInterruptedError) Error() string {
+	return fmt.Sprintf("gravação interrompida no passo %d de %d (recoloque a tag UID %s): %v",
+		e.Tx.Done+1, e.Tx.Steps(), e.Tx.UID, e.Err)
+}
+
+func (e *WriteInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// BeginWriteCFS descobre se o setor 1 abre com a key derivada (tag usada)
+// ou com a padrão (tag nova) e guarda os blocos 4–6 atuais para rollback.
+func (r *Reader) BeginWriteCFS(uid string, blocks []string) (*WriteTx, error) {
+	tx := &WriteTx{UID: strings.ToUpper(uid), Blocks: blocks}
+
+	derivedKey := r.DeriveKeyFromUID(uid)
+	log().Debug("key derivada do UID", "uid", uid, "key", MaskKey(derivedKey))
+
+	key := derivedKey
+	err := r.testAuthentication(4, derivedKey)
+	switch {
+	case err == nil:
+		log().Info("tag usada: setor 1 abre com a key derivada", "uid", uid)
+	case errors.Is(err, ErrCardRemoved):
+		return nil, err
+	default:
+		err = r.testAuthentication(4, DefaultKey)
+		switch {
+		case err == nil:
+			key, tx.NewTag = DefaultKey, true
+			log().Info("tag nova: setor 1 abre com a key padrão", "uid", uid)
+		case errors.Is(err, ErrCardRemoved):
+			return nil, err
+		default:
+			// A tag pode estar com uma key antiga: WriteBlockDirectly ainda
+			// tenta as alternativas, mas não há cópia para rollback
+			log().Warn("setor 1 não abre com a key derivada nem com a padrão; gravando com a derivada", "uid", uid)
+			return tx, nil
+		}
+	}
+
+	for block := byte(4); block <= 6; block++ {
+		data, err := r.TryReadBlock(block, KeyTypeA, key)
+		if err != nil {
+			if errors.Is(err, ErrCardRemoved) {
+				return nil, err
+			}
+			log().Warn("sem cópia do setor 1 para rollback", "block", block, "err", err)
+			tx.Original = nil
+			break
+		}
+		tx.Original = append(tx.Original, data)
+	}
+	return tx, nil
+}
+
+// ResumeWriteCFS executa os passos pendentes de tx. Pode ser chamado em
+// outra conexão depois que a tag voltou ao leitor; a tag precisa ter o
+// mesmo UID. O passo em andamento na remoção é regravado.
+func (r *Reader) ResumeWriteCFS(tx *WriteTx) error {
+	if err := r.checkTxUID(tx); err != nil {
+		return err
+	}
+	if tx.Done > 0 {
+		log().Info("retomando gravação", "uid", tx.UID, "step", tx.Done+1, "steps", tx.Steps())
+	}
+
+	// WriteBlockDirectly tenta key atual, derivada e padrão: serve tanto
+	// antes quanto depois da migração do trailer
+	key := DefaultKey
+	if !tx.NewTag {
+		key = r.DeriveKeyFromUID(tx.UID)
+	}
+	for tx.Done < len(tx.Blocks) {
+		block := byte(4 + tx.Done)
+		if err := r.WriteBlockDirectly(block, key, tx.Blocks[tx.Done], tx.UID); err != nil {
+			return txError(tx, fmt.Errorf("erro ao escrever bloco %d: %w", block, err))
+		}
+		log().Debug("bloco gravado", "block", block)
+		tx.Done++
+	}
+
+	// Para tags novas, atualizar o trailer (bloco 7) com key derivada
+	// IMPORTANTE: A impressora Creality só reconhece tags com key derivada no trailer
+	if !tx.Complete() {
+		derivedKey := r.DeriveKeyFromUID(tx.UID)
+		// Access bits de transporte FF0780: dados com KeyA ou KeyB, trailer pela KeyA
+		// GPB 69: padrão Creality
+		trailer := derivedKey + access.Transport.String() + derivedKey // KeyA + Access + GPB + KeyB
+		if err := r.WriteBlockDirectly(7, key, trailer, tx.UID); err != nil {
+			return txError(tx, fmt.Errorf("erro ao escrever trailer: %w", err))
+		}
+		tx.Done++
+		log().Info("trailer atualizado para a key derivada", "uid", tx.UID,
+			"trailer", MaskKey(derivedKey)+access.Transport.String()+MaskKey(derivedKey))
+	}
+	return nil
+}
+
+// RollbackWriteCFS regrava os blocos 4–6 originais e, se o trailer já foi
+// migrado, volta a key padrão. A tag precisa ter o mesmo UID.
+func (r *Reader) RollbackWriteCFS(tx *WriteTx) error {
+	if len(tx.Original) != 3 {
+		return fmt.Errorf("%w: sem cópia dos blocos originais", ErrNotSupported)
+	}
+	if err := r.checkTxUID(tx); err != nil {
+		return err
+	}
+
+	// Trailer possivelmente já migrado (inclusive se a remoção foi durante
+	// a gravação dele): WriteBlockDirectly também tenta a key padrão
+	migrated := tx.NewTag && tx.Done >= len(tx.Blocks)
+	key := DefaultKey
+	if !tx.NewTag || migrated {
+		key = r.DeriveKeyFromUID(tx.UID)
+	}
+	for i, data := range tx.Original {
+		block := byte(4 + i)
+		if err := r.WriteBlockDirectly(block, key, data, tx.UID); err != nil {
+			return txError(tx, fmt.Errorf("erro ao restaurar bloco %d: %w", block, err))
+		}
+	}
+	if migrated {
+		trailer := DefaultKey + access.Transport.String() + DefaultKey
+		if err := r.WriteBlockDirectly(7, key, trailer, tx.UID); err != nil {
+			return txError(tx, fmt.Errorf("erro ao restaurar trailer: %w", err))
+		}
+	}
+	tx.Done = 0
+	log().Info("gravação desfeita", "uid", tx.UID)
+	return nil
+}
+
+// checkTxUID confere que a tag no leitor é a da transação.
+func (r *Reader) checkTxUID(tx *WriteTx) error {
+	uid, err := r.UID()
+	if err != nil {
+		return txError(tx, err)
+	}
+	if !strings.EqualFold(uid, tx.UID) {
+		return fmt.Errorf("%w: UID %s, esperado %s", ErrWrongTag, strings.ToUpper(uid), tx.UID)
+	}
+	return nil
+}
+
+// txError devolve *WriteInterruptedError quando a tag saiu do leitor.
+func txError(tx *WriteTx, err error) error {
+	if errors.Is(err, ErrCardRemoved) {
+		return &WriteInterruptedError{Tx: tx, Err: err}
+	}
+	return err
+}
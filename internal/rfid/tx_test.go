@@ -0,0 +1,138 @@
+package rfid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ebfe/scard"
+)
+
+// removingTransport tira a tag do leitor depois de writes gravações (FF D6)
+// bem-sucedidas. Com lost, a gravação seguinte chega à tag mas a resposta
+// se perde, como numa remoção durante o APDU.
+type removingTransport struct {
+	*EmulatedCard
+	writes  int
+	lost    bool
+	removed bool
+}
+
+func (t *removingTransport) Transmit(cmd []byte) ([]byte, error) {
+	if t.removed {
+		return nil, scard.ErrRemovedCard
+	}
+	if len(cmd) < 2 || cmd[1] != 0xD6 {
+		return t.EmulatedCard.Transmit(cmd)
+	}
+	if t.writes == 0 && !t.lost {
+		t.removed = true
+		return nil, scard.ErrRemovedCard
+	}
+	resp, err := t.EmulatedCard.Transmit(cmd)
+	if bytes.HasSuffix(resp, swOK) {
+		if t.writes == 0 {
+			t.removed = true
+			return nil, scard.ErrRemovedCard
+		}
+		t.writes--
+	}
+	return resp, err
+}
+
+// interruptedWrite grava na tag nova até a remoção e devolve a transação.
+func interruptedWrite(t *testing.T, writes int, lost bool) (*EmulatedCard, *WriteTx) {
+	t.Helper()
+	card, err := NewEmulatedCard(testUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := &removingTransport{EmulatedCard: card, writes: writes, lost: lost}
+	err = NewReader(rt).WriteTagCFS(testUID, encryptedTestBlocks(t), false)
+
+	var ie *WriteInterruptedError
+	if !errors.As(err, &ie) || !errors.Is(err, ErrCardRemoved) {
+		t.Fatalf("esperado *WriteInterruptedError, obtido %v", err)
+	}
+	return card, ie.Tx
+}
+
+func TestWriteTxResume(t *testing.T) {
+	testes := []struct {
+		writes   int
+		lost     bool
+		esperado int // passos concluídos na remoção
+	}{
+		{0, false, 0},
+		{2, false, 2},
+		{2, true, 2}, // bloco 6 gravado mas não confirmado: regravado
+		{3, false, 3},
+		{3, true, 3}, // trailer gravado mas não confirmado
+	}
+	for _, tt := range testes {
+		card, tx := interruptedWrite(t, tt.writes, tt.lost)
+		if tx.Done != tt.esperado || !tx.NewTag || tx.Complete() {
+			t.Errorf("writes=%d lost=%v: tx = %+v", tt.writes, tt.lost, tx)
+			continue
+		}
+
+		// Tag recolocada: nova conexão com o mesmo cartão
+		rdr := NewReader(card)
+		if err := rdr.ResumeWriteCFS(tx); err != nil {
+			t.Errorf("writes=%d lost=%v: ResumeWriteCFS erro: %v", tt.writes, tt.lost, err)
+			continue
+		}
+		if !tx.Complete() {
+			t.Errorf("writes=%d: transação incompleta após retomar: %+v", tt.writes, tx)
+		}
+		if _, err := rdr.VerifyTagCFS(testUID, testFields()); err != nil {
+			t.Errorf("writes=%d lost=%v: VerifyTagCFS erro: %v", tt.writes, tt.lost, err)
+		}
+	}
+}
+
+func TestWriteTxRollback(t *testing.T) {
+	testes := []struct {
+		writes int
+		lost   bool
+	}{
+		{1, false},
+		{3, false},
+		{3, true}, // trailer já migrado: rollback volta a key padrão
+	}
+	for _, tt := range testes {
+		card, tx := interruptedWrite(t, tt.writes, tt.lost)
+		if len(tx.Original) != 3 {
+			t.Fatalf("sem cópia dos blocos originais: %+v", tx)
+		}
+
+		rdr := NewReader(card)
+		if err := rdr.RollbackWriteCFS(tx); err != nil {
+			t.Errorf("writes=%d lost=%v: RollbackWriteCFS erro: %v", tt.writes, tt.lost, err)
+			continue
+		}
+		for block := 4; block <= 6; block++ {
+			if !bytes.Equal(card.Block(block), make([]byte, 16)) {
+				t.Errorf("writes=%d lost=%v: bloco %d = % X, esperado zerado", tt.writes, tt.lost, block, card.Block(block))
+			}
+		}
+		if _, err := rdr.TryReadBlock(4, KeyTypeA, DefaultKey); err != nil {
+			t.Errorf("writes=%d lost=%v: key padrão não autentica após rollback: %v", tt.writes, tt.lost, err)
+		}
+	}
+}
+
+func TestWriteTxWrongTag(t *testing.T) {
+	_, tx := interruptedWrite(t, 1, false)
+
+	other, err := NewEmulatedCard("01020304")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewReader(other).ResumeWriteCFS(tx); !errors.Is(err, ErrWrongTag) {
+		t.Errorf("tag diferente: esperado ErrWrongTag, obtido %v", err)
+	}
+	if tx.Done != 1 {
+		t.Errorf("transação alterada pela tag errada: %+v", tx)
+	}
+}
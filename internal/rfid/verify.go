@@ -0,0 +1,110 @@
+package rfid
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// FieldMismatch campo CFS que não confere na releitura.
+type FieldMismatch struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// WriteVerification resultado da releitura de uma tag após WriteTagCFS.
+type WriteVerification struct {
+	UID        string          `json:"uid"`
+	Blocks     []string        `json:"blocks"`     // blocos 4–6 lidos (hex)
+	Trailer    string          `json:"trailer"`    // bloco 7 lido; KeyA aparece zerada
+	AccessBits string          `json:"accessBits"` // bytes 6–9 do trailer
+	Fields     creality.Fields `json:"fields"`     // campos decodificados da releitura
+	Mismatches []FieldMismatch `json:"mismatches"`
+}
+
+// OK indica que todos os campos lidos conferem com os pedidos.
+func (v *WriteVerification) OK() bool {
+	return len(v.Mismatches) == 0
+}
+
+// VerifyTagCFS relê o setor 1 com a key derivada do UID — a mesma que a
+// impressora usa — e compara os campos decodificados com want. Retorna
+// ErrVerifyFailed se algum campo não conferir; a verificação vem junto
+// para mostrar o que divergiu.
+func (r *Reader) VerifyTagCFS(uid string, want creality.Fields) (*WriteVerification, error) {
+	v := &WriteVerification{UID: uid}
+
+	// Autenticar uma vez: blocos 4–7 estão todos no setor 1
+	if err := r.auth(4, KeyTypeA, r.DeriveKeyFromUID(uid)); err != nil {
+		return v, fmt.Errorf("key derivada não autentica o setor 1: %w", err)
+	}
+	for block := byte(4); block <= 6; block++ {
+		data, err := r.readBlock(block)
+		if err != nil {
+			return v, err
+		}
+		v.Blocks = append(v.Blocks, data)
+	}
+	trailer, err := r.readBlock(7)
+	if err != nil {
+		return v, err
+	}
+	v.Trailer = trailer
+	v.AccessBits = trailer[12:20]
+
+	decrypted, err := creality.DecryptBlocks(strings.Join(v.Blocks, ""))
+	if err != nil {
+		return v, fmt.Errorf("%w: %v", ErrVerifyFailed, err)
+	}
+	got, err := creality.ParseFields(decrypted)
+	if err != nil {
+		return v, fmt.Errorf("%w: %v", ErrVerifyFailed, err)
+	}
+	v.Fields = got
+	v.Mismatches = compareFields(want, got)
+
+	if !v.OK() {
+		var diffs []string
+		for _, m := range v.Mismatches {
+			diffs = append(diffs, fmt.Sprintf("%s (esperado %s, lido %s)", m.Field, m.Expected, m.Actual))
+		}
+		return v, fmt.Errorf("%w: %s", ErrVerifyFailed, strings.Join(diffs, ", "))
+	}
+	return v, nil
+}
+
+// compareFields lista os campos divergentes; want passa pelas mesmas
+// correções de ASCIIConcat (Batch/Reserve fixos, 0 na cor).
+func compareFields(want, got creality.Fields) []FieldMismatch {
+	want.ValidateAndFix()
+	pairs := []struct{ name, want, got string }{
+		{"Date", want.Date, got.Date},
+		{"Supplier", want.Supplier, got.Supplier},
+		{"Batch", want.Batch, got.Batch},
+		{"Material", want.Material, got.Material},
+		{"Color", want.Color, got.Color},
+		{"Length", want.Length, got.Length},
+		{"Serial", want.Serial, got.Serial},
+		{"Reserve", want.Reserve, got.Reserve},
+	}
+	var out []FieldMismatch
+	for _, p := range pairs {
+		if !strings.EqualFold(p.want, p.got) {
+			out = append(out, FieldMismatch{Field: p.name, Expected: p.want, Actual: sanitizeField(p.got)})
+		}
+	}
+	return out
+}
+
+// sanitizeField mostra bytes não imprimíveis em hex para não poluir a UI.
+func sanitizeField(s string) string {
+	for _, c := range []byte(s) {
+		if c < 0x20 || c > 0x7E {
+			return "0x" + strings.ToUpper(hex.EncodeToString([]byte(s)))
+		}
+	}
+	return s
+}
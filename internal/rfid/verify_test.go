@@ -0,0 +1,62 @@
+package rfid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+func testFields() creality.Fields {
+	fields := creality.NewFields()
+	fields.Date = "26412"
+	fields.Supplier = "0276"
+	fields.Material = "04001"
+	fields.Length = "014A"
+	fields.Serial = "000001"
+	fields.Color = "077BB41"
+	return fields
+}
+
+func TestVerifyTagCFS(t *testing.T) {
+	rdr, card := newTestReader(t)
+	if err := rdr.WriteTagCFS(testUID, encryptedTestBlocks(t), false); err != nil {
+		t.Fatalf("WriteTagCFS erro: %v", err)
+	}
+
+	v, err := rdr.VerifyTagCFS(testUID, testFields())
+	if err != nil {
+		t.Fatalf("VerifyTagCFS erro: %v", err)
+	}
+	if !v.OK() || len(v.Blocks) != 3 {
+		t.Errorf("verificação inesperada: %+v", v)
+	}
+	if v.AccessBits != "FF078069" {
+		t.Errorf("AccessBits = %s, esperado FF078069", v.AccessBits)
+	}
+
+	// Campo pedido diferente do gravado
+	want := testFields()
+	want.Serial = "000002"
+	v, err = rdr.VerifyTagCFS(testUID, want)
+	if !errors.Is(err, ErrVerifyFailed) {
+		t.Fatalf("esperado ErrVerifyFailed, obtido %v", err)
+	}
+	if len(v.Mismatches) != 1 || v.Mismatches[0].Field != "Serial" || v.Mismatches[0].Actual != "000001" {
+		t.Errorf("divergências inesperadas: %+v", v.Mismatches)
+	}
+
+	// Bloco corrompido após a escrita
+	card.SetBlock(5, make([]byte, 16))
+	if _, err := rdr.VerifyTagCFS(testUID, testFields()); !errors.Is(err, ErrVerifyFailed) {
+		t.Errorf("bloco corrompido: esperado ErrVerifyFailed, obtido %v", err)
+	}
+}
+
+func TestVerifyTagCFSWithoutDerivedKey(t *testing.T) {
+	rdr, _ := newTestReader(t)
+	// Tag virgem: trailer ainda com FFFFFFFFFFFF
+	if _, err := rdr.VerifyTagCFS(testUID, testFields()); !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("esperado ErrAuthFailed, obtido %v", err)
+	}
+}
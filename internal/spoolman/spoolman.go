@@ -0,0 +1,230 @@
+package spoolman
+
+// Cliente da API REST do Spoolman (v1): fabricantes, filamentos e
+// bobinas. Os campos extras são texto JSON no Spoolman ("\"04A1...\"");
+// Text e Quote fazem a conversão.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout prazo de cada requisição sem outro configurado.
+const DefaultTimeout = 10 * time.Second
+
+var (
+	ErrNotFound = errors.New("não encontrado no Spoolman")
+	ErrBadURL   = errors.New("endereço do Spoolman inválido")
+)
+
+// Vendor fabricante.
+type Vendor struct {
+	ID    int               `json:"id,omitempty"`
+	Name  string            `json:"name"`
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// Filament filamento: material, cor e peso da bobina cheia. Na criação o
+// fabricante vai em VendorID; nas respostas ele vem em Vendor.
+type Filament struct {
+	ID           int               `json:"id,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Vendor       *Vendor           `json:"vendor,omitempty"`
+	VendorID     int               `json:"vendor_id,omitempty"`
+	Material     string            `json:"material,omitempty"`
+	ColorHex     string            `json:"color_hex,omitempty"` // 6 hex, sem #
+	Density      float64           `json:"density"`             // g/cm³
+	Diameter     float64           `json:"diameter"`            // mm
+	Weight       float64           `json:"weight,omitempty"`    // g de filamento
+	ExtruderTemp int               `json:"settings_extruder_temp,omitempty"`
+	BedTemp      int               `json:"settings_bed_temp,omitempty"`
+	Extra        map[string]string `json:"extra,omitempty"`
+}
+
+// Spool bobina. Na criação o filamento vai em FilamentID.
+type Spool struct {
+	ID              int               `json:"id,omitempty"`
+	Filament        *Filament         `json:"filament,omitempty"`
+	FilamentID      int               `json:"filament_id,omitempty"`
+	InitialWeight   float64           `json:"initial_weight,omitempty"` // g
+	RemainingWeight float64           `json:"remaining_weight,omitempty"`
+	Archived        bool              `json:"archived,omitempty"`
+	Extra           map[string]string `json:"extra,omitempty"`
+}
+
+// APIError resposta de erro do Spoolman.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("Spoolman respondeu %d", e.Status)
+	}
+	return fmt.Sprintf("Spoolman respondeu %d: %s", e.Status, e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.Status == http.StatusNotFound
+}
+
+// Client acesso à API de um servidor Spoolman.
+type Client struct {
+	base string
+	http *http.Client
+}
+
+// NewClient cliente do servidor em baseURL ("http://host:7912"); timeout
+// 0 usa DefaultTimeout.
+func NewClient(baseURL string, timeout time.Duration) (*Client, error) {
+	u, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrBadURL, baseURL)
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		base: strings.TrimRight(u.String(), "/") + "/api/v1",
+		http: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Vendors lista os fabricantes.
+func (c *Client) Vendors(ctx context.Context) ([]Vendor, error) {
+	var out []Vendor
+	return out, c.do(ctx, http.MethodGet, "/vendor", nil, &out)
+}
+
+// CreateVendor cria o fabricante.
+func (c *Client) CreateVendor(ctx context.Context, v Vendor) (*Vendor, error) {
+	var out Vendor
+	return &out, c.do(ctx, http.MethodPost, "/vendor", v, &out)
+}
+
+// Filaments lista os filamentos do fabricante (0 = todos).
+func (c *Client) Filaments(ctx context.Context, vendorID int) ([]Filament, error) {
+	path := "/filament"
+	if vendorID > 0 {
+		path += "?vendor.id=" + strconv.Itoa(vendorID)
+	}
+	var out []Filament
+	return out, c.do(ctx, http.MethodGet, path, nil, &out)
+}
+
+// CreateFilament cria o filamento.
+func (c *Client) CreateFilament(ctx context.Context, f Filament) (*Filament, error) {
+	var out Filament
+	return &out, c.do(ctx, http.MethodPost, "/filament", f, &out)
+}
+
+// Spools lista as bobinas não arquivadas.
+func (c *Client) Spools(ctx context.Context) ([]Spool, error) {
+	var out []Spool
+	return out, c.do(ctx, http.MethodGet, "/spool", nil, &out)
+}
+
+// Spool bobina pelo ID; ErrNotFound se não existe.
+func (c *Client) Spool(ctx context.Context, id int) (*Spool, error) {
+	var out Spool
+	return &out, c.do(ctx, http.MethodGet, "/spool/"+strconv.Itoa(id), nil, &out)
+}
+
+// CreateSpool cria a bobina.
+func (c *Client) CreateSpool(ctx context.Context, s Spool) (*Spool, error) {
+	var out Spool
+	return &out, c.do(ctx, http.MethodPost, "/spool", s, &out)
+}
+
+// UpdateSpool altera só os campos de patch (nomes JSON da API). Um
+// "extra" no patch substitui todos os campos extras da bobina.
+func (c *Client) UpdateSpool(ctx context.Context, id int, patch map[string]any) (*Spool, error) {
+	var out Spool
+	return &out, c.do(ctx, http.MethodPatch, "/spool/"+strconv.Itoa(id), patch, &out)
+}
+
+// EnsureField cria o campo extra key do tipo fieldType ("text",
+// "boolean"…) na entidade ("spool", "filament", "vendor") se ele ainda
+// não existe.
+func (c *Client) EnsureField(ctx context.Context, entity, key, name, fieldType string) error {
+	var fields []struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/field/"+entity, nil, &fields); err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if f.Key == key {
+			return nil
+		}
+	}
+	body := map[string]any{"name": name, "field_type": fieldType}
+	return c.do(ctx, http.MethodPost, "/field/"+entity+"/"+url.PathEscape(key), body, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		apiErr := &APIError{Status: resp.StatusCode}
+		var msg struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&msg) == nil {
+			apiErr.Message = msg.Message
+		}
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("resposta inválida do Spoolman em %s %s: %v", method, path, err)
+	}
+	return nil
+}
+
+// Quote valor de texto no formato dos campos extras.
+func Quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// Text valor de texto do campo extra key ("" se ausente ou não texto).
+func Text(extra map[string]string, key string) string {
+	var s string
+	if json.Unmarshal([]byte(extra[key]), &s) != nil {
+		return ""
+	}
+	return s
+}
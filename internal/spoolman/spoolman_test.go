@@ -0,0 +1,159 @@
+package spoolman_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/spoolman"
+	"github.com/robertocorreajr/cfs_spool/internal/spoolman/spoolmantest"
+)
+
+func newTestSyncer(t *testing.T) (*spoolman.Syncer, *spoolmantest.Fake) {
+	t.Helper()
+	fake := spoolmantest.NewFake()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	c, err := spoolman.NewClient(srv.URL+"/", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &spoolman.Syncer{Client: c}, fake
+}
+
+func TestNewClient(t *testing.T) {
+	testes := []struct {
+		entrada  string
+		esperado error
+	}{
+		{"http://spoolman.local:7912", nil},
+		{"https://10.0.0.5/spoolman/", nil},
+		{"spoolman.local:7912", spoolman.ErrBadURL},
+		{"", spoolman.ErrBadURL},
+	}
+	for _, tt := range testes {
+		if _, err := spoolman.NewClient(tt.entrada, 0); !errors.Is(err, tt.esperado) {
+			t.Errorf("NewClient(%q) = %v, esperado %v", tt.entrada, err, tt.esperado)
+		}
+	}
+}
+
+func TestFromFields(t *testing.T) {
+	f := creality.Fields{Supplier: "0276", Material: "04001", Color: "077bb41", Length: "014A"}
+	got := spoolman.FromFields("A1B2C3D4", f)
+	want := spoolman.Entry{UID: "A1B2C3D4", Vendor: "Creality", Name: "CR-PLA", Material: "PLA", ColorHex: "77BB41", Weight: 1000}
+	if got != want {
+		t.Errorf("FromFields = %+v, esperado %+v", got, want)
+	}
+}
+
+func TestSync(t *testing.T) {
+	s, fake := newTestSyncer(t)
+	ctx := context.Background()
+	e := spoolman.Entry{UID: "A1B2C3D4", Vendor: "Creality", Name: "Hyper PLA", Material: "PLA", ColorHex: "77BB41", Weight: 1000}
+
+	first, created, err := s.Sync(ctx, e)
+	if err != nil || !created {
+		t.Fatalf("primeira leitura: created %v, %v", created, err)
+	}
+	again, created, err := s.Sync(ctx, e)
+	if err != nil || created || again.ID != first.ID {
+		t.Errorf("segunda leitura: bobina %d, created %v, %v", again.ID, created, err)
+	}
+
+	// Tag regravada com outra cor e peso: mesma bobina, filamento novo
+	e.ColorHex, e.Weight = "FFFFFF", 500
+	changed, _, err := s.Sync(ctx, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testes := []struct {
+		campo    string
+		entrada  any
+		esperado any
+	}{
+		{"bobinas", len(fake.Spools()), 1},
+		{"ID", changed.ID, first.ID},
+		{"cor", changed.Filament.ColorHex, "FFFFFF"},
+		{"fabricante", changed.Filament.Vendor.Name, "Creality"},
+		{"peso", changed.InitialWeight, 500.0},
+		{"UID", spoolman.Text(changed.Extra, spoolman.DefaultField), "A1B2C3D4"},
+	}
+	for _, tt := range testes {
+		if tt.entrada != tt.esperado {
+			t.Errorf("%s = %v, esperado %v", tt.campo, tt.entrada, tt.esperado)
+		}
+	}
+}
+
+func TestLink(t *testing.T) {
+	s, _ := newTestSyncer(t)
+	ctx := context.Background()
+	old, _, err := s.Sync(ctx, spoolman.Entry{UID: "A1B2C3D4", Material: "PETG", ColorHex: "000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _, err := s.Sync(ctx, spoolman.Entry{UID: "04A1B2C3D4E5F6", Material: "PLA", ColorHex: "FFFFFF"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A tag A1B2C3D4 foi regravada com a bobina other
+	if _, err := s.Link(ctx, other.ID, "A1B2C3D4"); err != nil {
+		t.Fatal(err)
+	}
+	found, err := s.FindByUID(ctx, "a1b2c3d4")
+	if err != nil || found == nil || found.ID != other.ID {
+		t.Errorf("FindByUID = %+v, %v; esperado bobina %d", found, err, other.ID)
+	}
+	if got, _ := s.Client.Spool(ctx, old.ID); spoolman.Text(got.Extra, spoolman.DefaultField) != "" {
+		t.Errorf("bobina antiga ainda com o UID: %v", got.Extra)
+	}
+	if _, err := s.Link(ctx, 99, "A1B2C3D4"); !errors.Is(err, spoolman.ErrNotFound) {
+		t.Errorf("bobina inexistente: %v", err)
+	}
+
+	// A releitura da tag gravada não troca o filamento escolhido
+	got, _, err := s.Sync(ctx, spoolman.Entry{UID: "A1B2C3D4", Material: "PETG", ColorHex: "000000", Weight: 250})
+	if err != nil || got.ID != other.ID || got.Filament.ColorHex != "FFFFFF" || got.InitialWeight != other.InitialWeight {
+		t.Errorf("Sync da bobina ligada = %+v, %v", got, err)
+	}
+}
+
+func TestSyncConcurrent(t *testing.T) {
+	s, fake := newTestSyncer(t)
+	e := spoolman.Entry{UID: "A1B2C3D4", Material: "PLA", ColorHex: "77BB41"}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := s.Sync(context.Background(), e); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(fake.Spools()); n != 1 {
+		t.Errorf("%d bobinas com o mesmo UID, esperado 1", n)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+	c, err := spoolman.NewClient(srv.URL, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Vendors(context.Background()); err == nil {
+		t.Error("servidor lento deveria estourar o prazo")
+	}
+}
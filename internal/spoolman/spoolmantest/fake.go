@@ -0,0 +1,172 @@
+// Package spoolmantest servidor Spoolman falso para testes.
+package spoolmantest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/robertocorreajr/cfs_spool/internal/spoolman"
+)
+
+// Fake servidor Spoolman em memória, com o subconjunto da API usado pelo
+// spoolman.Client, para testes com httptest.NewServer.
+type Fake struct {
+	mu        sync.Mutex
+	mux       *http.ServeMux
+	vendors   []*spoolman.Vendor
+	filaments []*spoolman.Filament
+	spools    []*spoolman.Spool
+	fields    map[string][]map[string]any
+}
+
+// NewFake cria o servidor vazio.
+func NewFake() *Fake {
+	f := &Fake{mux: http.NewServeMux(), fields: map[string][]map[string]any{}}
+	f.mux.HandleFunc("GET /api/v1/vendor", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, f.vendors)
+	})
+	f.mux.HandleFunc("POST /api/v1/vendor", func(w http.ResponseWriter, r *http.Request) {
+		var v spoolman.Vendor
+		if !decode(w, r, &v) {
+			return
+		}
+		v.ID = len(f.vendors) + 1
+		f.vendors = append(f.vendors, &v)
+		reply(w, http.StatusOK, v)
+	})
+	f.mux.HandleFunc("GET /api/v1/filament", func(w http.ResponseWriter, r *http.Request) {
+		out := []*spoolman.Filament{}
+		for _, fil := range f.filaments {
+			if id := r.URL.Query().Get("vendor.id"); id == "" || (fil.Vendor != nil && strconv.Itoa(fil.Vendor.ID) == id) {
+				out = append(out, fil)
+			}
+		}
+		reply(w, http.StatusOK, out)
+	})
+	f.mux.HandleFunc("POST /api/v1/filament", func(w http.ResponseWriter, r *http.Request) {
+		var fil spoolman.Filament
+		if !decode(w, r, &fil) {
+			return
+		}
+		if fil.VendorID > 0 {
+			if fil.VendorID > len(f.vendors) {
+				reply(w, http.StatusNotFound, map[string]string{"message": "vendor not found"})
+				return
+			}
+			fil.Vendor, fil.VendorID = f.vendors[fil.VendorID-1], 0
+		}
+		fil.ID = len(f.filaments) + 1
+		f.filaments = append(f.filaments, &fil)
+		reply(w, http.StatusOK, fil)
+	})
+	f.mux.HandleFunc("GET /api/v1/spool", func(w http.ResponseWriter, r *http.Request) {
+		out := []*spoolman.Spool{}
+		for _, s := range f.spools {
+			if !s.Archived {
+				out = append(out, s)
+			}
+		}
+		reply(w, http.StatusOK, out)
+	})
+	f.mux.HandleFunc("POST /api/v1/spool", func(w http.ResponseWriter, r *http.Request) {
+		var s spoolman.Spool
+		if !decode(w, r, &s) || !f.setFilament(w, &s) {
+			return
+		}
+		s.ID = len(f.spools) + 1
+		f.spools = append(f.spools, &s)
+		reply(w, http.StatusOK, s)
+	})
+	f.mux.HandleFunc("GET /api/v1/spool/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if s := f.spool(w, r); s != nil {
+			reply(w, http.StatusOK, s)
+		}
+	})
+	f.mux.HandleFunc("PATCH /api/v1/spool/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s := f.spool(w, r)
+		if s == nil {
+			return
+		}
+		// Patch sobre uma cópia: campos ausentes ficam como estão
+		patched := *s
+		if !decode(w, r, &patched) || !f.setFilament(w, &patched) {
+			return
+		}
+		*s = patched
+		reply(w, http.StatusOK, s)
+	})
+	f.mux.HandleFunc("GET /api/v1/field/{entity}", func(w http.ResponseWriter, r *http.Request) {
+		out := f.fields[r.PathValue("entity")]
+		if out == nil {
+			out = []map[string]any{}
+		}
+		reply(w, http.StatusOK, out)
+	})
+	f.mux.HandleFunc("POST /api/v1/field/{entity}/{key}", func(w http.ResponseWriter, r *http.Request) {
+		var field map[string]any
+		if !decode(w, r, &field) {
+			return
+		}
+		field["key"] = r.PathValue("key")
+		entity := r.PathValue("entity")
+		f.fields[entity] = append(f.fields[entity], field)
+		reply(w, http.StatusOK, f.fields[entity])
+	})
+	return f
+}
+
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mux.ServeHTTP(w, r)
+}
+
+// Spools bobinas gravadas no servidor.
+func (f *Fake) Spools() []spoolman.Spool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]spoolman.Spool, len(f.spools))
+	for i, s := range f.spools {
+		out[i] = *s
+	}
+	return out
+}
+
+// spool bobina do {id} da rota; responde 404 se não existe.
+func (f *Fake) spool(w http.ResponseWriter, r *http.Request) *spoolman.Spool {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	if id < 1 || id > len(f.spools) {
+		reply(w, http.StatusNotFound, map[string]string{"message": "spool not found"})
+		return nil
+	}
+	return f.spools[id-1]
+}
+
+// setFilament troca filament_id pelo filamento; responde 404 se não existe.
+func (f *Fake) setFilament(w http.ResponseWriter, s *spoolman.Spool) bool {
+	if s.FilamentID == 0 {
+		return true
+	}
+	if s.FilamentID > len(f.filaments) {
+		reply(w, http.StatusNotFound, map[string]string{"message": "filament not found"})
+		return false
+	}
+	s.Filament, s.FilamentID = f.filaments[s.FilamentID-1], 0
+	return true
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		reply(w, http.StatusUnprocessableEntity, map[string]string{"message": err.Error()})
+		return false
+	}
+	return true
+}
+
+func reply(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,235 @@
+package spoolman
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// DefaultField campo extra da bobina com o UID da tag.
+const DefaultField = "tag_uid"
+
+// densities g/cm³ por material base; os demais ficam com a do PLA.
+var densities = map[string]float64{
+	"PLA": 1.24, "PETG": 1.27, "ABS": 1.04, "ASA": 1.07, "TPU": 1.21,
+	"PA": 1.14, "PC": 1.20, "PVA": 1.23, "HIPS": 1.04,
+}
+
+// Entry bobina lida de uma tag, no vocabulário do Spoolman.
+type Entry struct {
+	UID      string
+	Vendor   string // fabricante: "Creality"
+	Name     string // nome do filamento: "Hyper PLA"
+	Material string // material base: "PLA"
+	ColorHex string // 6 hex
+	Weight   int    // g (0 = não informado)
+	MaxTemp  int    // °C do bico (0 = não informado)
+}
+
+// FromFields Entry dos campos CFS: fabricante pelo fornecedor, nome do
+// catálogo Creality, material base, cor e peso pelo comprimento.
+func FromFields(uid string, f creality.Fields) Entry {
+	name := f.GetMaterialName()
+	e := Entry{
+		UID:      uid,
+		Vendor:   f.Brand(),
+		Name:     name,
+		Material: creality.BaseType(name),
+		Weight:   f.Grams(),
+	}
+	if len(f.Color) == 7 {
+		e.ColorHex = strings.ToUpper(f.Color[1:])
+	}
+	return e
+}
+
+// Syncer liga as tags às bobinas do Spoolman pelo UID num campo extra.
+// Sync e Link rodam um de cada vez: dois Sync do mesmo UID ao mesmo
+// tempo criariam duas bobinas.
+type Syncer struct {
+	Client *Client
+	Field  string // chave do campo extra ("" = DefaultField)
+
+	mu      sync.Mutex // cobre Sync e Link inteiros
+	fieldOK bool
+}
+
+func (s *Syncer) field() string {
+	if s.Field == "" {
+		return DefaultField
+	}
+	return s.Field
+}
+
+// linkedField campo extra booleano das bobinas ligadas por Link.
+func (s *Syncer) linkedField() string {
+	return s.field() + "_linked"
+}
+
+// ensureField cria os campos extras na primeira chamada que der certo;
+// chamada com s.mu travado.
+func (s *Syncer) ensureField(ctx context.Context) error {
+	if s.fieldOK {
+		return nil
+	}
+	if err := s.Client.EnsureField(ctx, "spool", s.field(), "UID da tag", "text"); err != nil {
+		return err
+	}
+	if err := s.Client.EnsureField(ctx, "spool", s.linkedField(), "Tag gravada desta bobina", "boolean"); err != nil {
+		return err
+	}
+	s.fieldOK = true
+	return nil
+}
+
+// FindByUID bobina com o UID no campo extra (nil se nenhuma).
+func (s *Syncer) FindByUID(ctx context.Context, uid string) (*Spool, error) {
+	spools, err := s.Client.Spools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range spools {
+		if got := Text(spools[i].Extra, s.field()); got != "" && strings.EqualFold(got, uid) {
+			return &spools[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Sync acha a bobina da tag (ou cria uma) e atualiza o filamento
+// (fabricante, material, cor) e o peso inicial com os dados da tag. Uma
+// bobina ligada por Link fica como está: a tag foi gravada a partir dela.
+// created indica bobina nova.
+func (s *Syncer) Sync(ctx context.Context, e Entry) (spool *Spool, created bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureField(ctx); err != nil {
+		return nil, false, err
+	}
+	spool, err = s.FindByUID(ctx, e.UID)
+	if err != nil {
+		return nil, false, err
+	}
+	if spool != nil && spool.Extra[s.linkedField()] == "true" {
+		return spool, false, nil
+	}
+	fil, err := s.filament(ctx, e)
+	if err != nil {
+		return nil, false, err
+	}
+	if spool == nil {
+		spool, err = s.Client.CreateSpool(ctx, Spool{
+			FilamentID:    fil.ID,
+			InitialWeight: float64(e.Weight),
+			Extra:         map[string]string{s.field(): Quote(e.UID)},
+		})
+		return spool, err == nil, err
+	}
+
+	patch := map[string]any{}
+	if spool.Filament == nil || spool.Filament.ID != fil.ID {
+		patch["filament_id"] = fil.ID
+	}
+	if e.Weight > 0 && spool.InitialWeight != float64(e.Weight) {
+		patch["initial_weight"] = e.Weight
+	}
+	if len(patch) == 0 {
+		return spool, false, nil
+	}
+	spool, err = s.Client.UpdateSpool(ctx, spool.ID, patch)
+	return spool, false, err
+}
+
+// Link grava o UID na bobina id e a marca como ligada, mantendo os
+// outros campos extras; outra bobina que tinha o UID perde o vínculo (a
+// tag foi regravada).
+func (s *Syncer) Link(ctx context.Context, id int, uid string) (*Spool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureField(ctx); err != nil {
+		return nil, err
+	}
+	// Bobina inexistente não pode desfazer o vínculo atual
+	spool, err := s.Client.Spool(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	old, err := s.FindByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if old != nil && old.ID != id {
+		if _, err := s.Client.UpdateSpool(ctx, old.ID, map[string]any{"extra": s.withUID(old.Extra, "")}); err != nil {
+			return nil, err
+		}
+	}
+	return s.Client.UpdateSpool(ctx, id, map[string]any{"extra": s.withUID(spool.Extra, uid)})
+}
+
+// withUID campos extras com o UID trocado; UID vazio desfaz o vínculo.
+func (s *Syncer) withUID(extra map[string]string, uid string) map[string]string {
+	out := make(map[string]string, len(extra)+2)
+	for k, v := range extra {
+		out[k] = v
+	}
+	out[s.field()] = Quote(uid)
+	out[s.linkedField()] = strconv.FormatBool(uid != "")
+	return out
+}
+
+// filament acha o filamento do fabricante com o mesmo nome, material e
+// cor, criando o que faltar.
+func (s *Syncer) filament(ctx context.Context, e Entry) (*Filament, error) {
+	vendor, err := s.vendor(ctx, e.Vendor)
+	if err != nil {
+		return nil, err
+	}
+	name := e.Name
+	if name == "" {
+		name = e.Material
+	}
+	list, err := s.Client.Filaments(ctx, vendor.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range list {
+		if strings.EqualFold(f.Name, name) && strings.EqualFold(f.Material, e.Material) &&
+			strings.EqualFold(f.ColorHex, e.ColorHex) {
+			return &list[i], nil
+		}
+	}
+	density, ok := densities[strings.ToUpper(e.Material)]
+	if !ok {
+		density = densities["PLA"]
+	}
+	return s.Client.CreateFilament(ctx, Filament{
+		Name:         name,
+		VendorID:     vendor.ID,
+		Material:     e.Material,
+		ColorHex:     e.ColorHex,
+		Density:      density,
+		Diameter:     creality.Diameter,
+		Weight:       float64(e.Weight),
+		ExtruderTemp: e.MaxTemp,
+	})
+}
+
+// vendor fabricante com o nome dado, criado se não existe.
+func (s *Syncer) vendor(ctx context.Context, name string) (*Vendor, error) {
+	if name == "" {
+		name = "Generic"
+	}
+	list, err := s.Client.Vendors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range list {
+		if strings.EqualFold(v.Name, name) {
+			return &list[i], nil
+		}
+	}
+	return s.Client.CreateVendor(ctx, Vendor{Name: name})
+}
@@ -0,0 +1,147 @@
+package tagformat
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/bambu"
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+// Nomes dos formatos (TagData.Format no app).
+const (
+	NameCFS       = "cfs"
+	NameBambu     = "bambu"
+	NameOpenSpool = "openspool"
+	NameOpenTag3D = "opentag3d"
+	NameTigerTag  = "tigertag"
+	NameACE       = "ace"
+)
+
+// defaultKey key de fábrica das tags Creality virgens.
+const defaultKey = "FFFFFFFFFFFF"
+
+// CFS tags Creality: blocos 4–6 cifrados em MIFARE Classic, lidos com a
+// key de fábrica (tag virgem) ou a derivada do UID (tag gravada).
+type CFS struct{}
+
+func (CFS) Name() string        { return NameCFS }
+func (CFS) Label() string       { return "Creality CFS" }
+func (CFS) Card() rfid.CardType { return rfid.CardClassic1K }
+func (CFS) Storage() Storage    { return StorageCreality }
+
+// cfsKeys keys tentadas em cada bloco, na ordem.
+func cfsKeys(uid string) []string {
+	keys := []string{defaultKey}
+	if k, err := creality.DeriveS1KeyFromUID(uid); err == nil {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (CFS) Detect(t Tag) (bool, error) {
+	_, err := t.Block(4, cfsKeys(t.Card().UID)...)
+	if errors.Is(err, rfid.ErrAuthFailed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (CFS) Decode(t Tag) (*Spool, error) {
+	keys := cfsKeys(t.Card().UID)
+	var payload strings.Builder
+	for block := 4; block <= 6; block++ {
+		b, err := t.Block(block, keys...)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&payload, "%X", b)
+	}
+	decrypted, err := creality.DecryptBlocks(payload.String())
+	if err != nil {
+		return nil, fmt.Errorf("descriptografia: %v", err)
+	}
+	fields, err := creality.ParseFieldsCompat(decrypted)
+	if err != nil {
+		return nil, fmt.Errorf("campos: %v", err)
+	}
+	return &Spool{Fields: fields, Blank: fields.IsBlankTag(), Native: &fields}, nil
+}
+
+// Encode conteúdo cifrado dos blocos 4–6, o mesmo que Decode lê. A
+// gravação em si (troca de keys e retomada) fica com a transação CFS do
+// app, não com Store.
+func (CFS) Encode(s Spool) ([]byte, error) {
+	payload, err := s.Fields.ASCIIConcat48()
+	if err != nil {
+		return nil, err
+	}
+	b4, b5, b6, err := creality.EncryptPayloadToBlocks(payload)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(b4 + b5 + b6)
+}
+
+// Bambu tags Bambu Lab: MIFARE Classic com keys por setor derivadas do
+// UID. Só leitura (as tags são assinadas).
+type Bambu struct{}
+
+func (Bambu) Name() string        { return NameBambu }
+func (Bambu) Label() string       { return "Bambu Lab" }
+func (Bambu) Card() rfid.CardType { return rfid.CardClassic1K }
+func (Bambu) Storage() Storage    { return StorageNone }
+
+func (Bambu) Detect(t Tag) (bool, error) {
+	keys, err := bambu.Keys(t.Card().UID)
+	if err != nil {
+		return false, nil
+	}
+	b := bambu.Blocks[0]
+	_, err = t.Block(b, keys[bambu.SectorOf(b)])
+	if errors.Is(err, rfid.ErrAuthFailed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (Bambu) Decode(t Tag) (*Spool, error) {
+	keys, err := bambu.Keys(t.Card().UID)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make(map[int][]byte, len(bambu.Blocks))
+	for _, b := range bambu.Blocks {
+		if blocks[b], err = t.Block(b, keys[bambu.SectorOf(b)]); err != nil {
+			return nil, err
+		}
+	}
+	tag, err := bambu.Parse(blocks)
+	if err != nil {
+		return nil, err
+	}
+	f := creality.NewFields()
+	f.Supplier = "0000"
+	f.Material = tag.CrealityCode()
+	f.Color = "0" + tag.ColorHex()
+	if tag.Weight > 0 {
+		f.Length = creality.LengthFromGrams(tag.Weight)
+	}
+	return &Spool{
+		Fields:  f,
+		Type:    tag.DetailedType,
+		Brand:   "Bambu Lab",
+		MinTemp: tag.MinTemp,
+		MaxTemp: tag.MaxTemp,
+		Weight:  tag.Weight,
+		Made:    tag.Produced,
+		Native:  tag,
+	}, nil
+}
+
+func (Bambu) Encode(Spool) ([]byte, error) {
+	return nil, fmt.Errorf("%w: tags Bambu Lab são assinadas", ErrReadOnly)
+}
@@ -0,0 +1,236 @@
+package tagformat
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/robertocorreajr/cfs_spool/internal/ace"
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+	"github.com/robertocorreajr/cfs_spool/internal/openspool"
+	"github.com/robertocorreajr/cfs_spool/internal/opentag3d"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+	"github.com/robertocorreajr/cfs_spool/internal/tigertag"
+)
+
+// ErrNoCatalog gravação TigerTag sem o catálogo de IDs.
+var ErrNoCatalog = errors.New("catálogo TigerTag ausente")
+
+// OpenSpool JSON OpenSpool em NDEF.
+type OpenSpool struct{}
+
+func (OpenSpool) Name() string        { return NameOpenSpool }
+func (OpenSpool) Label() string       { return "OpenSpool" }
+func (OpenSpool) Card() rfid.CardType { return rfid.CardUltralight }
+func (OpenSpool) Storage() Storage    { return StorageNDEF }
+
+func (OpenSpool) Detect(t Tag) (bool, error) {
+	msg, err := t.NDEF()
+	if err == nil {
+		_, err = openspool.Decode(msg)
+	}
+	return detected(err, openspool.ErrNotOpenSpool)
+}
+
+func (OpenSpool) Decode(t Tag) (*Spool, error) {
+	msg, err := t.NDEF()
+	if err != nil {
+		return nil, err
+	}
+	s, err := openspool.Decode(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Spool{
+		Fields:  s.Fields(),
+		Type:    s.Type,
+		Brand:   s.Brand,
+		MinTemp: int(s.MinTemp),
+		MaxTemp: int(s.MaxTemp),
+		Native:  s,
+	}, nil
+}
+
+func (OpenSpool) Encode(s Spool) ([]byte, error) {
+	return openspool.Encode(openspool.FromFields(s.Fields, s.MinTemp, s.MaxTemp))
+}
+
+// OpenTag3D layout binário OpenTag3D em NDEF. O formato tem uma única
+// temperatura de bico: na leitura ela vai para MinTemp e MaxTemp, na
+// gravação vem de MaxTemp.
+type OpenTag3D struct{}
+
+func (OpenTag3D) Name() string        { return NameOpenTag3D }
+func (OpenTag3D) Label() string       { return "OpenTag3D" }
+func (OpenTag3D) Card() rfid.CardType { return rfid.CardUltralight }
+func (OpenTag3D) Storage() Storage    { return StorageNDEF }
+
+func (OpenTag3D) Detect(t Tag) (bool, error) {
+	msg, err := t.NDEF()
+	if err == nil {
+		_, err = opentag3d.Decode(msg)
+	}
+	return detected(err, opentag3d.ErrNotOpenTag3D)
+}
+
+func (OpenTag3D) Decode(t Tag) (*Spool, error) {
+	msg, err := t.NDEF()
+	if err != nil {
+		return nil, err
+	}
+	tag, err := opentag3d.Decode(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Spool{
+		Fields:  tag.Fields(),
+		Type:    tag.Type(),
+		Brand:   tag.Manufacturer,
+		MinTemp: tag.PrintTemp,
+		MaxTemp: tag.PrintTemp,
+		Weight:  tag.Weight,
+		Native:  tag,
+	}, nil
+}
+
+func (OpenTag3D) Encode(s Spool) ([]byte, error) {
+	tag := opentag3d.FromFields(s.Fields)
+	if s.MaxTemp > 0 {
+		tag.PrintTemp = s.MaxTemp
+	}
+	return opentag3d.Encode(tag)
+}
+
+// TigerTag bloco binário TigerTag nas páginas da NTAG213. Os IDs de
+// material e marca só têm nome com o catálogo; MaterialCode, se dado,
+// escolhe o código CFS pelo nome (em vez do genérico do tipo).
+type TigerTag struct {
+	Catalog      func() (*tigertag.Catalog, error)
+	MaterialCode func(brand, name string) string
+}
+
+func (TigerTag) Name() string        { return NameTigerTag }
+func (TigerTag) Label() string       { return "TigerTag" }
+func (TigerTag) Card() rfid.CardType { return rfid.CardUltralight }
+func (TigerTag) Storage() Storage    { return StoragePages }
+
+func (TigerTag) Detect(t Tag) (bool, error) {
+	head, err := t.UserData(4)
+	if err != nil {
+		return false, err
+	}
+	return tigertag.IsTigerTag(head), nil
+}
+
+func (f TigerTag) Decode(t Tag) (*Spool, error) {
+	raw, err := t.UserData(tigertag.Size)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := tigertag.Unmarshal(raw)
+	if errors.Is(err, tigertag.ErrNotTigerTag) {
+		// Cabeçalho de TigerTag sem dados
+		return &Spool{Blank: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	catalog, _ := f.catalog()
+	name := catalog.MaterialName(tag.MaterialID)
+	brand := catalog.BrandName(tag.BrandID)
+	s := &Spool{
+		Fields:  catalog.Fields(*tag),
+		Type:    name,
+		Brand:   brand,
+		MinTemp: tag.MinTemp,
+		MaxTemp: tag.MaxTemp,
+		Weight:  catalog.Grams(*tag),
+		Made:    tag.Made,
+		Native:  tag,
+	}
+	if name == "" {
+		s.Type = fmt.Sprintf("TigerTag #%d", tag.MaterialID)
+	} else if f.MaterialCode != nil {
+		s.Fields.Material = f.MaterialCode(brand, name)
+	}
+	return s, nil
+}
+
+func (f TigerTag) Encode(s Spool) ([]byte, error) {
+	catalog, err := f.catalog()
+	if err != nil {
+		return nil, err
+	}
+	if catalog == nil {
+		return nil, ErrNoCatalog
+	}
+	tag, err := catalog.FromFields(s.Fields, s.MinTemp, s.MaxTemp)
+	if err != nil {
+		return nil, err
+	}
+	tag.Made = s.Made
+	return tag.Marshal()
+}
+
+func (f TigerTag) catalog() (*tigertag.Catalog, error) {
+	if f.Catalog == nil {
+		return nil, nil
+	}
+	return f.Catalog()
+}
+
+// ACE layout em claro das bobinas Anycubic (ACE Pro).
+type ACE struct{}
+
+func (ACE) Name() string        { return NameACE }
+func (ACE) Label() string       { return "Anycubic ACE" }
+func (ACE) Card() rfid.CardType { return rfid.CardUltralight }
+func (ACE) Storage() Storage    { return StoragePages }
+
+func (ACE) Detect(t Tag) (bool, error) {
+	head, err := t.UserData(4)
+	if err != nil {
+		return false, err
+	}
+	return ace.IsACE(head), nil
+}
+
+func (ACE) Decode(t Tag) (*Spool, error) {
+	raw, err := t.UserData(ace.Size)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ace.Unmarshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	fields := s.Fields()
+	weight := s.Weight
+	if weight == 0 {
+		weight = fields.Grams()
+	}
+	return &Spool{
+		Fields:  fields,
+		Type:    s.Type,
+		Brand:   s.Brand,
+		MinTemp: s.MinTemp,
+		MaxTemp: s.MaxTemp,
+		Weight:  weight,
+		Native:  s,
+	}, nil
+}
+
+func (ACE) Encode(s Spool) ([]byte, error) {
+	return ace.FromFields(s.Fields, s.MinTemp, s.MaxTemp).Marshal()
+}
+
+// detected resultado de Detect para formatos NDEF: sem mensagem ou com
+// a mensagem de outro formato não é erro.
+func detected(err, other error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, other), errors.Is(err, ndef.ErrNoMessage):
+		return false, nil
+	}
+	return false, err
+}
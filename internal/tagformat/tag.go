@@ -0,0 +1,150 @@
+package tagformat
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/robertocorreajr/cfs_spool/internal/ndef"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+// Tag dados brutos da tag presente, lidos sob demanda: cada formato pede
+// só o que precisa para se reconhecer, e o que já foi lido não volta ao
+// leitor.
+type Tag interface {
+	Card() *rfid.CardInfo
+	// UserData primeiros n bytes da NTAG a partir da página 4.
+	UserData(n int) ([]byte, error)
+	// NDEF primeira mensagem NDEF da NTAG.
+	NDEF() ([]byte, error)
+	// Block bloco MIFARE Classic lido com a primeira key (A) aceita, na
+	// ordem dada.
+	Block(block int, keys ...string) ([]byte, error)
+}
+
+// readerTag Tag lida pelo leitor.
+type readerTag struct {
+	r      *rfid.Reader
+	card   *rfid.CardInfo
+	data   []byte
+	msg    []byte
+	msgErr error
+	msgOK  bool
+	blocks map[int][]byte
+}
+
+// NewTag Tag do cartão presente no leitor.
+func NewTag(r *rfid.Reader, card *rfid.CardInfo) Tag {
+	return &readerTag{r: r, card: card, blocks: make(map[int][]byte)}
+}
+
+func (t *readerTag) Card() *rfid.CardInfo { return t.card }
+
+func (t *readerTag) UserData(n int) ([]byte, error) {
+	if len(t.data) < n {
+		data, err := t.r.ReadUserData(n)
+		if err != nil {
+			return nil, err
+		}
+		t.data = data
+	}
+	return t.data[:n], nil
+}
+
+func (t *readerTag) NDEF() ([]byte, error) {
+	if !t.msgOK {
+		t.msg, t.msgErr = t.r.ReadNDEF()
+		t.msgOK = true
+	}
+	return t.msg, t.msgErr
+}
+
+// Block tenta as keys em ordem; tag removida encerra as tentativas.
+func (t *readerTag) Block(block int, keys ...string) ([]byte, error) {
+	if b, ok := t.blocks[block]; ok {
+		return b, nil
+	}
+	err := error(rfid.ErrAuthFailed)
+	for _, key := range keys {
+		var data string
+		data, err = t.r.TryReadBlock(byte(block), rfid.KeyTypeA, key)
+		if err == nil {
+			b, err := hex.DecodeString(data)
+			if err != nil {
+				return nil, fmt.Errorf("bloco %d: %v", block, err)
+			}
+			t.blocks[block] = b
+			return b, nil
+		}
+		if errors.Is(err, rfid.ErrCardRemoved) {
+			break
+		}
+	}
+	return nil, fmt.Errorf("bloco %d: %w", block, err)
+}
+
+// storedTag Tag com os bytes de Encode, sem leitor.
+type storedTag struct {
+	card    *rfid.CardInfo
+	storage Storage
+	data    []byte
+}
+
+// Stored Tag com os bytes montados por Encode (ou relidos depois da
+// gravação), para decodificá-los sem o leitor.
+func Stored(card *rfid.CardInfo, storage Storage, data []byte) Tag {
+	return &storedTag{card: card, storage: storage, data: data}
+}
+
+func (t *storedTag) Card() *rfid.CardInfo { return t.card }
+
+func (t *storedTag) UserData(n int) ([]byte, error) {
+	if t.storage != StoragePages {
+		return nil, rfid.ErrNotSupported
+	}
+	b := make([]byte, n)
+	copy(b, t.data)
+	return b, nil
+}
+
+func (t *storedTag) NDEF() ([]byte, error) {
+	if t.storage != StorageNDEF {
+		return nil, ndef.ErrNoMessage
+	}
+	return t.data, nil
+}
+
+func (t *storedTag) Block(block int, keys ...string) ([]byte, error) {
+	if t.storage != StorageCreality || block < 4 || block > 6 {
+		return nil, fmt.Errorf("bloco %d: %w", block, rfid.ErrNotSupported)
+	}
+	off := (block - 4) * 16
+	if off+16 > len(t.data) {
+		return nil, fmt.Errorf("bloco %d: %w", block, rfid.ErrBlockNotFound)
+	}
+	return t.data[off : off+16], nil
+}
+
+// Store grava na NTAG os bytes de Encode. StorageCreality fica com a
+// transação CFS do app (troca de keys e retomada), não com Store.
+func Store(r *rfid.Reader, storage Storage, data []byte) error {
+	switch storage {
+	case StorageNDEF:
+		return r.WriteNDEF(data)
+	case StoragePages:
+		return r.WriteUserData(data)
+	}
+	return fmt.Errorf("%w: gravação direta do armazenamento %d", rfid.ErrNotSupported, storage)
+}
+
+// Load relê da NTAG o que Store gravou (n bytes em StoragePages).
+func Load(r *rfid.Reader, storage Storage, n int) ([]byte, error) {
+	switch storage {
+	case StorageNDEF:
+		return r.ReadNDEF()
+	case StoragePages:
+		return r.ReadUserData(n)
+	}
+	return nil, fmt.Errorf("%w: leitura direta do armazenamento %d", rfid.ErrNotSupported, storage)
+}
@@ -0,0 +1,190 @@
+package tagformat
+
+// Formatos de dados de bobina numa tag RFID. Cada formato reconhece os
+// próprios dados (pelo tipo de cartão e pelos bytes lidos), converte para
+// o modelo comum Spool e monta os bytes a gravar a partir dele. O
+// Registry guarda os formatos em ordem de prioridade: o primeiro que
+// reconhece a tag vence.
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+var (
+	ErrUnknownFormat = errors.New("nenhum formato conhecido na tag")
+	ErrReadOnly      = errors.New("formato só de leitura")
+)
+
+// Spool modelo comum entre os formatos: os campos CFS equivalentes mais
+// o que o formato traz além deles.
+type Spool struct {
+	Fields  creality.Fields // material, cor, comprimento e serial no vocabulário CFS
+	Type    string          // material como o formato o nomeia ("PLA Matte"); "" = nome Creality
+	Brand   string          // marca como o formato a nomeia; "" = fornecedor CFS
+	MinTemp int             // °C do bico (0 = não informado)
+	MaxTemp int
+	Weight  int       // g (0 = só o comprimento de Fields)
+	Made    time.Time // fabricação, nos formatos sem a data CFS
+	Blank   bool      // tag do formato ainda sem dados
+	Native  any       // estrutura do codec (*openspool.Spool, *bambu.Tag, ...)
+}
+
+// Storage onde os bytes de Encode ficam na tag.
+type Storage int
+
+const (
+	StorageNone     Storage = iota // formato só de leitura
+	StorageCreality                // blocos 4–6 cifrados, gravados pela transação CFS
+	StorageNDEF                    // mensagem NDEF na NTAG
+	StoragePages                   // bytes em claro a partir da página 4, sem NDEF
+)
+
+// Format formato de dados de bobina.
+type Format interface {
+	Name() string        // identificador: "cfs", "openspool", ...
+	Label() string       // nome para a UI: "Creality CFS"
+	Card() rfid.CardType // tipo de cartão que recebe o formato
+	Storage() Storage
+
+	// Detect reconhece o formato nos dados da tag. Dados de outro
+	// formato (ou keys recusadas) dão false sem erro; o erro fica para
+	// falhas de leitura.
+	Detect(t Tag) (bool, error)
+	// Decode lê a tag já reconhecida por Detect.
+	Decode(t Tag) (*Spool, error)
+	// Encode monta os bytes a gravar; ErrReadOnly se o formato não grava.
+	Encode(s Spool) ([]byte, error)
+}
+
+// Writable o formato aceita gravação.
+func Writable(f Format) bool {
+	return f.Storage() != StorageNone
+}
+
+// Registry formatos conhecidos, em ordem de detecção.
+type Registry struct {
+	formats []Format
+}
+
+// NewRegistry cria o registro com os formatos na ordem dada.
+func NewRegistry(formats ...Format) *Registry {
+	r := &Registry{}
+	for _, f := range formats {
+		r.Register(f)
+	}
+	return r
+}
+
+// Register acrescenta o formato ao fim da ordem de detecção; um nome já
+// registrado é substituído no mesmo lugar.
+func (r *Registry) Register(f Format) {
+	for i, g := range r.formats {
+		if g.Name() == f.Name() {
+			r.formats[i] = f
+			return
+		}
+	}
+	r.formats = append(r.formats, f)
+}
+
+// Formats formatos na ordem de detecção.
+func (r *Registry) Formats() []Format {
+	return append([]Format(nil), r.formats...)
+}
+
+// Get formato pelo nome.
+func (r *Registry) Get(name string) (Format, bool) {
+	for _, f := range r.formats {
+		if f.Name() == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Detect primeiro formato do tipo de cartão da tag que a reconhece;
+// ErrUnknownFormat se nenhum reconhece.
+func (r *Registry) Detect(t Tag) (Format, error) {
+	card := t.Card()
+	for _, f := range r.formats {
+		if !fits(f, card) {
+			continue
+		}
+		ok, err := f.Detect(t)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s (UID %s)", ErrUnknownFormat, cardName(card.Type), card.UID)
+}
+
+// Read reconhece e decodifica a tag.
+func (r *Registry) Read(t Tag) (Format, *Spool, error) {
+	f, err := r.Detect(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := f.Decode(t)
+	if err != nil {
+		return f, nil, fmt.Errorf("%s: %w", f.Label(), err)
+	}
+	return f, s, nil
+}
+
+// Check recusa com rfid.ErrUnsupportedCard o cartão que não recebe o
+// formato.
+func Check(f Format, card *rfid.CardInfo) error {
+	if fits(f, card) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s usa tags %s", rfid.ErrUnsupportedCard, f.Label(), cardName(f.Card()))
+}
+
+// fits o cartão é do tipo do formato. Leitores que não informam ATR nem
+// SAK dão CardUnknown, tratado como MIFARE Classic (como em
+// CardInfo.Supported); o 4K tem o setor 1 igual ao do 1K.
+func fits(f Format, card *rfid.CardInfo) bool {
+	switch card.Type {
+	case rfid.CardUnknown, rfid.CardClassic4K:
+		return f.Card() == rfid.CardClassic1K
+	}
+	return card.Type == f.Card()
+}
+
+func cardName(t rfid.CardType) string {
+	switch t {
+	case rfid.CardClassic1K:
+		return "MIFARE Classic"
+	case rfid.CardUltralight:
+		return "NTAG21x"
+	}
+	return string(t)
+}
+
+// Compare campos do modelo comum que divergem entre o gravado e o relido.
+func Compare(want, got Spool) []rfid.FieldMismatch {
+	var out []rfid.FieldMismatch
+	check := func(field, w, g string) {
+		if w != g {
+			out = append(out, rfid.FieldMismatch{Field: field, Expected: w, Actual: g})
+		}
+	}
+	check("material", want.Fields.Material, got.Fields.Material)
+	check("type", want.Type, got.Type)
+	check("brand", want.Brand, got.Brand)
+	check("color", want.Fields.Color, got.Fields.Color)
+	check("length", want.Fields.Length, got.Fields.Length)
+	check("weight", strconv.Itoa(want.Weight), strconv.Itoa(got.Weight))
+	check("min_temp", strconv.Itoa(want.MinTemp), strconv.Itoa(got.MinTemp))
+	check("max_temp", strconv.Itoa(want.MaxTemp), strconv.Itoa(got.MaxTemp))
+	return out
+}
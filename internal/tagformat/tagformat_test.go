@@ -0,0 +1,138 @@
+package tagformat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/robertocorreajr/cfs_spool/internal/ace"
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+func defaultRegistry() *Registry {
+	return NewRegistry(CFS{}, Bambu{}, TigerTag{}, ACE{}, OpenSpool{}, OpenTag3D{})
+}
+
+func identify(t *testing.T, tr rfid.Transport) (*rfid.Reader, *rfid.CardInfo) {
+	t.Helper()
+	r := rfid.NewReader(tr)
+	card, err := r.Identify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, card
+}
+
+func TestDetect(t *testing.T) {
+	classic, _ := rfid.NewEmulatedCard("A1B2C3D4")
+	blankNTAG, _ := rfid.NewEmulatedNTAG("04A1B2C3D4E5F6", rfid.NTAG215)
+	aceNTAG, _ := rfid.NewEmulatedNTAG("04A1B2C3D4E5F6", rfid.NTAG213)
+	b, err := ace.FromFields(creality.Fields{Material: "06002", Color: "077BB41", Length: "0330"}, 0, 0).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rfid.NewReader(aceNTAG).WriteUserData(b); err != nil {
+		t.Fatal(err)
+	}
+
+	reg := defaultRegistry()
+	testes := []struct {
+		nome     string
+		entrada  rfid.Transport
+		esperado string
+	}{
+		{"MIFARE Classic de fábrica", classic, NameCFS},
+		{"NTAG Anycubic", aceNTAG, NameACE},
+	}
+	for _, tt := range testes {
+		r, card := identify(t, tt.entrada)
+		f, err := reg.Detect(NewTag(r, card))
+		if err != nil {
+			t.Errorf("%s: %v", tt.nome, err)
+			continue
+		}
+		if f.Name() != tt.esperado {
+			t.Errorf("%s: formato %s, esperado %s", tt.nome, f.Name(), tt.esperado)
+		}
+	}
+
+	r, card := identify(t, blankNTAG)
+	if _, err := reg.Detect(NewTag(r, card)); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("NTAG virgem: %v", err)
+	}
+
+	r, card = identify(t, classic)
+	_, s, err := reg.Read(NewTag(r, card))
+	if err != nil || !s.Blank {
+		t.Errorf("CFS de fábrica: %+v, %v", s, err)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	ntag := &rfid.CardInfo{Type: rfid.CardUltralight, UID: "04A1B2C3D4E5F6"}
+	want := Spool{Fields: creality.Fields{Supplier: "0276", Material: "06002", Color: "077BB41", Length: "0330"}, MaxTemp: 240}
+	for _, f := range []Format{OpenSpool{}, OpenTag3D{}, ACE{}} {
+		b, err := f.Encode(want)
+		if err != nil {
+			t.Errorf("%s: Encode %v", f.Name(), err)
+			continue
+		}
+		tag := Stored(ntag, f.Storage(), b)
+		if ok, err := f.Detect(tag); !ok || err != nil {
+			t.Errorf("%s: Detect = %v, %v", f.Name(), ok, err)
+			continue
+		}
+		got, err := f.Decode(tag)
+		if err != nil {
+			t.Errorf("%s: Decode %v", f.Name(), err)
+			continue
+		}
+		if got.Fields.Color != want.Fields.Color || got.MaxTemp != want.MaxTemp {
+			t.Errorf("%s: cor %s, máx. %d", f.Name(), got.Fields.Color, got.MaxTemp)
+		}
+	}
+
+	// CFS: Encode devolve os blocos 4–6 cifrados que Decode lê
+	classic := &rfid.CardInfo{Type: rfid.CardClassic1K, UID: "A1B2C3D4"}
+	fields := creality.NewFields()
+	fields.Date, fields.Supplier, fields.Material = "AB124", "0276", "04001"
+	fields.Color, fields.Length, fields.Serial = "077BB41", "0330", "000001"
+	b, err := (CFS{}).Encode(Spool{Fields: fields})
+	if err != nil {
+		t.Fatalf("CFS: Encode %v", err)
+	}
+	if got, err := (CFS{}).Decode(Stored(classic, StorageCreality, b)); err != nil || got.Fields != fields {
+		t.Errorf("CFS: Decode = %+v, %v; esperado %+v", got, err, fields)
+	}
+	if _, err := (Bambu{}).Encode(want); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Bambu: %v", err)
+	}
+	if _, err := (TigerTag{}).Encode(want); !errors.Is(err, ErrNoCatalog) {
+		t.Errorf("TigerTag sem catálogo: %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := defaultRegistry()
+	reg.Register(ACE{})
+	if n := len(reg.Formats()); n != 6 {
+		t.Errorf("%d formatos depois de registrar o ACE de novo, esperado 6", n)
+	}
+	if f, ok := reg.Get(NameBambu); !ok || Writable(f) {
+		t.Errorf("Bambu: %v, gravável %v", ok, ok && Writable(f))
+	}
+
+	classic := &rfid.CardInfo{Type: rfid.CardClassic1K, UID: "A1B2C3D4"}
+	testes := []struct {
+		entrada  Format
+		esperado error
+	}{
+		{CFS{}, nil},
+		{ACE{}, rfid.ErrUnsupportedCard},
+	}
+	for _, tt := range testes {
+		if err := Check(tt.entrada, classic); !errors.Is(err, tt.esperado) {
+			t.Errorf("Check(%s) = %v, esperado %v", tt.entrada.Name(), err, tt.esperado)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package tigertag
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// ErrUnknownID valor sem ID correspondente no catálogo.
+var ErrUnknownID = errors.New("sem ID no catálogo TigerTag")
+
+// Entry item de uma lista de IDs do TigerTag.
+type Entry struct {
+	ID    int    `json:"id"`
+	Label string `json:"label"`
+}
+
+// Catalog listas de IDs da base TigerTag, no formato exportado pela API
+// do projeto ({"material": [{"id": ..., "label": "PLA"}], ...}). Os
+// métodos aceitam catálogo nil: sem ele os IDs ficam sem nome.
+type Catalog struct {
+	Material []Entry `json:"material"`
+	Brand    []Entry `json:"brand"`
+	Diameter []Entry `json:"diameter"` // label em mm: "1.75"
+	Unit     []Entry `json:"unit"`     // label: "g", "kg"
+}
+
+// LoadCatalog lê o catálogo em JSON.
+func LoadCatalog(r io.Reader) (*Catalog, error) {
+	var c Catalog
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, fmt.Errorf("catálogo TigerTag inválido: %v", err)
+	}
+	return &c, nil
+}
+
+//go:embed catalog.json
+var defaultCatalog []byte
+
+// DefaultCatalog catálogo embutido no app: recorte da base pública do
+// TigerTag com os materiais, marcas, diâmetros e unidades mais comuns.
+var DefaultCatalog = sync.OnceValue(func() *Catalog {
+	c, err := LoadCatalog(bytes.NewReader(defaultCatalog))
+	if err != nil {
+		panic(err)
+	}
+	return c
+})
+
+func label(list []Entry, id int) string {
+	for _, e := range list {
+		if e.ID == id {
+			return e.Label
+		}
+	}
+	return ""
+}
+
+func find(list []Entry, match func(string) bool) (int, bool) {
+	for _, e := range list {
+		if match(e.Label) {
+			return e.ID, true
+		}
+	}
+	return 0, false
+}
+
+// MaterialName nome do material ("" se o ID não está no catálogo).
+func (c *Catalog) MaterialName(id uint16) string {
+	if c == nil {
+		return ""
+	}
+	return label(c.Material, int(id))
+}
+
+// MaterialID ID do material com o nome dado; sem nome igual, o primeiro
+// com o mesmo material base ("PLA Basic" para "PLA").
+func (c *Catalog) MaterialID(name string) (uint16, error) {
+	if c != nil {
+		if id, ok := find(c.Material, func(l string) bool { return strings.EqualFold(l, name) }); ok {
+			return uint16(id), nil
+		}
+		typ := creality.BaseType(name)
+		if id, ok := find(c.Material, func(l string) bool { return creality.BaseType(l) == typ }); ok {
+			return uint16(id), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: material %q", ErrUnknownID, name)
+}
+
+// BrandName nome da marca ("" se o ID não está no catálogo).
+func (c *Catalog) BrandName(id uint16) string {
+	if c == nil {
+		return ""
+	}
+	return label(c.Brand, int(id))
+}
+
+// BrandID ID da marca, sem diferenciar maiúsculas.
+func (c *Catalog) BrandID(name string) (uint16, bool) {
+	if c == nil {
+		return 0, false
+	}
+	id, ok := find(c.Brand, func(l string) bool { return strings.EqualFold(l, name) })
+	return uint16(id), ok
+}
+
+// DiameterMM diâmetro em mm (0 se o ID não está no catálogo).
+func (c *Catalog) DiameterMM(id uint8) float64 {
+	if c == nil {
+		return 0
+	}
+	return millimeters(label(c.Diameter, int(id)))
+}
+
+// DiameterID ID do diâmetro em mm.
+func (c *Catalog) DiameterID(mm float64) (uint8, bool) {
+	if c == nil {
+		return 0, false
+	}
+	id, ok := find(c.Diameter, func(l string) bool { return millimeters(l) == mm })
+	return uint8(id), ok
+}
+
+// millimeters valor de um label de diâmetro ("1.75" ou "1.75mm").
+func millimeters(l string) float64 {
+	mm, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(l), "mm"), 64)
+	return mm
+}
+
+// UnitID ID da unidade ("g", "kg").
+func (c *Catalog) UnitID(unit string) (uint8, bool) {
+	if c == nil {
+		return 0, false
+	}
+	id, ok := find(c.Unit, func(l string) bool { return strings.EqualFold(l, unit) })
+	return uint8(id), ok
+}
+
+// Grams peso do filamento em gramas (0 se a unidade não é de massa ou
+// não está no catálogo).
+func (c *Catalog) Grams(t Tag) int {
+	if c == nil {
+		return 0
+	}
+	switch strings.ToLower(label(c.Unit, int(t.UnitID))) {
+	case "g":
+		return t.Measure
+	case "kg":
+		return t.Measure * 1000
+	}
+	return 0
+}
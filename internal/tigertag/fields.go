@@ -0,0 +1,56 @@
+package tigertag
+
+import (
+	"strings"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// Fields campos CFS equivalentes: material genérico do tipo, fornecedor,
+// cor e comprimento pelo peso. Sem o material no catálogo o código fica
+// vazio.
+func (c *Catalog) Fields(t Tag) creality.Fields {
+	f := creality.NewFields()
+	f.Supplier = "0000"
+	if strings.EqualFold(c.BrandName(t.BrandID), "Creality") {
+		f.Supplier = "0276"
+	}
+	f.Material = creality.GenericCode(c.MaterialName(t.MaterialID))
+	f.Color = "0" + t.ColorHex()
+	if g := c.Grams(t); g > 0 {
+		f.Length = creality.LengthFromGrams(g)
+	}
+	return f
+}
+
+// FromFields converte campos CFS para TigerTag. Temperaturas zeradas
+// usam a faixa padrão do material; marca, diâmetro e unidade fora do
+// catálogo ficam com ID 0. Só o material é obrigatório.
+func (c *Catalog) FromFields(f creality.Fields, minTemp, maxTemp int) (Tag, error) {
+	typ := creality.BaseType(f.GetMaterialName())
+	material, err := c.MaterialID(typ)
+	if err != nil {
+		return Tag{}, err
+	}
+	t := Tag{ID: TagMaker, MaterialID: material}
+	t.BrandID, _ = c.BrandID(f.Brand())
+	t.DiameterID, _ = c.DiameterID(creality.Diameter)
+	if unit, ok := c.UnitID("g"); ok {
+		t.UnitID, t.Measure = unit, f.Grams()
+	}
+	if len(f.Color) == 7 {
+		if t.Color, err = parseHex(f.Color[1:]); err != nil {
+			return Tag{}, err
+		}
+	}
+	t.MinTemp, t.MaxTemp = creality.DefaultTemps(typ)
+	if minTemp > 0 {
+		t.MinTemp = minTemp
+	}
+	if maxTemp > 0 {
+		t.MaxTemp = maxTemp
+	}
+	bed := creality.DefaultBedTemp(typ)
+	t.BedMinTemp, t.BedMaxTemp = bed, bed
+	return t, nil
+}
@@ -0,0 +1,202 @@
+package tigertag
+
+// Formato TigerTag: bloco binário gravado direto nas páginas da NTAG213 a
+// partir da página 4, sem NDEF. Material, marca, diâmetro e unidade são
+// IDs da base pública do TigerTag (ver Catalog).
+//
+// Layout (inteiros big-endian):
+//
+//   0x00  4  ID do formato            (TagMaker; TagInit = tag virgem)
+//   0x04  4  ID do produto
+//   0x08  2  ID do material
+//   0x0A  1  ID do aspecto 1          (Silk, Matte...)
+//   0x0B  1  ID do aspecto 2
+//   0x0C  1  ID do tipo               (filamento, resina)
+//   0x0D  1  ID do diâmetro
+//   0x0E  2  ID da marca
+//   0x10  4  cor RGBA
+//   0x14  3  quantidade               (na unidade do ID seguinte)
+//   0x17  1  ID da unidade
+//   0x18  2  temperatura mínima do bico (°C)
+//   0x1A  2  temperatura máxima do bico (°C)
+//   0x1C  1  temperatura de secagem (°C)
+//   0x1D  1  tempo de secagem (h)
+//   0x1E  1  temperatura mínima da mesa (°C)
+//   0x1F  1  temperatura máxima da mesa (°C)
+//   0x20  4  data de fabricação (segundos desde 2000-01-01 UTC)
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	TagMaker uint32 = 0x5BF59264 // TigerTag Maker v1.0
+	TagInit  uint32 = 0xBC0FCB97 // tag TigerTag ainda sem dados
+
+	Size = 0x24 // bytes do bloco (páginas 4 a 12)
+)
+
+var (
+	ErrNotTigerTag = errors.New("tag sem dados TigerTag")
+	ErrShort       = errors.New("dados TigerTag menores que o bloco")
+	ErrTooLong     = errors.New("valor não cabe no layout TigerTag")
+)
+
+// epoch início da contagem da data de fabricação.
+var epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	offID        = 0x00
+	offProduct   = 0x04
+	offMaterial  = 0x08
+	offAspect1   = 0x0A
+	offAspect2   = 0x0B
+	offType      = 0x0C
+	offDiameter  = 0x0D
+	offBrand     = 0x0E
+	offColor     = 0x10
+	offMeasure   = 0x14
+	offUnit      = 0x17
+	offMinTemp   = 0x18
+	offMaxTemp   = 0x1A
+	offDryTemp   = 0x1C
+	offDryHours  = 0x1D
+	offBedMin    = 0x1E
+	offBedMax    = 0x1F
+	offTimestamp = 0x20
+)
+
+// Tag conteúdo do bloco TigerTag.
+type Tag struct {
+	ID         uint32
+	ProductID  uint32
+	MaterialID uint16
+	Aspect1    uint8
+	Aspect2    uint8
+	TypeID     uint8
+	DiameterID uint8
+	BrandID    uint16
+	Color      [4]byte // RGBA
+	Measure    int     // quantidade na unidade UnitID
+	UnitID     uint8
+	MinTemp    int // °C do bico
+	MaxTemp    int
+	DryTemp    int // °C
+	DryHours   int
+	BedMinTemp int // °C
+	BedMaxTemp int
+	Made       time.Time // zero se não informada
+}
+
+// ColorHex cor sem o alfa, em 6 hex maiúsculos.
+func (t Tag) ColorHex() string {
+	return fmt.Sprintf("%02X%02X%02X", t.Color[0], t.Color[1], t.Color[2])
+}
+
+// Marshal monta o bloco binário; ID zero vira TagMaker.
+func (t Tag) Marshal() ([]byte, error) {
+	for _, n := range []struct {
+		name     string
+		value    int
+		min, max int
+	}{
+		{"quantidade", t.Measure, 0, 0xFFFFFF},
+		{"temperatura mínima do bico", t.MinTemp, 0, 0xFFFF},
+		{"temperatura máxima do bico", t.MaxTemp, 0, 0xFFFF},
+		{"temperatura de secagem", t.DryTemp, 0, 0xFF},
+		{"tempo de secagem", t.DryHours, 0, 0xFF},
+		{"temperatura mínima da mesa", t.BedMinTemp, 0, 0xFF},
+		{"temperatura máxima da mesa", t.BedMaxTemp, 0, 0xFF},
+	} {
+		if n.value < n.min || n.value > n.max {
+			return nil, fmt.Errorf("%w: %s %d", ErrTooLong, n.name, n.value)
+		}
+	}
+	var made uint32
+	if !t.Made.IsZero() {
+		secs := t.Made.Sub(epoch) / time.Second
+		if secs < 0 || secs > 0xFFFFFFFF {
+			return nil, fmt.Errorf("%w: data de fabricação %s", ErrTooLong, t.Made.Format("2006-01-02"))
+		}
+		made = uint32(secs)
+	}
+	id := t.ID
+	if id == 0 {
+		id = TagMaker
+	}
+
+	b := make([]byte, Size)
+	binary.BigEndian.PutUint32(b[offID:], id)
+	binary.BigEndian.PutUint32(b[offProduct:], t.ProductID)
+	binary.BigEndian.PutUint16(b[offMaterial:], t.MaterialID)
+	b[offAspect1], b[offAspect2] = t.Aspect1, t.Aspect2
+	b[offType], b[offDiameter] = t.TypeID, t.DiameterID
+	binary.BigEndian.PutUint16(b[offBrand:], t.BrandID)
+	copy(b[offColor:], t.Color[:])
+	b[offMeasure], b[offMeasure+1], b[offMeasure+2] = byte(t.Measure>>16), byte(t.Measure>>8), byte(t.Measure)
+	b[offUnit] = t.UnitID
+	binary.BigEndian.PutUint16(b[offMinTemp:], uint16(t.MinTemp))
+	binary.BigEndian.PutUint16(b[offMaxTemp:], uint16(t.MaxTemp))
+	b[offDryTemp], b[offDryHours] = byte(t.DryTemp), byte(t.DryHours)
+	b[offBedMin], b[offBedMax] = byte(t.BedMinTemp), byte(t.BedMaxTemp)
+	binary.BigEndian.PutUint32(b[offTimestamp:], made)
+	return b, nil
+}
+
+// Unmarshal lê o bloco; ErrNotTigerTag se o ID do formato não é TagMaker
+// (tags TagInit também, por não terem dados).
+func Unmarshal(b []byte) (*Tag, error) {
+	if len(b) < Size {
+		return nil, fmt.Errorf("%w: %d bytes", ErrShort, len(b))
+	}
+	if id := binary.BigEndian.Uint32(b[offID:]); id != TagMaker {
+		return nil, fmt.Errorf("%w: ID %08X", ErrNotTigerTag, id)
+	}
+	t := &Tag{
+		ID:         TagMaker,
+		ProductID:  binary.BigEndian.Uint32(b[offProduct:]),
+		MaterialID: binary.BigEndian.Uint16(b[offMaterial:]),
+		Aspect1:    b[offAspect1],
+		Aspect2:    b[offAspect2],
+		TypeID:     b[offType],
+		DiameterID: b[offDiameter],
+		BrandID:    binary.BigEndian.Uint16(b[offBrand:]),
+		Measure:    int(b[offMeasure])<<16 | int(b[offMeasure+1])<<8 | int(b[offMeasure+2]),
+		UnitID:     b[offUnit],
+		MinTemp:    int(binary.BigEndian.Uint16(b[offMinTemp:])),
+		MaxTemp:    int(binary.BigEndian.Uint16(b[offMaxTemp:])),
+		DryTemp:    int(b[offDryTemp]),
+		DryHours:   int(b[offDryHours]),
+		BedMinTemp: int(b[offBedMin]),
+		BedMaxTemp: int(b[offBedMax]),
+	}
+	copy(t.Color[:], b[offColor:])
+	if secs := binary.BigEndian.Uint32(b[offTimestamp:]); secs != 0 {
+		t.Made = epoch.Add(time.Duration(secs) * time.Second)
+	}
+	return t, nil
+}
+
+// IsTigerTag o bloco começa com um ID de formato TigerTag (com ou sem dados).
+func IsTigerTag(b []byte) bool {
+	if len(b) < 4 {
+		return false
+	}
+	id := binary.BigEndian.Uint32(b)
+	return id == TagMaker || id == TagInit
+}
+
+// parseHex cor de 6 hex (sem #), opaca.
+func parseHex(s string) ([4]byte, error) {
+	var c [4]byte
+	s = strings.TrimPrefix(s, "#")
+	if _, err := fmt.Sscanf(s, "%02x%02x%02x", &c[0], &c[1], &c[2]); err != nil || len(s) != 6 {
+		return c, fmt.Errorf("cor inválida %q", s)
+	}
+	c[3] = 0xFF
+	return c, nil
+}
@@ -0,0 +1,168 @@
+package tigertag
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/creality"
+)
+
+// testCatalog catálogo reduzido com IDs de teste.
+const testCatalog = `{
+	"material": [{"id": 100, "label": "PLA"}, {"id": 101, "label": "PLA Silk"}, {"id": 200, "label": "PETG"}],
+	"brand": [{"id": 10, "label": "Creality"}, {"id": 11, "label": "eSUN"}],
+	"diameter": [{"id": 1, "label": "1.75"}, {"id": 2, "label": "2.85mm"}],
+	"unit": [{"id": 5, "label": "g"}, {"id": 6, "label": "kg"}]
+}`
+
+func loadTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := LoadCatalog(strings.NewReader(testCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+var testTag = Tag{
+	ID: TagMaker, ProductID: 0x01020304, MaterialID: 200, Aspect1: 3, TypeID: 1,
+	DiameterID: 1, BrandID: 10, Color: [4]byte{0x77, 0xBB, 0x41, 0xFF},
+	Measure: 1000, UnitID: 5, MinTemp: 220, MaxTemp: 250, DryTemp: 65, DryHours: 6,
+	BedMinTemp: 70, BedMaxTemp: 80, Made: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+}
+
+func TestMarshalLayout(t *testing.T) {
+	b, err := testTag.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != Size {
+		t.Fatalf("%d bytes, esperado %d", len(b), Size)
+	}
+	testes := []struct {
+		campo    string
+		off, n   int
+		esperado string
+	}{
+		{"ID", 0x00, 4, "5BF59264"},
+		{"produto", 0x04, 4, "01020304"},
+		{"material", 0x08, 2, "00C8"},
+		{"marca", 0x0E, 2, "000A"},
+		{"cor", 0x10, 4, "77BB41FF"},
+		{"quantidade", 0x14, 4, "0003E805"},
+		{"bico", 0x18, 4, "00DC00FA"},
+		{"secagem e mesa", 0x1C, 4, "41064650"},
+		{"fabricação", 0x20, 4, "2D810600"},
+	}
+	for _, tt := range testes {
+		if got := hex.EncodeToString(b[tt.off : tt.off+tt.n]); !strings.EqualFold(got, tt.esperado) {
+			t.Errorf("%s = %s, esperado %s", tt.campo, got, tt.esperado)
+		}
+	}
+
+	got, err := Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != testTag {
+		t.Errorf("Unmarshal = %+v, esperado %+v", *got, testTag)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	blank := make([]byte, Size)
+	copy(blank, []byte{0xBC, 0x0F, 0xCB, 0x97})
+	testes := []struct {
+		nome     string
+		entrada  []byte
+		esperado error
+	}{
+		{"curto", make([]byte, 20), ErrShort},
+		{"TigerTag virgem", blank, ErrNotTigerTag},
+		{"NDEF", append([]byte{0x03, 0x00, 0xFE}, make([]byte, Size)...), ErrNotTigerTag},
+	}
+	for _, tt := range testes {
+		if _, err := Unmarshal(tt.entrada); !errors.Is(err, tt.esperado) {
+			t.Errorf("%s: %v, esperado %v", tt.nome, err, tt.esperado)
+		}
+	}
+	if !IsTigerTag(blank) {
+		t.Error("TigerTag virgem deveria ser reconhecida")
+	}
+	if _, err := (Tag{MaxTemp: 70000}).Marshal(); !errors.Is(err, ErrTooLong) {
+		t.Errorf("temperatura fora da faixa: %v", err)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	c := loadTestCatalog(t)
+	if got := c.MaterialName(101); got != "PLA Silk" {
+		t.Errorf("MaterialName(101) = %q", got)
+	}
+	if got := c.DiameterMM(2); got != 2.85 {
+		t.Errorf("DiameterMM(2) = %v", got)
+	}
+	if got := c.Grams(Tag{Measure: 2, UnitID: 6}); got != 2000 {
+		t.Errorf("Grams 2kg = %d", got)
+	}
+	testes := []struct {
+		entrada  string
+		esperado uint16
+	}{
+		{"PLA Silk", 101},
+		{"pla", 100},
+		{"Hyper PETG", 200},
+	}
+	for _, tt := range testes {
+		if got, err := c.MaterialID(tt.entrada); err != nil || got != tt.esperado {
+			t.Errorf("MaterialID(%q) = %d, %v; esperado %d", tt.entrada, got, err, tt.esperado)
+		}
+	}
+	if _, err := c.MaterialID("ABS"); !errors.Is(err, ErrUnknownID) {
+		t.Errorf("material fora do catálogo: %v", err)
+	}
+	var none *Catalog
+	if _, err := none.MaterialID("PLA"); !errors.Is(err, ErrUnknownID) || none.MaterialName(100) != "" {
+		t.Errorf("catálogo nil: %v", err)
+	}
+}
+
+func TestDefaultCatalog(t *testing.T) {
+	c := DefaultCatalog()
+	for _, name := range []string{"PLA", "PETG", "ABS", "TPU"} {
+		if _, err := c.MaterialID(name); err != nil {
+			t.Errorf("MaterialID(%q): %v", name, err)
+		}
+	}
+	if _, ok := c.DiameterID(1.75); !ok {
+		t.Error("diâmetro 1.75 fora do catálogo embutido")
+	}
+	if _, ok := c.UnitID("g"); !ok {
+		t.Error("unidade g fora do catálogo embutido")
+	}
+}
+
+func TestFields(t *testing.T) {
+	c := loadTestCatalog(t)
+	want := creality.Fields{Batch: "A2", Reserve: "0000", Supplier: "0276", Material: "00003", Color: "077BB41", Length: "014A"}
+	if got := c.Fields(testTag); got != want {
+		t.Errorf("Fields = %+v, esperado %+v", got, want)
+	}
+
+	f := creality.Fields{Supplier: "0276", Material: "E1001", Color: "0FFAABB", Length: "00A5"}
+	got, err := c.FromFields(f, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTag := Tag{ID: TagMaker, MaterialID: 100, BrandID: 11, DiameterID: 1, UnitID: 5, Measure: 500,
+		Color: [4]byte{0xFF, 0xAA, 0xBB, 0xFF}, MinTemp: 190, MaxTemp: 220, BedMinTemp: 60, BedMaxTemp: 60}
+	if got != wantTag {
+		t.Errorf("FromFields = %+v, esperado %+v", got, wantTag)
+	}
+	if _, err := c.FromFields(creality.Fields{Material: "03001"}, 0, 0); !errors.Is(err, ErrUnknownID) {
+		t.Errorf("ABS sem ID: %v", err)
+	}
+}
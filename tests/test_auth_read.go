@@ -3,13 +3,21 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
 
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
 	"github.com/robertocorreajr/cfs_spool/internal/rfid"
 )
 
 func main() {
+	trace := flag.Bool("trace", false, "registra as APDUs e mostra as keys em claro")
+	flag.Parse()
+	rfid.SetLogger(logging.New(os.Stderr, logging.LevelTrace))
+	rfid.SetTrace(*trace)
+
 	fmt.Println("=== Teste de Leitura com Autenticação ===")
 	
 	// Conectar ao leitor
@@ -41,7 +49,7 @@ func main() {
 
 	// Obter chave derivada do UID
 	derivedKey := rdr.DeriveKeyFromUID(uid)
-	fmt.Printf("Chave derivada do UID: %s\n", derivedKey)
+	fmt.Printf("Chave derivada do UID: %s\n", rfid.MaskKey(derivedKey))
 	
 	// Adicionar chave derivada às chaves de teste
 	testKeys := append([]string{derivedKey}, defaultKeys...)
@@ -52,7 +60,7 @@ func main() {
 		
 		success := false
 		for keyIndex, key := range testKeys {
-			fmt.Printf("Tentando chave %d (%s)...\n", keyIndex, key)
+			fmt.Printf("Tentando chave %d (%s)...\n", keyIndex, rfid.MaskKey(key))
 			
 			// Tentar com Key Type A
 			data, err := rdr.TryReadBlock(block, rfid.KeyTypeA, key)
@@ -83,7 +91,7 @@ func main() {
 	fmt.Println("Nota: O trailer pode não ser legível por questões de segurança")
 	
 	for keyIndex, key := range testKeys {
-		fmt.Printf("Tentando chave %d (%s)...\n", keyIndex, key)
+		fmt.Printf("Tentando chave %d (%s)...\n", keyIndex, rfid.MaskKey(key))
 		
 		data, err := rdr.TryReadBlock(7, rfid.KeyTypeA, key)
 		if err == nil {
@@ -3,14 +3,22 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
 
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
 	"github.com/robertocorreajr/cfs_spool/internal/rfid"
 	"github.com/robertocorreajr/cfs_spool/internal/creality"
 )
 
 func main() {
+	trace := flag.Bool("trace", false, "registra as APDUs e mostra as keys em claro")
+	flag.Parse()
+	rfid.SetLogger(logging.New(os.Stderr, logging.LevelTrace))
+	rfid.SetTrace(*trace)
+
 	fmt.Println("=== Teste de Leitura e Decodificação CFS ===")
 	
 	// Conectar ao leitor
@@ -29,7 +37,7 @@ func main() {
 
 	// Obter chave derivada
 	derivedKey := rdr.DeriveKeyFromUID(uid)
-	fmt.Printf("Chave derivada: %s\n", derivedKey)
+	fmt.Printf("Chave derivada: %s\n", rfid.MaskKey(derivedKey))
 
 	// Ler blocos 4, 5, 6
 	fmt.Println("\n=== Lendo Dados Brutos ===")
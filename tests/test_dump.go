@@ -0,0 +1,83 @@
+//go:build ignore
+
+package main
+
+// Dump completo da tag no leitor em .bin, .mct e JSON (Proxmark3).
+//
+//   go run tests/test_dump.go [-trace] [key extra 12 hex ...]
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/robertocorreajr/cfs_spool/internal/access"
+	"github.com/robertocorreajr/cfs_spool/internal/dump"
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+func main() {
+	trace := flag.Bool("trace", false, "registra as APDUs e mostra as keys em claro")
+	flag.Parse()
+	rfid.SetLogger(logging.New(os.Stderr, logging.LevelTrace))
+	rfid.SetTrace(*trace)
+
+	fmt.Println("=== Dump Completo (16 setores) ===")
+
+	rdr, err := rfid.Open()
+	if err != nil {
+		log.Fatalf("Erro ao conectar: %v", err)
+	}
+	defer rdr.Close()
+
+	uid, err := rdr.UID()
+	if err != nil {
+		log.Fatalf("Erro ao ler UID: %v", err)
+	}
+	fmt.Printf("UID: %s\n", uid)
+
+	keys, err := rfid.DumpKeys(uid, flag.Args()...)
+	if err != nil {
+		log.Fatalf("Dicionário inválido: %v", err)
+	}
+	masked := make([]string, len(keys))
+	for i, k := range keys {
+		masked[i] = rfid.MaskKey(k)
+	}
+	fmt.Printf("Dicionário: %v\n", masked)
+
+	d, err := rdr.Dump(keys)
+	if err != nil {
+		log.Fatalf("Erro no dump: %v", err)
+	}
+
+	for s := 0; s < dump.Sectors; s++ {
+		fmt.Printf("Setor %2d  KeyA=%-12s KeyB=%-12s\n", s, rfid.MaskKey(d.Keys[s].KeyA), rfid.MaskKey(d.Keys[s].KeyB))
+		if t := dump.TrailerBlock(s); d.Read[t] {
+			bits, err := access.Decode(d.Blocks[t][6:10])
+			if err != nil {
+				fmt.Printf("          ⚠ %v\n", err)
+				continue
+			}
+			for _, line := range bits.Describe() {
+				fmt.Printf("          %s\n", line)
+			}
+		}
+	}
+
+	base := fmt.Sprintf("%s_%s", d.UID, time.Now().Format("20060102-150405"))
+	for _, format := range []string{dump.FormatBin, dump.FormatMCT, dump.FormatJSON} {
+		f, err := os.Create(base + "." + format)
+		if err != nil {
+			log.Fatalf("Erro ao criar arquivo: %v", err)
+		}
+		if err := d.Write(f, format); err != nil {
+			log.Fatalf("Erro ao gravar %s: %v", format, err)
+		}
+		f.Close()
+		fmt.Printf("✓ %s.%s\n", base, format)
+	}
+}
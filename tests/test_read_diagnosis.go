@@ -3,13 +3,21 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
 	"github.com/robertocorreajr/cfs_spool/internal/rfid"
 	"github.com/robertocorreajr/cfs_spool/internal/creality"
 )
 
 func main() {
+	trace := flag.Bool("trace", false, "registra as APDUs e mostra as keys em claro")
+	flag.Parse()
+	rfid.SetLogger(logging.New(os.Stderr, logging.LevelTrace))
+	rfid.SetTrace(*trace)
+
 	fmt.Println("=== TESTE DE LEITURA PARA DIAGNÓSTICO ===")
 	
 	// Conectar ao leitor
@@ -31,7 +39,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Erro ao derivar chave: %v", err)
 	}
-	fmt.Printf("🔑 Chave derivada: %s\n", derivedKey)
+	fmt.Printf("🔑 Chave derivada: %s\n", rfid.MaskKey(derivedKey))
 
 	// Lista de chaves para testar
 	testKeys := []string{
@@ -48,7 +56,7 @@ func main() {
 		
 		found := false
 		for _, key := range testKeys {
-			fmt.Printf("   Tentando chave %s... ", key)
+			fmt.Printf("   Tentando chave %s... ", rfid.MaskKey(key))
 			
 			data, err := rdr.TryReadBlock(block, rfid.KeyTypeA, key)
 			if err != nil {
@@ -74,7 +82,7 @@ func main() {
 
 	// Tentar com each key sequencialmente para todos os blocos
 	for _, key := range testKeys {
-		fmt.Printf("Tentando ler todos os blocos com chave %s...\n", key)
+		fmt.Printf("Tentando ler todos os blocos com chave %s...\n", rfid.MaskKey(key))
 		blocks = nil
 		allBlocksRead := true
 		
@@ -89,7 +97,7 @@ func main() {
 		}
 		
 		if allBlocksRead {
-			fmt.Printf("✅ Todos os blocos lidos com chave %s!\n", key)
+			fmt.Printf("✅ Todos os blocos lidos com chave %s!\n", rfid.MaskKey(key))
 			readSuccess = true
 			break
 		}
@@ -0,0 +1,80 @@
+//go:build ignore
+
+package main
+
+// Restaura na tag do leitor um dump salvo (.bin, .mct ou .json).
+//
+//   go run tests/test_restore.go [-trace] <dump> [key extra 12 hex ...]
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/robertocorreajr/cfs_spool/internal/dump"
+	"github.com/robertocorreajr/cfs_spool/internal/logging"
+	"github.com/robertocorreajr/cfs_spool/internal/rfid"
+)
+
+// maskTrailer mascara as keys A e B de um trailer em hex, como no log
+func maskTrailer(b int, h string) string {
+	if !dump.IsTrailer(b) || len(h) != 32 {
+		return h
+	}
+	return rfid.MaskKey(h[:12]) + h[12:20] + rfid.MaskKey(h[20:])
+}
+
+func main() {
+	trace := flag.Bool("trace", false, "registra as APDUs e mostra as keys em claro")
+	flag.Parse()
+	rfid.SetLogger(logging.New(os.Stderr, logging.LevelTrace))
+	rfid.SetTrace(*trace)
+
+	if flag.NArg() < 1 {
+		log.Fatal("uso: go run tests/test_restore.go [-trace] <dump> [keys...]")
+	}
+	fmt.Println("=== Restauração de Dump ===")
+
+	d, err := dump.Load(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Erro ao carregar dump: %v", err)
+	}
+	fmt.Printf("Dump: UID %s\n", d.UID)
+
+	rdr, err := rfid.Open()
+	if err != nil {
+		log.Fatalf("Erro ao conectar: %v", err)
+	}
+	defer rdr.Close()
+
+	uid, err := rdr.UID()
+	if err != nil {
+		log.Fatalf("Erro ao ler UID: %v", err)
+	}
+	fmt.Printf("Tag:  UID %s\n", uid)
+
+	keys, err := rfid.DumpKeys(uid, flag.Args()[1:]...)
+	if err != nil {
+		log.Fatalf("Dicionário inválido: %v", err)
+	}
+
+	rep, err := rdr.Restore(d, keys)
+	if err != nil {
+		log.Fatalf("Erro na restauração: %v", err)
+	}
+
+	fmt.Printf("Blocos gravados: %v\n", rep.Written)
+	for _, w := range rep.Warnings {
+		fmt.Printf("⚠ %s\n", w)
+	}
+	for _, s := range rep.Skipped {
+		fmt.Printf("- bloco %2d ignorado: %s\n", s.Block, s.Reason)
+	}
+	for _, m := range rep.Mismatches {
+		fmt.Printf("✗ bloco %2d: %s (esperado %s, lido %s)\n", m.Block, m.Reason, maskTrailer(m.Block, m.Expected), maskTrailer(m.Block, m.Actual))
+	}
+	if rep.OK() {
+		fmt.Println("✓ Releitura confere")
+	}
+}